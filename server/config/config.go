@@ -26,6 +26,35 @@ type AppConfig struct {
 	JWTSecret        string
 	JWTExpireMinutes int
 
+	// Positions
+	CostBasisMethod string // 默认成本计算方法：fifo / lifo / average
+
+	// Stocks
+	StockDeletePolicy string // 删除仍被引用的股票时的处理方式：block / cascade / archive
+
+	// Plans
+	MinRewardRiskRatio float64 // 计划最低盈亏比，0 表示不检查
+	RewardRiskPolicy   string  // 盈亏比低于最低值时的处理方式：reject / flag
+
+	// Quotes
+	QuoteProvider string        // 行情数据源：mock
+	QuoteCacheTTL time.Duration // 行情缓存时间
+
+	// Alerts
+	AlertCheckInterval time.Duration // 计划价格监控的检查间隔，0 表示不启动
+
+	// Notifications
+	NotifyDispatchInterval time.Duration // 通知发送队列的处理间隔，0 表示不启动
+	NotifyMaxAttempts      int           // 单条通知的最大发送次数，超过后标记为失败
+	ReviewReminderTime     string        // 每日复盘提醒时间（HH:MM），为空表示不提醒
+
+	// SMTP
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
 	// Uploads
 	UploadDir          string
 	MaxUploadSizeBytes int64
@@ -67,6 +96,28 @@ func Load() *AppConfig {
 		JWTSecret:        getEnv("JWT_SECRET", "change_me_in_env"),
 		JWTExpireMinutes: getEnvInt("JWT_EXPIRE_MINUTES", 60*24), // 1 day default
 
+		CostBasisMethod: getEnv("COST_BASIS_METHOD", "average"),
+
+		StockDeletePolicy: getEnv("STOCK_DELETE_POLICY", "block"),
+
+		MinRewardRiskRatio: getEnvFloat("MIN_REWARD_RISK_RATIO", 1.5),
+		RewardRiskPolicy:   getEnv("REWARD_RISK_POLICY", "flag"),
+
+		QuoteProvider: getEnv("QUOTE_PROVIDER", "mock"),
+		QuoteCacheTTL: getEnvDuration("QUOTE_CACHE_TTL", 5*time.Second),
+
+		AlertCheckInterval: getEnvDuration("ALERT_CHECK_INTERVAL", time.Minute),
+
+		NotifyDispatchInterval: getEnvDuration("NOTIFY_DISPATCH_INTERVAL", 10*time.Second),
+		NotifyMaxAttempts:      getEnvInt("NOTIFY_MAX_ATTEMPTS", 5),
+		ReviewReminderTime:     getEnv("REVIEW_REMINDER_TIME", "20:00"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+
 		UploadDir:          getEnv("UPLOAD_DIR", "uploads"),
 		MaxUploadSizeBytes: getEnvInt64("MAX_UPLOAD_SIZE_BYTES", 5*1024*1024*1024), // 5GB
 		ChunkSizeBytes:     getEnvInt("CHUNK_SIZE_BYTES", 2*1024*1024),             // 2MB
@@ -107,6 +158,16 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+		log.Printf("WARN: invalid float for %s=%q, using default %v", key, v, defaultValue)
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if v := os.Getenv(key); v != "" {
 		switch v {
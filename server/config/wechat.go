@@ -2,14 +2,22 @@ package config
 
 // WechatConfig 微信小程序配置
 type WechatConfig struct {
-	AppID     string `json:"app_id"`     // 小程序AppID
-	AppSecret string `json:"app_secret"` // 小程序AppSecret
+	AppID               string `json:"app_id"`                // 小程序AppID
+	AppSecret           string `json:"app_secret"`            // 小程序AppSecret
+	APIBaseURL          string `json:"api_base_url"`          // 微信接口地址，可替换为本地模拟服务
+	SubscribeTemplateID string `json:"subscribe_template_id"` // 订阅消息模板ID
+	SubscribePage       string `json:"subscribe_page"`        // 订阅消息点击后跳转的小程序页面
+	SubscribeFields     string `json:"subscribe_fields"`      // 消息字段与模板字段的映射，如 title:thing1,content:thing2,time:time3
 }
 
 // GetWechatConfig 获取微信配置
 func GetWechatConfig() *WechatConfig {
 	return &WechatConfig{
-		AppID:     getEnv("WECHAT_APP_ID", ""),
-		AppSecret: getEnv("WECHAT_APP_SECRET", ""),
+		AppID:               getEnv("WECHAT_APP_ID", ""),
+		AppSecret:           getEnv("WECHAT_APP_SECRET", ""),
+		APIBaseURL:          getEnv("WECHAT_API_BASE", "https://api.weixin.qq.com"),
+		SubscribeTemplateID: getEnv("WECHAT_SUBSCRIBE_TEMPLATE_ID", ""),
+		SubscribePage:       getEnv("WECHAT_SUBSCRIBE_PAGE", ""),
+		SubscribeFields:     getEnv("WECHAT_SUBSCRIBE_FIELDS", "title:thing1,content:thing2,time:time3"),
 	}
 }
@@ -63,8 +63,11 @@ class App {
 
     async loadUserInfo() {
         try {
+            if (!localStorage.getItem('token')) {
+                return;
+            }
             const response = await this.apiCall('GET', '/user/profile');
-            if (response.success) {
+            if (response.code === 0) {
                 this.updateUserInfo(response.data);
             }
         } catch (error) {
@@ -94,7 +97,8 @@ class App {
 
         try {
             const response = await this.apiCall('POST', '/user/login', loginData);
-            if (response.success) {
+            if (response.code === 0) {
+                localStorage.setItem('token', response.data.token);
                 this.showAlert('登录成功！', 'success');
                 this.loadUserInfo();
                 setTimeout(() => {
@@ -120,7 +124,7 @@ class App {
 
         try {
             const response = await this.apiCall('POST', '/user/register', registerData);
-            if (response.success) {
+            if (response.code === 0) {
                 this.showAlert('注册成功！', 'success');
                 setTimeout(() => {
                     window.location.href = '/login';
@@ -182,6 +186,11 @@ class App {
             }
         };
 
+        const token = localStorage.getItem('token');
+        if (token) {
+            options.headers['Authorization'] = 'Bearer ' + token;
+        }
+
         if (data) {
             options.body = JSON.stringify(data);
         }
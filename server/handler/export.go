@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Export 以附件形式流式导出表格，格式由 format 查询参数指定（csv 或 xlsx，默认 csv）
+// 开始写出后无法再返回错误响应，中途出错只记录日志并中断输出
+func Export(c *gin.Context, name, sheet string, write func(w utils.RowWriter) error) {
+	format, err := utils.ParseExportFormat(c.Query("format"))
+	if err != nil {
+		Error(c, CodeInvalid, err.Error())
+		return
+	}
+
+	// 导出大表可能超过服务器的写超时，流式输出前取消本次请求的写超时，避免下载被中途截断
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		utils.LogWarning("取消导出写超时失败: %v", err)
+	}
+
+	fileName := fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102"), format)
+	c.Header("Content-Type", utils.ExportContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+
+	w, err := utils.NewRowWriter(c.Writer, format, sheet)
+	if err == nil {
+		err = write(w)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		utils.LogError("导出 %s 失败: %v", fileName, err)
+		c.Abort()
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"server/config"
+	"server/middleware"
 	"strconv"
 	"sync"
 	"time"
@@ -17,9 +18,8 @@ import (
 // UploadHandler 文件上传处理
 type UploadHandler struct {
 	*BaseHandler
-	uploadDir string
-	uploads   map[string]*UploadSession
-	mu        sync.RWMutex
+	uploads map[string]*UploadSession
+	mu      sync.RWMutex
 }
 
 // UploadSession 上传会话
@@ -36,14 +36,39 @@ type UploadSession struct {
 
 // NewUploadHandler 创建上传处理器实例
 func NewUploadHandler() *UploadHandler {
-	cfg := config.Load()
 	return &UploadHandler{
 		BaseHandler: NewBaseHandler(),
-		uploadDir:   cfg.UploadDir,
 		uploads:     make(map[string]*UploadSession),
 	}
 }
 
+// UserUploadDir 用户的上传目录，每个用户上传的文件单独存放
+func UserUploadDir(userID string) string {
+	return filepath.Join(config.Load().UploadDir, filepath.Base(userID))
+}
+
+// ResolveUpload 解析用户已上传文件的路径，只允许访问该用户上传目录下的文件
+func ResolveUpload(userID, fileName string) (string, error) {
+	if userID == "" {
+		return "", fmt.Errorf("缺少用户信息")
+	}
+	name := filepath.Base(fileName)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return "", fmt.Errorf("文件名无效")
+	}
+	return filepath.Join(UserUploadDir(userID), name), nil
+}
+
+// sessionKey 上传会话按用户区分，不同用户使用相同的 fileId 互不影响
+func sessionKey(userID, fileID string) string {
+	return userID + "/" + fileID
+}
+
+// chunkDir 分片临时目录，位于用户上传目录下
+func chunkDir(userID, fileID string) string {
+	return filepath.Join(UserUploadDir(userID), ".chunks", filepath.Base(fileID))
+}
+
 // InitUpload 初始化上传
 func (h *UploadHandler) InitUpload(c *gin.Context) {
 	var req struct {
@@ -71,6 +96,8 @@ func (h *UploadHandler) InitUpload(c *gin.Context) {
 		req.ChunkSize = cfg.ChunkSizeBytes
 	}
 
+	userID := middleware.GetCurrentUserID(c)
+
 	// 创建上传会话
 	session := &UploadSession{
 		FileID:         req.FileID,
@@ -83,11 +110,11 @@ func (h *UploadHandler) InitUpload(c *gin.Context) {
 	}
 
 	h.mu.Lock()
-	h.uploads[req.FileID] = session
+	h.uploads[sessionKey(userID, req.FileID)] = session
 	h.mu.Unlock()
 
 	// 创建临时目录
-	tempDir := filepath.Join(h.uploadDir, req.FileID)
+	tempDir := chunkDir(userID, req.FileID)
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
 		h.ServerError(c, "创建临时目录失败")
 		return
@@ -117,8 +144,9 @@ func (h *UploadHandler) UploadChunk(c *gin.Context) {
 	}
 
 	// 获取上传会话
+	userID := middleware.GetCurrentUserID(c)
 	h.mu.RLock()
-	session, exists := h.uploads[fileID]
+	session, exists := h.uploads[sessionKey(userID, fileID)]
 	h.mu.RUnlock()
 
 	if !exists {
@@ -135,7 +163,7 @@ func (h *UploadHandler) UploadChunk(c *gin.Context) {
 	defer file.Close()
 
 	// 保存分片文件
-	chunkPath := filepath.Join(h.uploadDir, fileID, fmt.Sprintf("chunk_%d", chunkIndex))
+	chunkPath := filepath.Join(chunkDir(userID, fileID), fmt.Sprintf("chunk_%d", chunkIndex))
 	chunkFile, err := os.Create(chunkPath)
 	if err != nil {
 		h.ServerError(c, "创建分片文件失败")
@@ -194,8 +222,9 @@ func (h *UploadHandler) CompleteUpload(c *gin.Context) {
 	}
 
 	// 获取上传会话
+	userID := middleware.GetCurrentUserID(c)
 	h.mu.RLock()
-	session, exists := h.uploads[fileId]
+	session, exists := h.uploads[sessionKey(userID, fileId)]
 	h.mu.RUnlock()
 
 	if !exists {
@@ -212,8 +241,12 @@ func (h *UploadHandler) CompleteUpload(c *gin.Context) {
 	}
 	session.mu.Unlock()
 
-	// 合并文件
-	finalPath := filepath.Join(h.uploadDir, session.FileName)
+	// 合并文件，保存到用户自己的上传目录
+	finalPath, err := ResolveUpload(userID, session.FileName)
+	if err != nil {
+		h.ParamError(c, err.Error())
+		return
+	}
 	finalFile, err := os.Create(finalPath)
 	if err != nil {
 		h.ServerError(c, "创建最终文件失败")
@@ -223,7 +256,7 @@ func (h *UploadHandler) CompleteUpload(c *gin.Context) {
 
 	// 按顺序合并分片
 	for i := 0; i < session.TotalChunks; i++ {
-		chunkPath := filepath.Join(h.uploadDir, fileId, fmt.Sprintf("chunk_%d", i))
+		chunkPath := filepath.Join(chunkDir(userID, fileId), fmt.Sprintf("chunk_%d", i))
 		chunkFile, err := os.Open(chunkPath)
 		if err != nil {
 			h.ServerError(c, "读取分片文件失败")
@@ -239,12 +272,12 @@ func (h *UploadHandler) CompleteUpload(c *gin.Context) {
 	}
 
 	// 清理临时文件
-	tempDir := filepath.Join(h.uploadDir, fileId)
+	tempDir := chunkDir(userID, fileId)
 	os.RemoveAll(tempDir)
 
 	// 清理会话
 	h.mu.Lock()
-	delete(h.uploads, fileId)
+	delete(h.uploads, sessionKey(userID, fileId))
 	h.mu.Unlock()
 
 	h.Success(c, gin.H{
@@ -259,7 +292,7 @@ func (h *UploadHandler) GetUploadProgress(c *gin.Context) {
 	fileID := c.Param("fileId")
 
 	h.mu.RLock()
-	session, exists := h.uploads[fileID]
+	session, exists := h.uploads[sessionKey(middleware.GetCurrentUserID(c), fileID)]
 	h.mu.RUnlock()
 
 	if !exists {
@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"server/config"
+	"server/modules/alert"
+	"server/modules/notification"
+	"server/modules/position"
+	"server/modules/review"
 	"server/router"
 	"server/storage"
 	"server/utils"
@@ -14,6 +20,10 @@ import (
 )
 
 func main() {
+	migrateCmd := flag.String("migrate", "", "执行数据库迁移后退出: up / down / status")
+	migrateSteps := flag.Int("steps", 0, "迁移步数，up 默认执行全部，down 默认回滚 1 个")
+	flag.Parse()
+
 	cfg := config.Load()
 
 	// 初始化日志系统
@@ -38,6 +48,16 @@ func main() {
 	}
 	utils.LogInfo("数据库连接成功")
 
+	if *migrateCmd != "" {
+		err := runMigrateCommand(db, *migrateCmd, *migrateSteps)
+		db.Close()
+		if err != nil {
+			utils.LogError("数据库迁移失败: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 执行数据库迁移
 	utils.LogInfo("正在执行数据库迁移...")
 	if err := db.Migrate(); err != nil {
@@ -45,6 +65,11 @@ func main() {
 		os.Exit(1)
 	}
 	utils.LogInfo("数据库迁移完成")
+
+	// 按当前默认成本计算方法重新计算开仓批次，失败不影响启动
+	if err := position.RebuildLots(); err != nil {
+		utils.LogWarning("重新计算开仓批次失败: %v", err)
+	}
 	defer func() {
 		if err := db.Close(); err != nil {
 			utils.LogError("关闭数据库连接失败: %v", err)
@@ -53,6 +78,16 @@ func main() {
 		}
 	}()
 
+	// 启动计划价格监控
+	monitor := alert.NewMonitor(cfg.AlertCheckInterval)
+	monitor.Start()
+
+	// 启动通知发送和复盘提醒
+	dispatcher := notification.NewDispatcher(cfg.NotifyDispatchInterval)
+	dispatcher.Start()
+	reminder := review.NewReminder(cfg.ReviewReminderTime)
+	reminder.Start()
+
 	// 设置路由
 	utils.LogInfo("正在设置路由...")
 	r := router.SetupRouter()
@@ -95,7 +130,37 @@ func main() {
 	} else {
 		utils.LogInfo("服务器已优雅关闭")
 	}
+	monitor.Stop()
+	reminder.Stop()
+	dispatcher.Stop()
 
 	utils.LogInfo("服务器退出")
 	utils.LogInfo("=========================================")
 }
+
+// runMigrateCommand 执行命令行指定的迁移操作
+func runMigrateCommand(db *storage.DB, cmd string, steps int) error {
+	switch cmd {
+	case "up":
+		return db.MigrateUp(steps)
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		return db.MigrateDown(steps)
+	case "status":
+		status, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, appliedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("未知的迁移命令: %s", cmd)
+}
@@ -0,0 +1,54 @@
+package market
+
+import (
+	"sync"
+	"time"
+)
+
+// QuoteCache 行情缓存，按 TTL 过期
+type QuoteCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	quote     Quote
+	expiresAt time.Time
+}
+
+// NewQuoteCache 创建行情缓存，ttl 小于等于 0 时不缓存
+func NewQuoteCache(ttl time.Duration) *QuoteCache {
+	return &QuoteCache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func cacheKey(region, code string) string {
+	return region + ":" + code
+}
+
+// Get 获取未过期的缓存行情
+func (c *QuoteCache) Get(region, code string) (Quote, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[cacheKey(region, code)]
+	if !ok || time.Now().After(e.expiresAt) {
+		return Quote{}, false
+	}
+	return e.quote, true
+}
+
+// Set 写入缓存，顺便清理已过期的条目
+func (c *QuoteCache) Set(region string, quote Quote) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[cacheKey(region, quote.Code)] = cacheEntry{quote: quote, expiresAt: now.Add(c.ttl)}
+}
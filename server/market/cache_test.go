@@ -0,0 +1,48 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuoteCacheTTL(t *testing.T) {
+	c := NewQuoteCache(50 * time.Millisecond)
+	c.Set(RegionChina, Quote{Code: "600000", Price: 10.5})
+
+	tests := []struct {
+		region string
+		code   string
+		hit    bool
+	}{
+		{RegionChina, "600000", true},
+		{RegionHongKong, "600000", false},
+		{RegionChina, "000001", false},
+	}
+	for _, tt := range tests {
+		q, hit := c.Get(tt.region, tt.code)
+		if hit != tt.hit || (hit && q.Price != 10.5) {
+			t.Errorf("Get(%s, %s) = %+v, %v, want hit=%v", tt.region, tt.code, q, hit, tt.hit)
+		}
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if _, hit := c.Get(RegionChina, "600000"); hit {
+		t.Fatal("过期的行情不应命中")
+	}
+
+	// 写入时清理已过期的条目
+	c.Set(RegionChina, Quote{Code: "000001"})
+	if len(c.entries) != 1 {
+		t.Fatalf("过期条目未清理，剩余 %d 条", len(c.entries))
+	}
+}
+
+func TestQuoteCacheDisabled(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		c := NewQuoteCache(ttl)
+		c.Set(RegionChina, Quote{Code: "600000"})
+		if _, hit := c.Get(RegionChina, "600000"); hit {
+			t.Errorf("ttl=%v 时不应缓存", ttl)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package market
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mockAt 创建固定时间的模拟数据源
+func mockAt(basePrice BasePriceFunc, now time.Time) *MockProvider {
+	p := NewMockProvider(RegionChina, basePrice)
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestMockProviderDeterministic(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	basePrice := func(code string) (float64, bool) {
+		if code == "600000" {
+			return 10.5, true
+		}
+		return 0, false
+	}
+
+	tests := []struct {
+		name string
+		code string
+		now  time.Time
+	}{
+		{"开盘前", "600000", time.Date(2024, 1, 2, 0, 0, 0, 0, loc)},
+		{"盘中", "600000", time.Date(2024, 1, 2, 10, 37, 0, 0, loc)},
+		{"收盘", "600000", time.Date(2024, 1, 2, 15, 0, 0, 0, loc)},
+		{"无基准价", "AAPL", time.Date(2024, 1, 2, 23, 59, 0, 0, loc)},
+		{"港股", "00700", time.Date(2024, 3, 15, 11, 5, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		first, err := mockAt(basePrice, tt.now).GetQuotes(context.Background(), []string{tt.code})
+		if err != nil {
+			t.Fatalf("%s: GetQuotes 失败: %v", tt.name, err)
+		}
+		second, _ := mockAt(basePrice, tt.now).GetQuotes(context.Background(), []string{tt.code, "000001"})
+		q := first[tt.code]
+		if !reflect.DeepEqual(q, second[tt.code]) {
+			t.Fatalf("%s: 同一代码同一时间的行情不一致: %+v != %+v", tt.name, q, second[tt.code])
+		}
+
+		if q.Code != tt.code || q.Region != RegionChina || q.Source != "mock" || !q.Time.Equal(tt.now) {
+			t.Errorf("%s: 基本字段不正确: %+v", tt.name, q)
+		}
+		if q.Low > q.Price || q.Price > q.High || q.Low > q.Open || q.Open > q.High || q.Low <= 0 {
+			t.Errorf("%s: 最高最低价应包含现价和开盘价: %+v", tt.name, q)
+		}
+		if q.Change != round(q.Price-q.PrevClose) || q.Volume <= 0 {
+			t.Errorf("%s: 涨跌额或成交量不正确: %+v", tt.name, q)
+		}
+		if price, found := basePrice(tt.code); found && q.PrevClose != price {
+			t.Errorf("%s: 昨收 %v，want 基准价 %v", tt.name, q.PrevClose, price)
+		} else if !found && (q.PrevClose < 5 || q.PrevClose >= 205) {
+			t.Errorf("%s: 无基准价时按代码生成的昨收 %v 超出范围", tt.name, q.PrevClose)
+		}
+		if tt.now.Hour() == 0 && tt.now.Minute() == 0 && (q.High != q.Open || q.Low != q.Open || q.Price != q.Open) {
+			t.Errorf("%s: 当日第一个价格点的最高最低价应等于开盘价: %+v", tt.name, q)
+		}
+	}
+}
+
+func TestMockProviderIntraday(t *testing.T) {
+	day := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	p := NewMockProvider(RegionUSA, nil)
+	prev := p.quote("AAPL", day)
+	for i := 1; i <= 60; i++ {
+		q := p.quote("AAPL", day.Add(time.Duration(i)*5*time.Minute))
+		if q.Open != prev.Open || q.PrevClose != prev.PrevClose {
+			t.Fatalf("同一天的开盘价和昨收应不变: %+v, %+v", prev, q)
+		}
+		if q.High < prev.High || q.Low > prev.Low || q.Volume < prev.Volume {
+			t.Fatalf("随时间推移最高价、最低价和成交量不应回退: %+v, %+v", prev, q)
+		}
+		prev = q
+	}
+}
+
+func TestMockProviderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewMockProvider(RegionChina, nil).GetQuotes(ctx, []string{"600000"}); err == nil {
+		t.Fatal("上下文取消后应返回错误")
+	}
+}
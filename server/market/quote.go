@@ -0,0 +1,86 @@
+package market
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// 股票地区，与前端 stockCategoryConfig 中的 region 一致
+const (
+	RegionChina    = "china"
+	RegionHongKong = "hongkong"
+	RegionUSA      = "usa"
+)
+
+// Regions 支持的全部地区
+var Regions = []string{RegionChina, RegionHongKong, RegionUSA}
+
+// defaultLotSizes 各地区默认每手股数，港股每手股数因股票而异，应在股票上单独设置
+var defaultLotSizes = map[string]int{
+	RegionChina:    100,
+	RegionHongKong: 100,
+	RegionUSA:      1,
+}
+
+// DefaultLotSize 地区的默认每手股数，未知地区为 1
+func DefaultLotSize(region string) int {
+	if lot, ok := defaultLotSizes[region]; ok {
+		return lot
+	}
+	return 1
+}
+
+// defaultCurrencies 各地区默认计价币种
+var defaultCurrencies = map[string]string{
+	RegionChina:    "CNY",
+	RegionHongKong: "HKD",
+	RegionUSA:      "USD",
+}
+
+// DefaultCurrency 地区的默认计价币种，未知地区为空
+func DefaultCurrency(region string) string {
+	return defaultCurrencies[region]
+}
+
+// Quote 实时或延时行情
+type Quote struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	Price     float64   `json:"price"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	PrevClose float64   `json:"prevClose"`
+	Change    float64   `json:"change"`
+	ChangePct float64   `json:"changePct"`
+	Volume    float64   `json:"volume"`
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Delayed   bool      `json:"delayed"`
+}
+
+// QuoteProvider 行情数据源，每个地区注册一个
+type QuoteProvider interface {
+	// Name 数据源名称
+	Name() string
+	// GetQuotes 批量获取行情，返回的 map 以股票代码为键，缺失的代码视为获取失败
+	GetQuotes(ctx context.Context, codes []string) (map[string]Quote, error)
+}
+
+var (
+	chinaCodePattern    = regexp.MustCompile(`^\d{6}$`)
+	hongKongCodePattern = regexp.MustCompile(`^\d{4,5}(\.HK)?$`)
+)
+
+// InferRegion 根据代码格式推断地区，用于股票列表中不存在的代码
+func InferRegion(code string) string {
+	switch {
+	case chinaCodePattern.MatchString(code):
+		return RegionChina
+	case hongKongCodePattern.MatchString(code):
+		return RegionHongKong
+	}
+	return RegionUSA
+}
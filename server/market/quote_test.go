@@ -0,0 +1,28 @@
+package market
+
+import "testing"
+
+func TestInferRegion(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"600000", RegionChina},
+		{"000001", RegionChina},
+		{"00700", RegionHongKong},
+		{"0700", RegionHongKong},
+		{"00700.HK", RegionHongKong},
+		{"AAPL", RegionUSA},
+		{"BRK.B", RegionUSA},
+		{"1234567", RegionUSA},
+		{"123", RegionUSA},
+		{"600000.SH", RegionUSA},
+		{"0700.hk", RegionUSA},
+		{"", RegionUSA},
+	}
+	for _, tt := range tests {
+		if got := InferRegion(tt.code); got != tt.want {
+			t.Errorf("InferRegion(%q) = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package market
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry 按地区选择行情数据源
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]QuoteProvider
+}
+
+// NewRegistry 创建数据源注册表
+func NewRegistry() *Registry {
+	return &Registry{providers: map[string]QuoteProvider{}}
+}
+
+// Register 为地区注册数据源，重复注册时覆盖
+func (r *Registry) Register(region string, provider QuoteProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[region] = provider
+}
+
+// Provider 获取地区对应的数据源
+func (r *Registry) Provider(region string) (QuoteProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[region]
+	if !ok {
+		return nil, fmt.Errorf("地区 %s 没有可用的行情数据源", region)
+	}
+	return p, nil
+}
+
+// defaultRegistry 全局数据源注册表
+var defaultRegistry = NewRegistry()
+
+// Register 向全局注册表注册数据源
+func Register(region string, provider QuoteProvider) {
+	defaultRegistry.Register(region, provider)
+}
+
+// ProviderFor 从全局注册表获取地区对应的数据源
+func ProviderFor(region string) (QuoteProvider, error) {
+	return defaultRegistry.Provider(region)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"server/config"
+	"server/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -10,16 +11,23 @@ import (
 // UserContextKey 用户上下文键
 const UserContextKey = "user"
 
-// AuthMiddleware 认证中间件
+// CurrentUser 当前登录用户，由 JWT 载荷解析得到
+type CurrentUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// AuthMiddleware 认证中间件，解析 Authorization 头中的 JWT 并写入上下文
+// 未携带或携带无效 token 时上下文中的用户为空，是否拒绝请求由 RequireAuth 决定
 func AuthMiddleware() gin.HandlerFunc {
 	cfg := config.Load()
-	_ = cfg // placeholder to show config usage; token validation below remains mock
+	if cfg.JWTSecret == "change_me_in_env" {
+		utils.LogWarning("JWT_SECRET 使用默认值，请在环境变量中配置")
+	}
 	return func(c *gin.Context) {
 		// 从请求头获取token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			// 如果没有token，设置空用户信息
-			c.Set(UserContextKey, nil)
 			c.Next()
 			return
 		}
@@ -27,43 +35,49 @@ func AuthMiddleware() gin.HandlerFunc {
 		// 检查token格式
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.Set(UserContextKey, nil)
 			c.Next()
 			return
 		}
 
-		token := tokenParts[1]
-
 		// 验证token并获取用户信息
-		userInfo := validateToken(token)
-		c.Set(UserContextKey, userInfo)
+		claims, err := utils.ParseJWT(tokenParts[1], cfg.JWTSecret)
+		if err != nil {
+			utils.LogDebug("token校验失败: %v", err)
+			c.Next()
+			return
+		}
+		c.Set(UserContextKey, &CurrentUser{ID: claims.Subject, Username: claims.Username})
 
 		c.Next()
 	}
 }
 
-// validateToken 验证token并返回用户信息
-func validateToken(token string) any {
-	// 这里应该从数据库或缓存中验证token
-	// 暂时使用简单的验证逻辑
-	if token == "mock_token_123456" {
-		return map[string]any{
-			"id":       1,
-			"username": "admin",
-			"email":    "admin@example.com",
-			"nickname": "管理员",
-			"status":   1,
+// RequireAuth 要求请求已登录，需在 AuthMiddleware 之后使用
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetCurrentUser(c) == nil {
+			utils.UnauthorizedError(c, "请先登录")
+			c.Abort()
+			return
 		}
+		c.Next()
 	}
-
-	return nil
 }
 
-// GetCurrentUser 从Context中获取当前用户信息
-func GetCurrentUser(c *gin.Context) any {
+// GetCurrentUser 从Context中获取当前用户信息，未登录时返回nil
+func GetCurrentUser(c *gin.Context) *CurrentUser {
 	user, exists := c.Get(UserContextKey)
 	if !exists {
 		return nil
 	}
-	return user
+	currentUser, _ := user.(*CurrentUser)
+	return currentUser
+}
+
+// GetCurrentUserID 获取当前用户ID，未登录时返回空字符串
+func GetCurrentUserID(c *gin.Context) string {
+	if user := GetCurrentUser(c); user != nil {
+		return user.ID
+	}
+	return ""
 }
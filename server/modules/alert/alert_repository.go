@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+)
+
+// AlertRepository 提醒记录数据访问层
+type AlertRepository struct{}
+
+// NewAlertRepository 创建提醒记录仓库
+func NewAlertRepository() *AlertRepository {
+	return &AlertRepository{}
+}
+
+// Create 写入提醒记录，同一计划的同一类型和价位已存在时不写入并返回 false
+func (r *AlertRepository) Create(a *Alert) (bool, error) {
+	result, err := storage.GetDB().Exec(`INSERT INTO plan_alerts (
+		id, user_id, plan_id, plan_name, stock_code, stock_name, kind, level, price, quote_time, source, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (plan_id, kind, level) DO NOTHING`,
+		a.ID, a.UserID, a.PlanID, a.PlanName, a.StockCode, a.StockName, a.Kind, a.Level, a.Price,
+		a.QuoteTime, a.Source, a.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// List 分页查询提醒记录，按触发时间倒序
+func (r *AlertRepository) List(userID string, req *AlertListRequest, page, pageSize int) ([]Alert, int, error) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
+
+	if req.PlanID != "" {
+		where = append(where, "plan_id = ?")
+		args = append(args, req.PlanID)
+	}
+	if req.StockCode != "" {
+		where = append(where, "stock_code = ?")
+		args = append(args, req.StockCode)
+	}
+	if req.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, req.Kind)
+	}
+	if req.StartDate != "" {
+		where = append(where, "created_at >= ?")
+		args = append(args, req.StartDate)
+	}
+	if req.EndDate != "" {
+		where = append(where, "created_at <= ?")
+		args = append(args, req.EndDate)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := storage.GetDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM plan_alerts WHERE %s", whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, user_id, plan_id, plan_name, stock_code, stock_name, kind, level, price,
+		quote_time, source, created_at
+		FROM plan_alerts WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	alerts := []Alert{}
+	for rows.Next() {
+		var a Alert
+		var planName, stockName, source sql.NullString
+		var quoteTime sql.NullTime
+		if err := rows.Scan(
+			&a.ID, &a.UserID, &a.PlanID, &planName, &a.StockCode, &stockName, &a.Kind, &a.Level, &a.Price,
+			&quoteTime, &source, &a.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		a.PlanName = planName.String
+		a.StockName = stockName.String
+		a.Source = source.String
+		a.QuoteTime = quoteTime.Time
+		alerts = append(alerts, a)
+	}
+	return alerts, total, rows.Err()
+}
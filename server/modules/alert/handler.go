@@ -0,0 +1,57 @@
+package alert
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAlertRoutes 注册计划价格提醒路由
+func RegisterAlertRoutes(r *gin.RouterGroup) {
+	alertService := NewAlertService()
+
+	g := r.Group("/alerts")
+	{
+		g.GET("/getList", func(c *gin.Context) {
+			req := &AlertListRequest{
+				PlanID:    c.Query("planId"),
+				StockCode: c.Query("stockCode"),
+				Kind:      c.Query("kind"),
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+			}
+			if pageStr := c.Query("page"); pageStr != "" {
+				if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+					req.Page = page
+				}
+			}
+			if pageSizeStr := c.Query("pageSize"); pageSizeStr != "" {
+				if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
+					req.PageSize = pageSize
+				}
+			}
+
+			response, err := alertService.ListAlerts(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		// 立即检查当前用户的计划价位，不等待后台监控
+		g.POST("/check", func(c *gin.Context) {
+			result, err := alertService.Check(c.Request.Context(), middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
+	}
+}
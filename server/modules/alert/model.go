@@ -0,0 +1,52 @@
+package alert
+
+import "time"
+
+// 提醒类型
+const (
+	KindTarget     = "target"      // 触及目标价
+	KindStopLoss   = "stop_loss"   // 触及止损价
+	KindTakeProfit = "take_profit" // 触及止盈价
+)
+
+// Alert 计划价格提醒记录，同一计划的同一类型和价位只记录一次
+type Alert struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"userId" db:"user_id"`
+	PlanID    string    `json:"planId" db:"plan_id"`
+	PlanName  string    `json:"planName" db:"plan_name"`
+	StockCode string    `json:"stockCode" db:"stock_code"`
+	StockName string    `json:"stockName" db:"stock_name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Level     float64   `json:"level" db:"level"` // 触及的计划价位
+	Price     float64   `json:"price" db:"price"` // 触发时的行情价格
+	QuoteTime time.Time `json:"quoteTime" db:"quote_time"`
+	Source    string    `json:"source" db:"source"` // 行情数据源
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// AlertListRequest 提醒列表请求
+type AlertListRequest struct {
+	PlanID    string `form:"planId"`
+	StockCode string `form:"stockCode"`
+	Kind      string `form:"kind"`
+	StartDate string `form:"startDate"` // 按触发时间过滤
+	EndDate   string `form:"endDate"`
+	Page      int    `form:"page"`
+	PageSize  int    `form:"pageSize"`
+}
+
+// AlertListResponse 提醒列表响应
+type AlertListResponse struct {
+	Items    []Alert `json:"list"`
+	Total    int     `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"pageSize"`
+}
+
+// CheckResult 一次价格检查的结果
+type CheckResult struct {
+	Plans  int     `json:"plans"`  // 检查的计划数
+	Quotes int     `json:"quotes"` // 获取到行情的代码数
+	Alerts []Alert `json:"alerts"` // 本次新产生的提醒
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"server/utils"
+)
+
+// Monitor 后台定时检查计划价位
+type Monitor struct {
+	service  AlertService
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewMonitor 创建计划价格监控，interval 不大于 0 时不启动
+func NewMonitor(interval time.Duration) *Monitor {
+	return &Monitor{service: NewAlertService(), interval: interval}
+}
+
+// Start 在后台按间隔检查，启动后立即检查一次
+func (m *Monitor) Start() {
+	if m.interval <= 0 {
+		utils.LogInfo("计划价格监控未启用")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.runOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	utils.LogInfo("计划价格监控已启动，检查间隔: %s", m.interval)
+}
+
+// Stop 停止监控并等待正在进行的检查结束
+func (m *Monitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+	utils.LogInfo("计划价格监控已停止")
+}
+
+// runOnce 执行一次检查，单次检查不超过检查间隔
+func (m *Monitor) runOnce(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	// 单次检查出错不影响后续检查
+	defer func() {
+		if r := recover(); r != nil {
+			utils.LogError("计划价格检查异常: %v", r)
+		}
+	}()
+
+	result, err := m.service.Check(ctx, "")
+	if err != nil {
+		if ctx.Err() == nil {
+			utils.LogError("计划价格检查失败: %v", err)
+		}
+		return
+	}
+	if len(result.Alerts) > 0 {
+		utils.LogInfo("计划价格检查完成，计划 %d 个，新提醒 %d 条", result.Plans, len(result.Alerts))
+	}
+}
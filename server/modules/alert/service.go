@@ -0,0 +1,298 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"server/modules/notification"
+	"server/modules/plan"
+	"server/modules/quote"
+	"server/utils"
+)
+
+// maxCodesPerRequest 每次向行情服务查询的代码数量上限，与行情接口的限制一致
+const maxCodesPerRequest = 50
+
+// QuoteSource 价格检查使用的行情来源，默认为行情报价服务
+type QuoteSource interface {
+	GetQuotes(ctx context.Context, userID string, codes []string) (*quote.QuoteListResponse, error)
+}
+
+// quoteSource 由 SetQuoteSource 替换，便于接入其他行情或在本地使用固定价格
+var quoteSource QuoteSource
+
+// SetQuoteSource 设置价格检查使用的行情来源，为空时恢复默认
+func SetQuoteSource(source QuoteSource) {
+	quoteSource = source
+}
+
+// AlertService 计划价格提醒服务接口
+type AlertService interface {
+	ListAlerts(userID string, req *AlertListRequest) (*AlertListResponse, error)
+	// Check 检查计划价位并记录新触发的提醒，userID 为空时检查全部用户
+	Check(ctx context.Context, userID string) (*CheckResult, error)
+}
+
+// alertService 计划价格提醒服务实现
+type alertService struct {
+	alertRepo           *AlertRepository
+	planRepo            *plan.PlanRepository
+	planService         plan.PlanService
+	notificationService notification.NotificationService
+}
+
+// NewAlertService 创建计划价格提醒服务
+func NewAlertService() AlertService {
+	return &alertService{
+		alertRepo:           NewAlertRepository(),
+		planRepo:            plan.NewPlanRepository(),
+		planService:         plan.NewPlanService(),
+		notificationService: notification.NewNotificationService(),
+	}
+}
+
+// source 当前使用的行情来源
+func (s *alertService) source() QuoteSource {
+	if quoteSource != nil {
+		return quoteSource
+	}
+	return quote.NewQuoteService()
+}
+
+// ListAlerts 获取提醒列表
+func (s *alertService) ListAlerts(userID string, req *AlertListRequest) (*AlertListResponse, error) {
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	// 结束日期只有日期时包含当天全部提醒
+	if len(req.EndDate) == len("2006-01-02") {
+		req.EndDate += " 23:59:59"
+	}
+
+	alerts, total, err := s.alertRepo.List(userID, req, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取提醒记录失败: %w", err)
+	}
+
+	return &AlertListResponse{
+		Items:    alerts,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Check 按用户分组获取计划股票的行情，记录触及的价位，目标价触发后计划状态改为 triggered
+func (s *alertService) Check(ctx context.Context, userID string) (*CheckResult, error) {
+	plans, err := s.planRepo.ListMonitored()
+	if err != nil {
+		return nil, fmt.Errorf("获取监控计划失败: %w", err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	byUser := map[string][]plan.Plan{}
+	users := []string{}
+	for _, p := range plans {
+		if userID != "" && p.UserID != userID {
+			continue
+		}
+		if !inWindow(&p, today) {
+			continue
+		}
+		if _, ok := byUser[p.UserID]; !ok {
+			users = append(users, p.UserID)
+		}
+		byUser[p.UserID] = append(byUser[p.UserID], p)
+	}
+
+	result := &CheckResult{Alerts: []Alert{}}
+	source := s.source()
+	for _, uid := range users {
+		userPlans := byUser[uid]
+		result.Plans += len(userPlans)
+
+		codes := []string{}
+		seen := map[string]bool{}
+		for _, p := range userPlans {
+			if !seen[p.StockCode] {
+				seen[p.StockCode] = true
+				codes = append(codes, p.StockCode)
+			}
+		}
+
+		quotes := map[string]quoteInfo{}
+		for start := 0; start < len(codes); start += maxCodesPerRequest {
+			end := start + maxCodesPerRequest
+			if end > len(codes) {
+				end = len(codes)
+			}
+			resp, err := source.GetQuotes(ctx, uid, codes[start:end])
+			if err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				utils.LogWarning("价格检查获取行情失败，用户: %s, 错误: %v", uid, err)
+				continue
+			}
+			for _, q := range resp.Items {
+				quotes[q.Code] = quoteInfo{price: q.Price, time: q.Time, source: q.Source, name: q.Name}
+			}
+			for _, e := range resp.Errors {
+				utils.LogDebug("价格检查跳过 %s: %s", e.Code, e.Message)
+			}
+		}
+		result.Quotes += len(quotes)
+
+		for i := range userPlans {
+			p := &userPlans[i]
+			q, ok := quotes[p.StockCode]
+			if !ok || q.price <= 0 {
+				continue
+			}
+			for _, hit := range crossedLevels(p, q.price) {
+				a, err := s.record(p, hit, q)
+				if err != nil {
+					utils.LogError("记录价格提醒失败，计划ID: %s, 错误: %v", p.ID, err)
+					continue
+				}
+				if a != nil {
+					result.Alerts = append(result.Alerts, *a)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// quoteInfo 价格检查用到的行情字段
+type quoteInfo struct {
+	price  float64
+	time   time.Time
+	source string
+	name   string
+}
+
+// levelHit 被触及的计划价位
+type levelHit struct {
+	kind  string
+	level float64
+}
+
+// crossedLevels 返回行情价格已触及的计划价位，判断方式与计划回测一致
+// 未开仓（active / pending）的计划检查目标价，已触发或执行中的计划检查止损价和止盈价
+// 买多计划价格不高于目标价、不高于止损价、不低于止盈价时触及，买空计划相反
+func crossedLevels(p *plan.Plan, price float64) []levelHit {
+	long := p.Type != plan.TypeSell
+	// adverse 价格向不利方向到达价位，favorable 向有利方向到达价位
+	adverse := func(level float64) bool {
+		if long {
+			return price <= level
+		}
+		return price >= level
+	}
+	favorable := func(level float64) bool {
+		if long {
+			return price >= level
+		}
+		return price <= level
+	}
+
+	hits := []levelHit{}
+	switch p.Status {
+	case plan.StatusActive, plan.StatusPending:
+		if p.TargetPrice > 0 && adverse(p.TargetPrice) {
+			hits = append(hits, levelHit{KindTarget, p.TargetPrice})
+		}
+	case plan.StatusTriggered, plan.StatusExecuting:
+		if p.StopLoss > 0 && adverse(p.StopLoss) {
+			hits = append(hits, levelHit{KindStopLoss, p.StopLoss})
+		}
+		if p.TakeProfit > 0 && favorable(p.TakeProfit) {
+			hits = append(hits, levelHit{KindTakeProfit, p.TakeProfit})
+		}
+	}
+	return hits
+}
+
+// record 写入提醒，已记录过的价位返回 nil；目标价触发时把计划状态改为 triggered
+func (s *alertService) record(p *plan.Plan, hit levelHit, q quoteInfo) (*Alert, error) {
+	stockName := p.StockName
+	if stockName == "" {
+		stockName = q.name
+	}
+	a := &Alert{
+		ID:        utils.GenerateID(),
+		UserID:    p.UserID,
+		PlanID:    p.ID,
+		PlanName:  p.Name,
+		StockCode: p.StockCode,
+		StockName: stockName,
+		Kind:      hit.kind,
+		Level:     hit.level,
+		Price:     q.price,
+		QuoteTime: q.time,
+		Source:    q.source,
+		CreatedAt: time.Now(),
+	}
+	created, err := s.alertRepo.Create(a)
+	if err != nil || !created {
+		return nil, err
+	}
+	utils.LogInfo("计划价格提醒，计划: %s, 股票: %s, 类型: %s, 价位: %.4f, 现价: %.4f", p.Name, p.StockCode, hit.kind, hit.level, q.price)
+	if err := s.notificationService.Notify(p.UserID, notification.EventPlanAlert, "alert:"+a.ID, alertMessage(a)); err != nil {
+		utils.LogWarning("发送价格提醒通知失败，计划ID: %s, 错误: %v", p.ID, err)
+	}
+
+	if hit.kind == KindTarget {
+		if _, err := s.planService.UpdatePlanStatus(p.UserID, p.ID, plan.StatusTriggered); err != nil {
+			utils.LogWarning("更新计划状态失败，计划ID: %s, 错误: %v", p.ID, err)
+		}
+	}
+	return a, nil
+}
+
+// kindNames 提醒类型在通知中的名称
+var kindNames = map[string]string{
+	KindTarget:     "目标价",
+	KindStopLoss:   "止损价",
+	KindTakeProfit: "止盈价",
+}
+
+// alertMessage 生成提醒的通知内容
+func alertMessage(a *Alert) *notification.Message {
+	stock := a.StockCode
+	if a.StockName != "" {
+		stock = a.StockName + "(" + a.StockCode + ")"
+	}
+	return &notification.Message{
+		Event:     notification.EventPlanAlert,
+		Title:     fmt.Sprintf("%s 触及%s", stock, kindNames[a.Kind]),
+		Content:   fmt.Sprintf("计划「%s」%s %g，现价 %g", a.PlanName, kindNames[a.Kind], a.Level, a.Price),
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// inWindow 判断计划在指定日期是否处于有效期内，未设置开始或结束时间时不限制
+func inWindow(p *plan.Plan, today string) bool {
+	if p.StartTime != "" && today < datePart(p.StartTime) {
+		return false
+	}
+	if p.EndTime != "" && today > datePart(p.EndTime) {
+		return false
+	}
+	return true
+}
+
+// datePart 取时间的日期部分
+func datePart(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAnalyticsRoutes 注册交易绩效分析路由
+func RegisterAnalyticsRoutes(r *gin.RouterGroup) {
+	analyticsService := NewAnalyticsService()
+
+	g := r.Group("/analytics")
+	{
+		g.GET("/performance", func(c *gin.Context) {
+			req := &PerformanceRequest{
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+				StockCode: c.Query("stockCode"),
+				Strategy:  c.Query("strategy"),
+				PlanID:    c.Query("planId"),
+				Method:    c.Query("method"),
+			}
+			if equityStr := c.Query("initialEquity"); equityStr != "" {
+				equity, err := strconv.ParseFloat(equityStr, 64)
+				if err != nil || equity < 0 {
+					handler.Error(c, handler.CodeInvalid, "期初权益无效: "+equityStr)
+					return
+				}
+				req.InitialEquity = &equity
+			}
+
+			response, err := analyticsService.Performance(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.GET("/strategies", func(c *gin.Context) {
+			req := &StrategyReportRequest{
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+				StockCode: c.Query("stockCode"),
+				Method:    c.Query("method"),
+			}
+
+			response, err := analyticsService.StrategyReport(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"server/modules/position"
+)
+
+// summarizeTrades 统计胜率、盈亏、期望和连续盈亏，trades 需按平仓时间升序排列
+func summarizeTrades(perf *Performance, trades []position.Trade) {
+	var grossWin, grossLoss, holding float64
+	winStreak, lossStreak := 0, 0
+	for _, t := range trades {
+		pnl := t.BasePnL
+		perf.TradeCount++
+		perf.TotalPnL += pnl
+		holding += t.HoldingDays
+
+		switch {
+		case pnl > 0:
+			perf.WinCount++
+			grossWin += pnl
+			perf.LargestWin = math.Max(perf.LargestWin, pnl)
+			winStreak++
+			lossStreak = 0
+		case pnl < 0:
+			perf.LossCount++
+			grossLoss += pnl
+			perf.LargestLoss = math.Min(perf.LargestLoss, pnl)
+			lossStreak++
+			winStreak = 0
+		default:
+			// 不盈不亏中断连续盈亏
+			winStreak, lossStreak = 0, 0
+		}
+		if winStreak > perf.MaxWinStreak {
+			perf.MaxWinStreak = winStreak
+		}
+		if lossStreak > perf.MaxLossStreak {
+			perf.MaxLossStreak = lossStreak
+		}
+	}
+
+	if perf.TradeCount > 0 {
+		perf.WinRate = round2(float64(perf.WinCount) / float64(perf.TradeCount) * 100)
+		perf.Expectancy = round2(perf.TotalPnL / float64(perf.TradeCount))
+		perf.AvgHoldingDays = round2(holding / float64(perf.TradeCount))
+	}
+	if perf.WinCount > 0 {
+		perf.AvgWin = round2(grossWin / float64(perf.WinCount))
+	}
+	if perf.LossCount > 0 {
+		perf.AvgLoss = round2(grossLoss / float64(perf.LossCount))
+		perf.ProfitFactor = round2(grossWin / -grossLoss)
+	}
+	perf.TotalPnL = round2(perf.TotalPnL)
+	perf.LargestWin = round2(perf.LargestWin)
+	perf.LargestLoss = round2(perf.LargestLoss)
+}
+
+// buildEquityCurve 按平仓日期汇总每日盈亏，生成从 start 到 end 的每日权益曲线
+// 曲线包含区间内的全部工作日以及有平仓交易的休息日，start、end 为空时取第一笔和最后一笔交易的日期
+func buildEquityCurve(trades []position.Trade, initialEquity float64, start, end string) []EquityPoint {
+	curve := []EquityPoint{}
+	if len(trades) == 0 {
+		return curve
+	}
+
+	daily := map[string]float64{}
+	counts := map[string]int{}
+	for _, t := range trades {
+		date := datePart(t.CloseTime)
+		daily[date] += t.BasePnL
+		counts[date]++
+	}
+	if start == "" || datePart(trades[0].CloseTime) < start {
+		start = datePart(trades[0].CloseTime)
+	}
+	last := datePart(trades[len(trades)-1].CloseTime)
+	if end == "" || end < last {
+		end = last
+	}
+	if today := time.Now().Format("2006-01-02"); end > today && last <= today {
+		end = today
+	}
+
+	from, err1 := time.Parse("2006-01-02", start)
+	to, err2 := time.Parse("2006-01-02", end)
+	if err1 != nil || err2 != nil {
+		return curve
+	}
+
+	var cum float64
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		weekend := d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+		if weekend && counts[date] == 0 {
+			continue
+		}
+		cum += daily[date]
+		curve = append(curve, EquityPoint{
+			Date:       date,
+			PnL:        round2(daily[date]),
+			CumPnL:     round2(cum),
+			Equity:     round2(initialEquity + cum),
+			TradeCount: counts[date],
+		})
+	}
+	return curve
+}
+
+// applyDrawdown 计算权益曲线每个点的回撤以及最大回撤和最长回撤持续时间
+// 期初权益视为第一天之前的高点
+func applyDrawdown(perf *Performance, curve []EquityPoint) {
+	if len(curve) == 0 {
+		return
+	}
+	peak := perf.InitialEquity
+	peakDate := curve[0].Date
+	underwaterSince := ""
+	for i := range curve {
+		p := &curve[i]
+		if p.Equity >= peak {
+			if underwaterSince != "" {
+				perf.MaxDrawdownDays = maxInt(perf.MaxDrawdownDays, daysBetween(underwaterSince, p.Date))
+				underwaterSince = ""
+			}
+			peak = p.Equity
+			peakDate = p.Date
+			continue
+		}
+
+		if underwaterSince == "" {
+			underwaterSince = peakDate
+		}
+		p.Drawdown = round2(peak - p.Equity)
+		if peak > 0 {
+			p.DrawdownPct = round2(p.Drawdown / peak * 100)
+		}
+		if p.Drawdown > perf.MaxDrawdown {
+			perf.MaxDrawdown = p.Drawdown
+			perf.MaxDrawdownPct = p.DrawdownPct
+			perf.DrawdownPeakDate = peakDate
+			perf.DrawdownLowDate = p.Date
+		}
+	}
+	// 统计结束时仍未收复
+	if underwaterSince != "" {
+		perf.MaxDrawdownDays = maxInt(perf.MaxDrawdownDays, daysBetween(underwaterSince, curve[len(curve)-1].Date))
+	}
+}
+
+// applyRatios 按每日收益计算年化夏普和索提诺比率
+// 期初权益为正时每日收益为当日盈亏除以前一日权益，否则直接使用当日盈亏金额
+func applyRatios(perf *Performance, curve []EquityPoint) {
+	if len(curve) < 2 {
+		return
+	}
+	returns := make([]float64, 0, len(curve))
+	prevEquity := perf.InitialEquity
+	for _, p := range curve {
+		r := p.PnL
+		if perf.InitialEquity > 0 {
+			if prevEquity <= 0 {
+				// 权益已亏完，之后的收益率没有意义
+				break
+			}
+			r = p.PnL / prevEquity
+		}
+		returns = append(returns, r)
+		prevEquity = p.Equity
+	}
+	if len(returns) < 2 {
+		return
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(len(returns))
+
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+	annualize := math.Sqrt(tradingDaysPerYear)
+	if std > 0 {
+		perf.Sharpe = round2(mean / std * annualize)
+	}
+	if downsideDev > 0 {
+		perf.Sortino = round2(mean / downsideDev * annualize)
+	}
+}
+
+// datePart 取交易时间的日期部分
+func datePart(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+// daysBetween 计算两个日期相差的自然日数
+func daysBetween(from, to string) int {
+	a, err1 := time.Parse("2006-01-02", from)
+	b, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func round2(f float64) float64 {
+	r := math.Round(f*100) / 100
+	if r == 0 {
+		return 0 // 避免输出 -0
+	}
+	return r
+}
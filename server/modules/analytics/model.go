@@ -0,0 +1,101 @@
+package analytics
+
+// tradingDaysPerYear 年化夏普、索提诺比率使用的年交易日数
+const tradingDaysPerYear = 252
+
+// PerformanceRequest 交易绩效请求
+type PerformanceRequest struct {
+	StartDate     string   `form:"startDate"` // 按平仓时间过滤
+	EndDate       string   `form:"endDate"`
+	StockCode     string   `form:"stockCode"`
+	Strategy      string   `form:"strategy"`
+	PlanID        string   `form:"planId"`
+	Method        string   `form:"method"`        // 成本计算方法，为空时使用配置默认值
+	InitialEquity *float64 `form:"initialEquity"` // 期初权益，为空时取用户设置的账户权益
+}
+
+// EquityPoint 每日权益曲线上的一个点，金额为本位币
+type EquityPoint struct {
+	Date        string  `json:"date"`
+	PnL         float64 `json:"pnl"`         // 当日平仓盈亏
+	CumPnL      float64 `json:"cumPnl"`      // 累计平仓盈亏
+	Equity      float64 `json:"equity"`      // 期初权益 + 累计盈亏
+	Drawdown    float64 `json:"drawdown"`    // 距此前最高权益的回撤金额
+	DrawdownPct float64 `json:"drawdownPct"` // 回撤占最高权益的比例（%），最高权益不为正时为 0
+	TradeCount  int     `json:"tradeCount"`  // 当日平仓交易笔数
+}
+
+// Performance 交易绩效，由已平仓交易计算，金额均为本位币
+type Performance struct {
+	StartDate     string  `json:"startDate"`
+	EndDate       string  `json:"endDate"`
+	Method        string  `json:"method"`
+	BaseCurrency  string  `json:"baseCurrency"`
+	InitialEquity float64 `json:"initialEquity"`
+	FinalEquity   float64 `json:"finalEquity"`
+	TotalPnL      float64 `json:"totalPnl"`
+
+	TradeCount     int     `json:"tradeCount"`
+	WinCount       int     `json:"winCount"`
+	LossCount      int     `json:"lossCount"`
+	WinRate        float64 `json:"winRate"` // 盈利交易占比（%），盈亏为 0 的交易计入总数
+	AvgWin         float64 `json:"avgWin"`
+	AvgLoss        float64 `json:"avgLoss"` // 亏损交易的平均亏损，为负数
+	LargestWin     float64 `json:"largestWin"`
+	LargestLoss    float64 `json:"largestLoss"`
+	ProfitFactor   float64 `json:"profitFactor"` // 总盈利 / 总亏损，没有亏损交易时为 0
+	Expectancy     float64 `json:"expectancy"`   // 每笔交易的期望盈亏
+	AvgHoldingDays float64 `json:"avgHoldingDays"`
+	MaxWinStreak   int     `json:"maxWinStreak"`  // 最长连续盈利笔数
+	MaxLossStreak  int     `json:"maxLossStreak"` // 最长连续亏损笔数
+
+	MaxDrawdown      float64 `json:"maxDrawdown"`
+	MaxDrawdownPct   float64 `json:"maxDrawdownPct"`
+	MaxDrawdownDays  int     `json:"maxDrawdownDays"`  // 最长回撤持续的自然日数（从高点到收复或统计结束）
+	DrawdownPeakDate string  `json:"drawdownPeakDate"` // 最大回撤的高点日期
+	DrawdownLowDate  string  `json:"drawdownLowDate"`  // 最大回撤的低点日期
+	Sharpe           float64 `json:"sharpe"`           // 年化夏普比率，无风险利率按 0 计算
+	Sortino          float64 `json:"sortino"`          // 年化索提诺比率
+
+	EquityCurve  []EquityPoint `json:"equityCurve"`
+	MissingRates []string      `json:"missingRates"` // 缺少汇率的币种，对应交易不参与统计
+}
+
+// StrategyReportRequest 策略归因请求
+type StrategyReportRequest struct {
+	StartDate string `form:"startDate"` // 按平仓时间过滤
+	EndDate   string `form:"endDate"`
+	StockCode string `form:"stockCode"`
+	Method    string `form:"method"`
+}
+
+// StrategyGroup 一个策略分组的已平仓交易统计，金额为本位币
+// 未设置策略的交易归入策略ID为空的分组
+type StrategyGroup struct {
+	SelectionStrategy     string  `json:"selectionStrategy,omitempty"` // 选股策略ID，按交易策略分组时为空
+	SelectionStrategyName string  `json:"selectionStrategyName,omitempty"`
+	TradingStrategy       string  `json:"tradingStrategy,omitempty"` // 交易策略ID，按选股策略分组时为空
+	TradingStrategyName   string  `json:"tradingStrategyName,omitempty"`
+	TradeCount            int     `json:"tradeCount"`
+	WinCount              int     `json:"winCount"`
+	LossCount             int     `json:"lossCount"`
+	WinRate               float64 `json:"winRate"`
+	TotalPnL              float64 `json:"totalPnl"`
+	AvgPnL                float64 `json:"avgPnl"`
+	ProfitFactor          float64 `json:"profitFactor"` // 没有亏损交易时为 0
+	AvgHoldingDays        float64 `json:"avgHoldingDays"`
+}
+
+// StrategyReport 策略归因报告，各分组按总盈亏从高到低排列
+type StrategyReport struct {
+	StartDate    string          `json:"startDate"`
+	EndDate      string          `json:"endDate"`
+	Method       string          `json:"method"`
+	BaseCurrency string          `json:"baseCurrency"`
+	TradeCount   int             `json:"tradeCount"`
+	TotalPnL     float64         `json:"totalPnl"`
+	BySelection  []StrategyGroup `json:"bySelection"`  // 按选股策略（计划的 strategy）
+	ByTrading    []StrategyGroup `json:"byTrading"`    // 按交易策略（日志的 strategy，未设置时取计划的 tradingStrategy）
+	Combinations []StrategyGroup `json:"combinations"` // 按选股策略 + 交易策略组合
+	MissingRates []string        `json:"missingRates"` // 缺少汇率的币种，对应交易不参与统计
+}
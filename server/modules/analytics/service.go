@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"server/modules/plan"
+	"server/modules/position"
+	"server/modules/strategy"
+	"server/modules/user"
+	"server/utils"
+)
+
+// AnalyticsService 交易绩效分析服务接口
+type AnalyticsService interface {
+	Performance(userID string, req *PerformanceRequest) (*Performance, error)
+	StrategyReport(userID string, req *StrategyReportRequest) (*StrategyReport, error)
+}
+
+// analyticsService 交易绩效分析服务实现
+type analyticsService struct {
+	positionService position.PositionService
+	planService     plan.PlanService
+	strategyService strategy.StrategyService
+	userService     user.UserService
+}
+
+// NewAnalyticsService 创建交易绩效分析服务
+func NewAnalyticsService() AnalyticsService {
+	return &analyticsService{
+		positionService: position.NewPositionService(),
+		planService:     plan.NewPlanService(),
+		strategyService: strategy.NewStrategyService(),
+		userService:     user.NewUserService(),
+	}
+}
+
+// listTrades 按平仓时间获取已平仓交易，结束日期只有日期时包含当天全部交易
+func (s *analyticsService) listTrades(userID string, req *position.TradeListRequest) (*position.TradeListResponse, error) {
+	if len(req.EndDate) == len("2006-01-02") {
+		req.EndDate += " 23:59:59"
+	}
+	return s.positionService.ListTrades(userID, req)
+}
+
+// Performance 由交易日志回放得到的已平仓交易计算绩效指标和每日权益曲线
+func (s *analyticsService) Performance(userID string, req *PerformanceRequest) (*Performance, error) {
+	trades, err := s.listTrades(userID, &position.TradeListRequest{
+		StockCode: req.StockCode,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Strategy:  req.Strategy,
+		PlanID:    req.PlanID,
+		Method:    req.Method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	perf := &Performance{
+		StartDate:    datePart(req.StartDate),
+		EndDate:      datePart(req.EndDate),
+		Method:       trades.Method,
+		BaseCurrency: trades.BaseCurrency,
+		MissingRates: trades.MissingRates,
+	}
+	if req.InitialEquity != nil {
+		perf.InitialEquity = *req.InitialEquity
+	} else {
+		settings, err := s.userService.GetSettings(userID)
+		if err != nil {
+			return nil, fmt.Errorf("获取账户设置失败: %w", err)
+		}
+		perf.InitialEquity = settings.AccountEquity
+	}
+
+	// 缺少汇率的交易无法换算为本位币，不参与统计
+	converted := make([]position.Trade, 0, len(trades.Items))
+	for _, t := range trades.Items {
+		if t.FxRate > 0 {
+			converted = append(converted, t)
+		}
+	}
+	sort.SliceStable(converted, func(i, j int) bool {
+		return converted[i].CloseTime < converted[j].CloseTime
+	})
+
+	summarizeTrades(perf, converted)
+	perf.EquityCurve = buildEquityCurve(converted, perf.InitialEquity, perf.StartDate, perf.EndDate)
+	applyDrawdown(perf, perf.EquityCurve)
+	applyRatios(perf, perf.EquityCurve)
+	perf.FinalEquity = round2(perf.InitialEquity + perf.TotalPnL)
+
+	utils.LogDebug("绩效计算完成，共 %d 笔已平仓交易，权益曲线 %d 天", perf.TradeCount, len(perf.EquityCurve))
+	return perf, nil
+}
+
+// StrategyReport 按选股策略和交易策略归因已平仓交易的盈亏
+// 选股策略取交易关联计划的 strategy；交易策略取日志的 strategy，未设置时取计划的 tradingStrategy
+func (s *analyticsService) StrategyReport(userID string, req *StrategyReportRequest) (*StrategyReport, error) {
+	trades, err := s.listTrades(userID, &position.TradeListRequest{
+		StockCode: req.StockCode,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Method:    req.Method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plans := map[string]*plan.Plan{}
+	attributed := make([]attributedTrade, 0, len(trades.Items))
+	report := &StrategyReport{
+		StartDate:    datePart(req.StartDate),
+		EndDate:      datePart(req.EndDate),
+		Method:       trades.Method,
+		BaseCurrency: trades.BaseCurrency,
+		MissingRates: trades.MissingRates,
+	}
+	for _, t := range trades.Items {
+		// 缺少汇率的交易无法换算为本位币，不参与统计
+		if t.FxRate == 0 {
+			continue
+		}
+		at := attributedTrade{Trade: t, Trading: t.Strategy}
+		if t.PlanID != "" {
+			p, ok := plans[t.PlanID]
+			if !ok {
+				// 计划可能已被删除，按未关联计划处理
+				p, _ = s.planService.GetPlan(userID, t.PlanID)
+				plans[t.PlanID] = p
+			}
+			if p != nil {
+				at.Selection = p.Strategy
+				if at.Trading == "" {
+					at.Trading = p.TradingStrategy
+				}
+			}
+		}
+		attributed = append(attributed, at)
+		report.TradeCount++
+		report.TotalPnL += t.BasePnL
+	}
+	report.TotalPnL = round2(report.TotalPnL)
+
+	names := map[string]string{}
+	strategies, err := s.strategyService.ListStrategies(userID, &strategy.StrategyListRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range strategies.Items {
+		names[st.ID] = st.Name
+	}
+
+	report.BySelection = groupTrades(attributed, names, true, false)
+	report.ByTrading = groupTrades(attributed, names, false, true)
+	report.Combinations = groupTrades(attributed, names, true, true)
+	return report, nil
+}
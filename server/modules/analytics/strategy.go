@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"sort"
+
+	"server/modules/position"
+)
+
+// unassignedName 未设置策略的分组名称
+const unassignedName = "未设置"
+
+// attributedTrade 已确定选股策略和交易策略的交易
+type attributedTrade struct {
+	position.Trade
+	Selection string
+	Trading   string
+}
+
+// groupAccumulator 分组统计的累加器
+type groupAccumulator struct {
+	group     StrategyGroup
+	grossWin  float64
+	grossLoss float64
+	holding   float64
+}
+
+func (a *groupAccumulator) add(t attributedTrade) {
+	a.group.TradeCount++
+	a.group.TotalPnL += t.BasePnL
+	a.holding += t.HoldingDays
+	switch {
+	case t.BasePnL > 0:
+		a.group.WinCount++
+		a.grossWin += t.BasePnL
+	case t.BasePnL < 0:
+		a.group.LossCount++
+		a.grossLoss += t.BasePnL
+	}
+}
+
+func (a *groupAccumulator) result() StrategyGroup {
+	g := a.group
+	g.WinRate = round2(float64(g.WinCount) / float64(g.TradeCount) * 100)
+	g.AvgPnL = round2(g.TotalPnL / float64(g.TradeCount))
+	g.AvgHoldingDays = round2(a.holding / float64(g.TradeCount))
+	if a.grossLoss < 0 {
+		g.ProfitFactor = round2(a.grossWin / -a.grossLoss)
+	}
+	g.TotalPnL = round2(g.TotalPnL)
+	return g
+}
+
+// groupTrades 按选股策略和/或交易策略分组统计，names 为策略ID到名称的映射
+func groupTrades(trades []attributedTrade, names map[string]string, bySelection, byTrading bool) []StrategyGroup {
+	index := map[[2]string]*groupAccumulator{}
+	order := [][2]string{}
+	for _, t := range trades {
+		var selection, trading string
+		if bySelection {
+			selection = t.Selection
+		}
+		if byTrading {
+			trading = t.Trading
+		}
+		k := [2]string{selection, trading}
+		acc, ok := index[k]
+		if !ok {
+			acc = &groupAccumulator{group: StrategyGroup{SelectionStrategy: selection, TradingStrategy: trading}}
+			index[k] = acc
+			order = append(order, k)
+		}
+		acc.add(t)
+	}
+
+	groups := make([]StrategyGroup, 0, len(order))
+	for _, k := range order {
+		g := index[k].result()
+		if bySelection {
+			g.SelectionStrategyName = strategyName(names, g.SelectionStrategy)
+		}
+		if byTrading {
+			g.TradingStrategyName = strategyName(names, g.TradingStrategy)
+		}
+		groups = append(groups, g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].TotalPnL > groups[j].TotalPnL
+	})
+	return groups
+}
+
+// strategyName 策略ID对应的名称，策略已删除时返回ID本身
+func strategyName(names map[string]string, id string) string {
+	if id == "" {
+		return unassignedName
+	}
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
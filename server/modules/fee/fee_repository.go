@@ -0,0 +1,210 @@
+package fee
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+	"time"
+)
+
+// FeeRepository 费率表数据访问层
+type FeeRepository struct{}
+
+// NewFeeRepository 创建费率表仓库
+func NewFeeRepository() *FeeRepository {
+	return &FeeRepository{}
+}
+
+const scheduleColumns = `id, user_id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side,
+	transfer_fee_rate, regulatory_fee_rate, sec_fee_rate, created_at, updated_at`
+
+// visibleCondition 用户可见的费率表：内置费率表和自己创建的费率表
+const visibleCondition = "(user_id IS NULL OR user_id = ?)"
+
+// Create 创建费率表
+func (r *FeeRepository) Create(s *Schedule) error {
+	query := fmt.Sprintf(`INSERT INTO fee_schedules (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, scheduleColumns)
+	_, err := storage.GetDB().Exec(query,
+		s.ID, s.UserID, s.Region, s.Category, s.Name, s.CommissionRate, s.MinCommission, s.StampDutyRate, s.StampDutySide,
+		s.TransferFeeRate, s.RegulatoryFeeRate, s.SecFeeRate, s.CreatedAt, s.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID 根据ID获取用户可见的费率表
+func (r *FeeRepository) GetByID(userID, id string) (*Schedule, error) {
+	query := fmt.Sprintf("SELECT %s FROM fee_schedules WHERE id = ? AND %s", scheduleColumns, visibleCondition)
+	s, err := scanSchedule(storage.GetDB().QueryRow(query, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("费率表不存在")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Exists 判断费率表ID是否已被占用，费率表ID在所有用户间唯一
+func (r *FeeRepository) Exists(id string) (bool, error) {
+	var count int
+	err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM fee_schedules WHERE id = ?", id).Scan(&count)
+	return count > 0, err
+}
+
+// FindOwn 查找用户自己在地区和板块上创建的费率表，没有时返回 nil
+func (r *FeeRepository) FindOwn(userID, region, category string) (*Schedule, error) {
+	query := fmt.Sprintf("SELECT %s FROM fee_schedules WHERE user_id = ? AND region = ? AND category = ?", scheduleColumns)
+	s, err := scanSchedule(storage.GetDB().QueryRow(query, userID, region, category))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Match 查找适用于地区和板块的费率表，用户自己的费率表优先于内置费率表，
+// 同一归属下板块没有单独设置时使用地区默认费率表，都没有时返回 nil
+func (r *FeeRepository) Match(userID, region, category string) (*Schedule, error) {
+	query := fmt.Sprintf(`SELECT %s FROM fee_schedules WHERE %s AND region = ? AND category IN (?, '')
+		ORDER BY user_id IS NULL ASC, category DESC LIMIT 1`, scheduleColumns, visibleCondition)
+	s, err := scanSchedule(storage.GetDB().QueryRow(query, userID, region, category))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// List 获取用户可见的费率表列表，按地区和板块排序
+func (r *FeeRepository) List(userID, region string) ([]Schedule, error) {
+	where := []string{visibleCondition}
+	args := []interface{}{userID}
+	if region != "" {
+		where = append(where, "region = ?")
+		args = append(args, region)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM fee_schedules WHERE %s ORDER BY region ASC, category ASC, user_id IS NULL ASC",
+		scheduleColumns, strings.Join(where, " AND "))
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []Schedule{}
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// Update 更新用户自己创建的费率表
+func (r *FeeRepository) Update(userID, id string, req *ScheduleUpdateRequest) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	set := func(column string, value interface{}) {
+		setParts = append(setParts, column+" = ?")
+		args = append(args, value)
+	}
+	if req.Name != nil {
+		set("name", *req.Name)
+	}
+	if req.CommissionRate != nil {
+		set("commission_rate", *req.CommissionRate)
+	}
+	if req.MinCommission != nil {
+		set("min_commission", *req.MinCommission)
+	}
+	if req.StampDutyRate != nil {
+		set("stamp_duty_rate", *req.StampDutyRate)
+	}
+	if req.StampDutySide != nil {
+		set("stamp_duty_side", *req.StampDutySide)
+	}
+	if req.TransferFeeRate != nil {
+		set("transfer_fee_rate", *req.TransferFeeRate)
+	}
+	if req.RegulatoryFeeRate != nil {
+		set("regulatory_fee_rate", *req.RegulatoryFeeRate)
+	}
+	if req.SecFeeRate != nil {
+		set("sec_fee_rate", *req.SecFeeRate)
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("没有要更新的字段")
+	}
+
+	set("updated_at", time.Now())
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf("UPDATE fee_schedules SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
+	result, err := storage.GetDB().Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("费率表不存在")
+	}
+
+	return nil
+}
+
+// Delete 删除用户自己创建的费率表
+func (r *FeeRepository) Delete(userID, id string) error {
+	result, err := storage.GetDB().Exec("DELETE FROM fee_schedules WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("费率表不存在")
+	}
+
+	return nil
+}
+
+// rowScanner sql.Row 和 sql.Rows 的公共扫描接口
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSchedule 扫描一行费率表数据
+func scanSchedule(row rowScanner) (*Schedule, error) {
+	s := &Schedule{}
+	var userID, stampDutySide sql.NullString
+	err := row.Scan(
+		&s.ID, &userID, &s.Region, &s.Category, &s.Name, &s.CommissionRate, &s.MinCommission, &s.StampDutyRate, &stampDutySide,
+		&s.TransferFeeRate, &s.RegulatoryFeeRate, &s.SecFeeRate, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.UserID = userID.String
+	s.StampDutySide = stampDutySide.String
+	return s, nil
+}
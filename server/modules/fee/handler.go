@@ -0,0 +1,136 @@
+package fee
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeeHandler 费率处理器
+type FeeHandler struct {
+	feeService FeeService
+}
+
+// NewFeeHandler 创建费率处理器
+func NewFeeHandler() *FeeHandler {
+	return &FeeHandler{
+		feeService: NewFeeService(),
+	}
+}
+
+// RegisterFeeRoutes 注册费率路由
+func RegisterFeeRoutes(r *gin.RouterGroup) {
+	handler := NewFeeHandler()
+
+	g := r.Group("/fees")
+	{
+		g.POST("/create", handler.createSchedule)
+		g.GET("/getList", handler.listSchedules)
+		g.GET("/getDetail/:id", handler.getSchedule)
+		g.PUT("/update/:id", handler.updateSchedule)
+		g.DELETE("/delete/:id", handler.deleteSchedule)
+		g.GET("/calculate", handler.calculate)
+	}
+}
+
+// CreateSchedule 创建费率表
+func (h *FeeHandler) createSchedule(c *gin.Context) {
+	var req ScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	schedule, err := h.feeService.CreateSchedule(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, schedule)
+}
+
+// GetSchedule 获取费率表详情
+func (h *FeeHandler) getSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "费率表ID不能为空")
+		return
+	}
+
+	schedule, err := h.feeService.GetSchedule(middleware.GetCurrentUserID(c), id)
+	if err != nil {
+		handler.Error(c, handler.CodeNotFound, err.Error())
+		return
+	}
+
+	handler.Success(c, schedule)
+}
+
+// ListSchedules 获取费率表列表
+func (h *FeeHandler) listSchedules(c *gin.Context) {
+	response, err := h.feeService.ListSchedules(middleware.GetCurrentUserID(c), c.Query("region"))
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, response)
+}
+
+// UpdateSchedule 更新费率表
+func (h *FeeHandler) updateSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "费率表ID不能为空")
+		return
+	}
+
+	var req ScheduleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	schedule, err := h.feeService.UpdateSchedule(middleware.GetCurrentUserID(c), id, &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, schedule)
+}
+
+// DeleteSchedule 删除费率表
+func (h *FeeHandler) deleteSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "费率表ID不能为空")
+		return
+	}
+
+	if err := h.feeService.DeleteSchedule(middleware.GetCurrentUserID(c), id); err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, gin.H{"id": id})
+}
+
+// Calculate 按费率表试算一笔成交的费用
+func (h *FeeHandler) calculate(c *gin.Context) {
+	var req CalculateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	breakdown, err := h.feeService.Calculate(middleware.GetCurrentUserID(c), req.Region, req.Category, req.Type, req.Price, req.Quantity)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, breakdown)
+}
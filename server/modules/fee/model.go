@@ -0,0 +1,83 @@
+package fee
+
+import "time"
+
+// 印花税收取方向
+const (
+	SideSell = "sell" // 仅卖出收取
+	SideBuy  = "buy"  // 仅买入收取
+	SideBoth = "both" // 买卖双边收取
+)
+
+// Schedule 费率表，按地区和板块（前端 stockCategoryConfig）匹配
+// 内置费率表所有用户共用，用户创建的费率表只对自己生效且优先于内置费率表
+// 板块为空的费率表适用于该地区没有单独设置的全部板块
+type Schedule struct {
+	ID                string    `json:"id" db:"id"`
+	UserID            string    `json:"userId" db:"user_id"` // 为空表示内置费率表
+	Region            string    `json:"region" db:"region"`
+	Category          string    `json:"category" db:"category"`
+	Name              string    `json:"name" db:"name"`
+	CommissionRate    float64   `json:"commissionRate" db:"commission_rate"`        // 佣金费率，双边
+	MinCommission     float64   `json:"minCommission" db:"min_commission"`          // 单笔最低佣金
+	StampDutyRate     float64   `json:"stampDutyRate" db:"stamp_duty_rate"`         // 印花税率
+	StampDutySide     string    `json:"stampDutySide" db:"stamp_duty_side"`         // 印花税收取方向：sell / buy / both
+	TransferFeeRate   float64   `json:"transferFeeRate" db:"transfer_fee_rate"`     // 过户费率，双边
+	RegulatoryFeeRate float64   `json:"regulatoryFeeRate" db:"regulatory_fee_rate"` // 交易所及监管费用合计费率，双边
+	SecFeeRate        float64   `json:"secFeeRate" db:"sec_fee_rate"`               // 美股 SEC 费率，仅卖出
+	CreatedAt         time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt         time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Breakdown 单笔成交的费用明细
+type Breakdown struct {
+	ScheduleID    string  `json:"scheduleId"` // 计算所用的费率表，手动填写费用时为空
+	Commission    float64 `json:"commission"`
+	StampDuty     float64 `json:"stampDuty"`
+	TransferFee   float64 `json:"transferFee"`
+	RegulatoryFee float64 `json:"regulatoryFee"`
+	SecFee        float64 `json:"secFee"`
+	Total         float64 `json:"total"`
+}
+
+// ScheduleCreateRequest 创建费率表请求，ID 为空时自动生成
+type ScheduleCreateRequest struct {
+	ID                string  `json:"id"`
+	Region            string  `json:"region" binding:"required"`
+	Category          string  `json:"category"`
+	Name              string  `json:"name" binding:"required"`
+	CommissionRate    float64 `json:"commissionRate" binding:"min=0"`
+	MinCommission     float64 `json:"minCommission" binding:"min=0"`
+	StampDutyRate     float64 `json:"stampDutyRate" binding:"min=0"`
+	StampDutySide     string  `json:"stampDutySide" binding:"omitempty,oneof=sell buy both"` // 默认仅卖出
+	TransferFeeRate   float64 `json:"transferFeeRate" binding:"min=0"`
+	RegulatoryFeeRate float64 `json:"regulatoryFeeRate" binding:"min=0"`
+	SecFeeRate        float64 `json:"secFeeRate" binding:"min=0"`
+}
+
+// ScheduleUpdateRequest 更新费率表请求，地区和板块不可修改
+type ScheduleUpdateRequest struct {
+	Name              *string  `json:"name,omitempty"`
+	CommissionRate    *float64 `json:"commissionRate,omitempty" binding:"omitempty,min=0"`
+	MinCommission     *float64 `json:"minCommission,omitempty" binding:"omitempty,min=0"`
+	StampDutyRate     *float64 `json:"stampDutyRate,omitempty" binding:"omitempty,min=0"`
+	StampDutySide     *string  `json:"stampDutySide,omitempty" binding:"omitempty,oneof=sell buy both"`
+	TransferFeeRate   *float64 `json:"transferFeeRate,omitempty" binding:"omitempty,min=0"`
+	RegulatoryFeeRate *float64 `json:"regulatoryFeeRate,omitempty" binding:"omitempty,min=0"`
+	SecFeeRate        *float64 `json:"secFeeRate,omitempty" binding:"omitempty,min=0"`
+}
+
+// ScheduleListResponse 费率表列表响应
+type ScheduleListResponse struct {
+	Items []Schedule `json:"list"`
+	Total int        `json:"total"`
+}
+
+// CalculateRequest 费用试算请求
+type CalculateRequest struct {
+	Region   string  `form:"region" binding:"required"`
+	Category string  `form:"category"`
+	Type     string  `form:"type" binding:"required,oneof=buy sell"`
+	Price    float64 `form:"price" binding:"required,gt=0"`
+	Quantity int     `form:"quantity" binding:"required,gt=0"`
+}
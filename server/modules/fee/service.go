@@ -0,0 +1,201 @@
+package fee
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"server/market"
+	"server/utils"
+)
+
+// FeeService 费率服务接口
+type FeeService interface {
+	CreateSchedule(userID string, req *ScheduleCreateRequest) (*Schedule, error)
+	GetSchedule(userID, id string) (*Schedule, error)
+	ListSchedules(userID, region string) (*ScheduleListResponse, error)
+	UpdateSchedule(userID, id string, req *ScheduleUpdateRequest) (*Schedule, error)
+	DeleteSchedule(userID, id string) error
+	Calculate(userID, region, category, tradeType string, price float64, quantity int) (*Breakdown, error)
+}
+
+// feeService 费率服务实现
+type feeService struct {
+	feeRepo *FeeRepository
+}
+
+// NewFeeService 创建费率服务
+func NewFeeService() FeeService {
+	return &feeService{
+		feeRepo: NewFeeRepository(),
+	}
+}
+
+// validateRegion 校验地区
+func validateRegion(region string) error {
+	for _, r := range market.Regions {
+		if r == region {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的地区: %s", region)
+}
+
+// CreateSchedule 创建用户自己的费率表，每个用户在同一地区和板块只能有一个费率表
+func (s *feeService) CreateSchedule(userID string, req *ScheduleCreateRequest) (*Schedule, error) {
+	if err := validateRegion(req.Region); err != nil {
+		return nil, err
+	}
+
+	id := req.ID
+	if id == "" {
+		id = "fee_" + utils.GenerateID()
+	}
+	exists, err := s.feeRepo.Exists(id)
+	if err != nil {
+		return nil, fmt.Errorf("检查费率表ID失败: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("费率表ID %s 已存在", id)
+	}
+	existing, err := s.feeRepo.FindOwn(userID, req.Region, req.Category)
+	if err != nil {
+		return nil, fmt.Errorf("查询费率表失败: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("地区 %s 板块 %s 已有费率表 %s", req.Region, req.Category, existing.ID)
+	}
+
+	side := req.StampDutySide
+	if side == "" {
+		side = SideSell
+	}
+
+	schedule := &Schedule{
+		ID:                id,
+		UserID:            userID,
+		Region:            req.Region,
+		Category:          req.Category,
+		Name:              req.Name,
+		CommissionRate:    req.CommissionRate,
+		MinCommission:     req.MinCommission,
+		StampDutyRate:     req.StampDutyRate,
+		StampDutySide:     side,
+		TransferFeeRate:   req.TransferFeeRate,
+		RegulatoryFeeRate: req.RegulatoryFeeRate,
+		SecFeeRate:        req.SecFeeRate,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	if err := s.feeRepo.Create(schedule); err != nil {
+		return nil, fmt.Errorf("创建费率表失败: %w", err)
+	}
+
+	return s.feeRepo.GetByID(userID, id)
+}
+
+// GetSchedule 获取费率表详情
+func (s *feeService) GetSchedule(userID, id string) (*Schedule, error) {
+	schedule, err := s.feeRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取费率表失败: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules 获取费率表列表
+func (s *feeService) ListSchedules(userID, region string) (*ScheduleListResponse, error) {
+	schedules, err := s.feeRepo.List(userID, region)
+	if err != nil {
+		return nil, fmt.Errorf("获取费率表列表失败: %w", err)
+	}
+
+	return &ScheduleListResponse{
+		Items: schedules,
+		Total: len(schedules),
+	}, nil
+}
+
+// ownSchedule 获取用户自己创建的费率表，内置费率表不能修改或删除
+func (s *feeService) ownSchedule(userID, id string) (*Schedule, error) {
+	schedule, err := s.feeRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.UserID == "" {
+		return nil, fmt.Errorf("内置费率表 %s 不能修改或删除，可以创建同地区和板块的费率表覆盖", schedule.Name)
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule 更新费率表，已有日志的费用需调用重新计算接口才会更新
+func (s *feeService) UpdateSchedule(userID, id string, req *ScheduleUpdateRequest) (*Schedule, error) {
+	if _, err := s.ownSchedule(userID, id); err != nil {
+		return nil, err
+	}
+	if err := s.feeRepo.Update(userID, id, req); err != nil {
+		return nil, fmt.Errorf("更新费率表失败: %w", err)
+	}
+
+	updated, err := s.feeRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取更新后的费率表失败: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteSchedule 删除费率表
+func (s *feeService) DeleteSchedule(userID, id string) error {
+	if _, err := s.ownSchedule(userID, id); err != nil {
+		return err
+	}
+	if err := s.feeRepo.Delete(userID, id); err != nil {
+		return fmt.Errorf("删除费率表失败: %w", err)
+	}
+	utils.LogInfo("费率表已删除，用户: %s, ID: %s", userID, id)
+
+	return nil
+}
+
+// Calculate 按用户适用的地区和板块费率表计算一笔成交的费用，没有适用的费率表时费用为 0
+func (s *feeService) Calculate(userID, region, category, tradeType string, price float64, quantity int) (*Breakdown, error) {
+	schedule, err := s.feeRepo.Match(userID, region, category)
+	if err != nil {
+		return nil, fmt.Errorf("查询费率表失败: %w", err)
+	}
+	if schedule == nil {
+		return &Breakdown{}, nil
+	}
+	return Compute(schedule, tradeType, price, quantity), nil
+}
+
+// Compute 按费率表计算成交费用，各项费用分别保留两位小数
+func Compute(schedule *Schedule, tradeType string, price float64, quantity int) *Breakdown {
+	b := &Breakdown{ScheduleID: schedule.ID}
+	amount := price * float64(quantity)
+	if amount <= 0 {
+		return b
+	}
+
+	if schedule.CommissionRate > 0 || schedule.MinCommission > 0 {
+		b.Commission = round2(math.Max(amount*schedule.CommissionRate, schedule.MinCommission))
+	}
+	if schedule.StampDutySide == SideBoth || schedule.StampDutySide == tradeType {
+		b.StampDuty = round2(amount * schedule.StampDutyRate)
+	}
+	b.TransferFee = round2(amount * schedule.TransferFeeRate)
+	b.RegulatoryFee = round2(amount * schedule.RegulatoryFeeRate)
+	if tradeType == SideSell {
+		b.SecFee = round2(amount * schedule.SecFeeRate)
+	}
+	b.Total = round2(b.Commission + b.StampDuty + b.TransferFee + b.RegulatoryFee + b.SecFee)
+	return b
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,94 @@
+package fx
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"server/utils"
+)
+
+// Converter 按用户可用的汇率将各币种金额换算为本位币，批量换算时缓存已查询的汇率
+type Converter struct {
+	userID  string
+	base    string
+	repo    *FxRepository
+	cache   map[string]float64
+	missing map[string]bool
+}
+
+// NewConverter 创建用户换算到 base 币种的换算器
+func NewConverter(userID, base string) *Converter {
+	return &Converter{
+		userID:  userID,
+		base:    strings.ToUpper(base),
+		repo:    NewFxRepository(),
+		cache:   map[string]float64{},
+		missing: map[string]bool{},
+	}
+}
+
+// Base 本位币
+func (c *Converter) Base() string {
+	return c.base
+}
+
+// Rate 1 单位 currency 可兑换的本位币数量，取不晚于 date 的最近汇率，date 为空时取最新汇率
+// 币种为空或与本位币相同时为 1；没有直接汇率时使用反向汇率的倒数；都没有时返回 false
+func (c *Converter) Rate(currency, date string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == c.base {
+		return 1, true
+	}
+	if len(date) > 10 {
+		date = date[:10]
+	}
+
+	key := currency + "|" + date
+	rate, ok := c.cache[key]
+	if !ok {
+		rate = c.lookup(currency, date)
+		c.cache[key] = rate
+	}
+	if rate <= 0 {
+		c.missing[currency] = true
+		return 0, false
+	}
+	return rate, true
+}
+
+// lookup 查询直接汇率或反向汇率
+func (c *Converter) lookup(currency, date string) float64 {
+	rate, err := c.repo.Lookup(c.userID, currency, c.base, date)
+	if err == nil && rate <= 0 {
+		var inverse float64
+		inverse, err = c.repo.Lookup(c.userID, c.base, currency, date)
+		if inverse > 0 {
+			rate = 1 / inverse
+		}
+	}
+	if err != nil {
+		utils.LogWarning("查询汇率失败，%s/%s %s: %v", currency, c.base, date, err)
+		return 0
+	}
+	return rate
+}
+
+// Convert 将金额换算为本位币并保留两位小数，缺少汇率时返回 false
+func (c *Converter) Convert(amount float64, currency, date string) (float64, bool) {
+	rate, ok := c.Rate(currency, date)
+	if !ok {
+		return 0, false
+	}
+	return math.Round(amount*rate*100) / 100, true
+}
+
+// Missing 换算过程中缺少汇率的币种，按字母排序
+func (c *Converter) Missing() []string {
+	currencies := make([]string, 0, len(c.missing))
+	for currency := range c.missing {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
@@ -0,0 +1,169 @@
+package fx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportErrors 导入结果中最多返回的错误行数
+const maxImportErrors = 50
+
+// columnAliases CSV 表头别名，表头不区分大小写
+var columnAliases = map[string][]string{
+	"from": {"from", "from_currency", "fromcurrency", "base", "currency", "币种", "源币种"},
+	"to":   {"to", "to_currency", "tocurrency", "quote", "目标币种"},
+	"date": {"date", "rate_date", "ratedate", "日期"},
+	"rate": {"rate", "close", "汇率", "中间价"},
+}
+
+// dateLayouts 支持的日期格式
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"20060102",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+}
+
+// parseDate 解析汇率日期并统一为 YYYY-MM-DD
+func parseDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("无法识别的日期格式: %s", value)
+}
+
+// normalizeCurrency 币种代码统一为大写并校验为三位字母
+func normalizeCurrency(value string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(value))
+	if len(c) != 3 {
+		return "", fmt.Errorf("无效的币种代码: %s", value)
+	}
+	for _, ch := range c {
+		if ch < 'A' || ch > 'Z' {
+			return "", fmt.Errorf("无效的币种代码: %s", value)
+		}
+	}
+	return c, nil
+}
+
+// mapColumns 根据表头确定各字段所在列
+func mapColumns(header []string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for field, aliases := range columnAliases {
+			if _, ok := index[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					index[field] = i
+					break
+				}
+			}
+		}
+	}
+	for _, field := range []string{"from", "date", "rate"} {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("CSV 缺少 %s 列", field)
+		}
+	}
+	return index, nil
+}
+
+// parseCSV 解析汇率 CSV，返回有效的汇率、总行数和出错的行
+func parseCSV(r io.Reader, defaultTo, source string) ([]Rate, int, []ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+	index, err := mapColumns(header)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if _, ok := index["to"]; !ok && defaultTo == "" {
+		return nil, 0, nil, fmt.Errorf("CSV 没有目标币种列时需要指定 toCurrency")
+	}
+
+	var rates []Rate
+	var errs []ImportError
+	total := 0
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			errs = append(errs, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		total++
+
+		rate, err := parseRecord(record, index, defaultTo, source)
+		if err != nil {
+			errs = append(errs, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		rates = append(rates, *rate)
+	}
+
+	return rates, total, errs, nil
+}
+
+// parseRecord 解析一行汇率数据
+func parseRecord(record []string, index map[string]int, defaultTo, source string) (*Rate, error) {
+	field := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rate := &Rate{Source: source}
+	var err error
+	if rate.FromCurrency, err = normalizeCurrency(field("from")); err != nil {
+		return nil, err
+	}
+	to := field("to")
+	if to == "" {
+		to = defaultTo
+	}
+	if rate.ToCurrency, err = normalizeCurrency(to); err != nil {
+		return nil, err
+	}
+	if rate.FromCurrency == rate.ToCurrency {
+		return nil, fmt.Errorf("源币种和目标币种相同: %s", rate.FromCurrency)
+	}
+	if rate.RateDate, err = parseDate(field("date")); err != nil {
+		return nil, err
+	}
+
+	v := strings.ReplaceAll(field("rate"), ",", "")
+	rate.Rate, err = strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(rate.Rate) || math.IsInf(rate.Rate, 0) {
+		return nil, fmt.Errorf("rate 不是有效数字: %s", v)
+	}
+	if rate.Rate <= 0 {
+		return nil, fmt.Errorf("汇率必须大于0")
+	}
+	return rate, nil
+}
@@ -0,0 +1,136 @@
+package fx
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+	"time"
+)
+
+// FxRepository 汇率数据访问层
+type FxRepository struct{}
+
+// NewFxRepository 创建汇率仓库
+func NewFxRepository() *FxRepository {
+	return &FxRepository{}
+}
+
+// visibleCondition 用户可见的汇率：公共汇率和自己录入的汇率
+const visibleCondition = "(user_id IS NULL OR user_id = ?)"
+
+// Upsert 在事务中批量写入用户的汇率，同一币种对和日期已存在时覆盖
+func (r *FxRepository) Upsert(userID string, rates []Rate) error {
+	tx, err := storage.GetDB().SQL.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO fx_rates (
+		user_id, from_currency, to_currency, rate_date, rate, source, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(user_id, from_currency, to_currency, rate_date) DO UPDATE SET
+		rate = excluded.rate, source = excluded.source, updated_at = excluded.updated_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, rate := range rates {
+		if _, err := stmt.Exec(userID, rate.FromCurrency, rate.ToCurrency, rate.RateDate, rate.Rate, rate.Source, now, now); err != nil {
+			return fmt.Errorf("%s/%s %s: %w", rate.FromCurrency, rate.ToCurrency, rate.RateDate, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// List 查询用户可见的汇率，按币种对和日期排序，同一日期自己录入的汇率在前
+func (r *FxRepository) List(userID string, req *RateListRequest) ([]Rate, error) {
+	where := []string{visibleCondition}
+	args := []interface{}{userID}
+
+	if req.FromCurrency != "" {
+		where = append(where, "from_currency = ?")
+		args = append(args, strings.ToUpper(req.FromCurrency))
+	}
+	if req.ToCurrency != "" {
+		where = append(where, "to_currency = ?")
+		args = append(args, strings.ToUpper(req.ToCurrency))
+	}
+	if req.StartDate != "" {
+		where = append(where, "rate_date >= ?")
+		args = append(args, req.StartDate)
+	}
+	if req.EndDate != "" {
+		where = append(where, "rate_date <= ?")
+		args = append(args, req.EndDate)
+	}
+
+	query := fmt.Sprintf(`SELECT user_id, from_currency, to_currency, rate_date, rate, source, created_at, updated_at
+		FROM fx_rates WHERE %s ORDER BY from_currency ASC, to_currency ASC, rate_date DESC, user_id IS NULL ASC`,
+		strings.Join(where, " AND "))
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []Rate{}
+	for rows.Next() {
+		var rate Rate
+		var userID, source sql.NullString
+		if err := rows.Scan(&userID, &rate.FromCurrency, &rate.ToCurrency, &rate.RateDate, &rate.Rate, &source,
+			&rate.CreatedAt, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rate.UserID = userID.String
+		rate.Source = source.String
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Lookup 查询用户可用的不晚于 date 的最近一条汇率，同一日期自己录入的汇率优先于公共汇率，
+// date 为空时取最新汇率，不存在时返回 0
+func (r *FxRepository) Lookup(userID, from, to, date string) (float64, error) {
+	where := visibleCondition + " AND from_currency = ? AND to_currency = ?"
+	args := []interface{}{userID, from, to}
+	if date != "" {
+		where += " AND rate_date <= ?"
+		args = append(args, date)
+	}
+
+	var rate float64
+	err := storage.GetDB().QueryRow(fmt.Sprintf(
+		"SELECT rate FROM fx_rates WHERE %s ORDER BY rate_date DESC, user_id IS NULL ASC LIMIT 1", where), args...).Scan(&rate)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return rate, err
+}
+
+// Delete 删除用户自己录入的一条汇率
+func (r *FxRepository) Delete(userID, from, to, date string) error {
+	result, err := storage.GetDB().Exec(`DELETE FROM fx_rates
+		WHERE user_id = ? AND from_currency = ? AND to_currency = ? AND rate_date = ?`, userID, from, to, date)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("汇率不存在，公共汇率不能删除")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package fx
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterFxRoutes 注册汇率路由
+func RegisterFxRoutes(r *gin.RouterGroup) {
+	fxService := NewFxService()
+
+	g := r.Group("/fx")
+	{
+		g.POST("/save", func(c *gin.Context) {
+			var req RateSaveRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			rate, err := fxService.SaveRate(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, rate)
+		})
+
+		g.POST("/import", func(c *gin.Context) {
+			var req RateImportRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			result, err := fxService.ImportCSV(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
+
+		g.GET("/getList", func(c *gin.Context) {
+			var req RateListRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			response, err := fxService.ListRates(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.DELETE("/delete", func(c *gin.Context) {
+			var req RateDeleteRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			if err := fxService.DeleteRate(middleware.GetCurrentUserID(c), &req); err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, req)
+		})
+
+		g.GET("/convert", func(c *gin.Context) {
+			var req ConvertRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			result, err := fxService.Convert(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
+	}
+}
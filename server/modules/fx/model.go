@@ -0,0 +1,87 @@
+package fx
+
+import "time"
+
+// Rate 汇率，1 单位 FromCurrency 可兑换 Rate 单位 ToCurrency
+// 公共汇率所有用户共用，用户录入的汇率只对自己生效
+type Rate struct {
+	UserID       string    `json:"userId" db:"user_id"` // 为空表示公共汇率
+	FromCurrency string    `json:"fromCurrency" db:"from_currency"`
+	ToCurrency   string    `json:"toCurrency" db:"to_currency"`
+	RateDate     string    `json:"rateDate" db:"rate_date"` // 生效日期 YYYY-MM-DD，换算时取不晚于交易日的最近汇率
+	Rate         float64   `json:"rate" db:"rate"`
+	Source       string    `json:"source" db:"source"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// RateSaveRequest 手动录入汇率请求，同一币种对和日期已存在时覆盖
+type RateSaveRequest struct {
+	FromCurrency string  `json:"fromCurrency" binding:"required,len=3,alpha"`
+	ToCurrency   string  `json:"toCurrency" binding:"required,len=3,alpha"`
+	RateDate     string  `json:"rateDate" binding:"required"`
+	Rate         float64 `json:"rate" binding:"required,gt=0"`
+	Source       string  `json:"source"`
+}
+
+// RateDeleteRequest 删除汇率请求
+type RateDeleteRequest struct {
+	FromCurrency string `form:"fromCurrency" json:"fromCurrency" binding:"required"`
+	ToCurrency   string `form:"toCurrency" json:"toCurrency" binding:"required"`
+	RateDate     string `form:"rateDate" json:"rateDate" binding:"required"`
+}
+
+// RateListRequest 汇率列表请求
+type RateListRequest struct {
+	FromCurrency string `form:"fromCurrency"`
+	ToCurrency   string `form:"toCurrency"`
+	StartDate    string `form:"startDate"`
+	EndDate      string `form:"endDate"`
+}
+
+// RateListResponse 汇率列表响应
+type RateListResponse struct {
+	Items []Rate `json:"list"`
+	Total int    `json:"total"`
+}
+
+// RateImportRequest 汇率导入请求，文件需先通过分片上传接口上传
+type RateImportRequest struct {
+	FileName string `json:"fileName" binding:"required"`
+	// ToCurrency CSV 中没有目标币种列时使用
+	ToCurrency string `json:"toCurrency"`
+	Source     string `json:"source"`
+}
+
+// ImportError 导入失败的行
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// RateImportResult 汇率导入结果
+type RateImportResult struct {
+	Total    int           `json:"total"`
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Pairs    []string      `json:"pairs"` // 导入的币种对，如 USD/CNY
+	Errors   []ImportError `json:"errors"`
+}
+
+// ConvertRequest 金额换算请求
+type ConvertRequest struct {
+	From   string  `form:"from" binding:"required"`
+	To     string  `form:"to" binding:"required"`
+	Date   string  `form:"date"` // 为空时取最新汇率
+	Amount float64 `form:"amount"`
+}
+
+// ConvertResult 金额换算结果
+type ConvertResult struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Date      string  `json:"date"`
+	Rate      float64 `json:"rate"`
+	Amount    float64 `json:"amount"`
+	Converted float64 `json:"converted"`
+}
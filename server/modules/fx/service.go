@@ -0,0 +1,174 @@
+package fx
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"server/handler"
+	"server/utils"
+)
+
+// FxService 汇率服务接口
+type FxService interface {
+	SaveRate(userID string, req *RateSaveRequest) (*Rate, error)
+	ListRates(userID string, req *RateListRequest) (*RateListResponse, error)
+	DeleteRate(userID string, req *RateDeleteRequest) error
+	ImportCSV(userID string, req *RateImportRequest) (*RateImportResult, error)
+	Convert(userID string, req *ConvertRequest) (*ConvertResult, error)
+}
+
+// fxService 汇率服务实现
+type fxService struct {
+	fxRepo *FxRepository
+}
+
+// NewFxService 创建汇率服务
+func NewFxService() FxService {
+	return &fxService{
+		fxRepo: NewFxRepository(),
+	}
+}
+
+// SaveRate 手动录入汇率
+func (s *fxService) SaveRate(userID string, req *RateSaveRequest) (*Rate, error) {
+	from, err := normalizeCurrency(req.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	to, err := normalizeCurrency(req.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("源币种和目标币种不能相同")
+	}
+	date, err := parseDate(req.RateDate)
+	if err != nil {
+		return nil, err
+	}
+	source := req.Source
+	if source == "" {
+		source = "manual"
+	}
+
+	rate := Rate{FromCurrency: from, ToCurrency: to, RateDate: date, Rate: req.Rate, Source: source}
+	if err := s.fxRepo.Upsert(userID, []Rate{rate}); err != nil {
+		return nil, fmt.Errorf("保存汇率失败: %w", err)
+	}
+
+	rates, err := s.fxRepo.List(userID, &RateListRequest{FromCurrency: from, ToCurrency: to, StartDate: date, EndDate: date})
+	if err != nil || len(rates) == 0 {
+		return nil, fmt.Errorf("获取保存后的汇率失败: %v", err)
+	}
+	return &rates[0], nil
+}
+
+// ListRates 获取汇率列表
+func (s *fxService) ListRates(userID string, req *RateListRequest) (*RateListResponse, error) {
+	rates, err := s.fxRepo.List(userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("获取汇率列表失败: %w", err)
+	}
+
+	return &RateListResponse{
+		Items: rates,
+		Total: len(rates),
+	}, nil
+}
+
+// DeleteRate 删除汇率
+func (s *fxService) DeleteRate(userID string, req *RateDeleteRequest) error {
+	if err := s.fxRepo.Delete(userID, strings.ToUpper(req.FromCurrency), strings.ToUpper(req.ToCurrency), req.RateDate); err != nil {
+		return fmt.Errorf("删除汇率失败: %w", err)
+	}
+	return nil
+}
+
+// ImportCSV 导入已上传的汇率 CSV 文件，同一币种对和日期的汇率会被覆盖
+func (s *fxService) ImportCSV(userID string, req *RateImportRequest) (*RateImportResult, error) {
+	path, err := handler.ResolveUpload(userID, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开上传文件失败: %w", err)
+	}
+	defer file.Close()
+
+	source := req.Source
+	if source == "" {
+		source = "csv"
+	}
+	rates, total, errs, err := parseCSV(file, req.ToCurrency, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rates) > 0 {
+		if err := s.fxRepo.Upsert(userID, rates); err != nil {
+			utils.LogError("导入汇率失败，文件: %s, 错误: %v", req.FileName, err)
+			return nil, fmt.Errorf("导入汇率失败: %w", err)
+		}
+	}
+
+	result := &RateImportResult{
+		Total:    total,
+		Imported: len(rates),
+		Skipped:  total - len(rates),
+		Pairs:    []string{},
+		Errors:   errs,
+	}
+	if len(result.Errors) > maxImportErrors {
+		result.Errors = result.Errors[:maxImportErrors]
+	}
+	if result.Errors == nil {
+		result.Errors = []ImportError{}
+	}
+	seen := map[string]bool{}
+	for _, rate := range rates {
+		pair := rate.FromCurrency + "/" + rate.ToCurrency
+		if !seen[pair] {
+			seen[pair] = true
+			result.Pairs = append(result.Pairs, pair)
+		}
+	}
+	sort.Strings(result.Pairs)
+
+	utils.LogInfo("汇率导入完成，文件: %s, 共 %d 行, 导入 %d 行", req.FileName, total, len(rates))
+	return result, nil
+}
+
+// Convert 按汇率换算金额
+func (s *fxService) Convert(userID string, req *ConvertRequest) (*ConvertResult, error) {
+	from, err := normalizeCurrency(req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := normalizeCurrency(req.To)
+	if err != nil {
+		return nil, err
+	}
+	date := req.Date
+	if date != "" {
+		if date, err = parseDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	rate, ok := NewConverter(userID, to).Rate(from, date)
+	if !ok {
+		return nil, fmt.Errorf("缺少 %s/%s 汇率", from, to)
+	}
+	return &ConvertResult{
+		From:      from,
+		To:        to,
+		Date:      date,
+		Rate:      rate,
+		Amount:    req.Amount,
+		Converted: math.Round(req.Amount*rate*100) / 100,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package home
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHomeRoutes 注册首页路由
+func RegisterHomeRoutes(r *gin.RouterGroup) {
+	homeService := NewHomeService()
+
+	g := r.Group("/home")
+	{
+		g.GET("/stats", func(c *gin.Context) {
+			stats, err := homeService.GetStats(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, stats)
+		})
+
+		tradingCount := func(window string) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				count, err := homeService.GetTradingCount(middleware.GetCurrentUserID(c), window)
+				if err != nil {
+					handler.Error(c, handler.CodeError, err.Error())
+					return
+				}
+
+				handler.Success(c, count)
+			}
+		}
+		g.GET("/today-trading", tradingCount(WindowToday))
+		g.GET("/week-trading", tradingCount(WindowWeek))
+		g.GET("/month-trading", tradingCount(WindowMonth))
+
+		g.GET("/recent-trades", func(c *gin.Context) {
+			limit := 5
+			if limitStr := c.Query("limit"); limitStr != "" {
+				if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+					limit = l
+				}
+			}
+			if limit > 50 {
+				limit = 50
+			}
+
+			trades, err := homeService.GetRecentTrades(middleware.GetCurrentUserID(c), limit)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, trades)
+		})
+
+		g.GET("/quick-actions", func(c *gin.Context) {
+			actions, err := homeService.GetQuickActions(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, actions)
+		})
+
+		g.GET("/market-overview", func(c *gin.Context) {
+			overview, err := homeService.GetMarketOverview(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, overview)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package home
+
+// HomeStats 首页统计数据
+type HomeStats struct {
+	StockCount        int               `json:"stockCount"`
+	WatchCount        int               `json:"watchCount"` // 已启用的股票数量
+	PlanCount         int               `json:"planCount"`
+	ActivePlanCount   int               `json:"activePlanCount"`
+	LogCount          int               `json:"logCount"`
+	TotalProfit       float64           `json:"totalProfit"`  // 已实现盈亏 + 浮动盈亏，按本位币计
+	BaseCurrency      string            `json:"baseCurrency"` // 本位币
+	TodayTradingCount int               `json:"todayTradingCount"`
+	WeekTradingCount  int               `json:"weekTradingCount"`
+	MonthTradingCount int               `json:"monthTradingCount"`
+	ActivePlans       ActivePlanSummary `json:"activePlans"`
+}
+
+// TradingCount 时间窗口内的交易统计
+type TradingCount struct {
+	StartDate string  `json:"startDate"`
+	EndDate   string  `json:"endDate"`
+	Count     int     `json:"count"`
+	BuyCount  int     `json:"buyCount"`
+	SellCount int     `json:"sellCount"`
+	Amount    float64 `json:"amount"` // 成交金额
+}
+
+// ActivePlanSummary 进行中计划概要
+type ActivePlanSummary struct {
+	Count int         `json:"count"`
+	Items []PlanBrief `json:"list"` // 最近创建的若干条
+}
+
+// PlanBrief 计划简要信息
+type PlanBrief struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	StockCode   string  `json:"stockCode"`
+	StockName   string  `json:"stockName"`
+	TargetPrice float64 `json:"targetPrice"`
+	StopLoss    float64 `json:"stopLoss"`
+	TakeProfit  float64 `json:"takeProfit"`
+	EndTime     string  `json:"endTime"`
+	Status      string  `json:"status"`
+}
+
+// QuickAction 快速操作入口
+type QuickAction struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	Icon  string `json:"icon"`
+	Path  string `json:"path"`
+	Badge int    `json:"badge"` // 提示数量，如进行中的计划数
+}
+
+// MarketOverview 市场概览，按地区汇总股票和持仓
+type MarketOverview struct {
+	Regions      []RegionOverview `json:"regions"`
+	UpdatedAt    string           `json:"updatedAt"`
+	HoldingCount int              `json:"holdingCount"` // 当前持仓股票数量
+}
+
+// RegionOverview 单个地区的概览
+type RegionOverview struct {
+	Region        string  `json:"region"`
+	StockCount    int     `json:"stockCount"`
+	HoldingCount  int     `json:"holdingCount"`
+	MarketValue   float64 `json:"marketValue"`
+	UnrealizedPnL float64 `json:"unrealizedPnl"`
+}
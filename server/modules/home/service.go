@@ -0,0 +1,281 @@
+package home
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"server/modules/log"
+	"server/modules/plan"
+	"server/modules/position"
+	"server/storage"
+)
+
+// activePlanStatuses 视为进行中的计划状态，已触发但未执行的计划也算进行中
+var activePlanStatuses = []interface{}{plan.StatusActive, plan.StatusPending, plan.StatusTriggered, plan.StatusExecuting}
+
+// activePlanPlaceholders 与 activePlanStatuses 对应的 IN 占位符
+var activePlanPlaceholders = strings.TrimSuffix(strings.Repeat("?, ", len(activePlanStatuses)), ", ")
+
+// HomeService 首页服务接口
+type HomeService interface {
+	GetStats(userID string) (*HomeStats, error)
+	GetTradingCount(userID, window string) (*TradingCount, error)
+	GetRecentTrades(userID string, limit int) ([]log.Log, error)
+	GetQuickActions(userID string) ([]QuickAction, error)
+	GetMarketOverview(userID string) (*MarketOverview, error)
+}
+
+// homeService 首页服务实现
+type homeService struct {
+	logService      log.LogService
+	positionService position.PositionService
+}
+
+// NewHomeService 创建首页服务
+func NewHomeService() HomeService {
+	return &homeService{
+		logService:      log.NewLogService(),
+		positionService: position.NewPositionService(),
+	}
+}
+
+// 统计时间窗口
+const (
+	WindowToday = "today"
+	WindowWeek  = "week"
+	WindowMonth = "month"
+)
+
+// windowRange 计算时间窗口的起止日期（YYYY-MM-DD），周从周一开始
+func windowRange(window string, now time.Time) (string, string, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch window {
+	case WindowToday:
+		return today.Format("2006-01-02"), today.Format("2006-01-02"), nil
+	case WindowWeek:
+		start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start.Format("2006-01-02"), start.AddDate(0, 0, 6).Format("2006-01-02"), nil
+	case WindowMonth:
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return start.Format("2006-01-02"), start.AddDate(0, 1, -1).Format("2006-01-02"), nil
+	}
+	return "", "", fmt.Errorf("不支持的时间窗口: %s", window)
+}
+
+// countRows 执行 COUNT 查询
+func countRows(query string, args ...interface{}) (int, error) {
+	var count int
+	if err := storage.GetDB().QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetStats 获取首页统计数据
+func (s *homeService) GetStats(userID string) (*HomeStats, error) {
+	stats := &HomeStats{}
+	var err error
+
+	if stats.StockCount, err = countRows("SELECT COUNT(*) FROM stocks WHERE user_id = ?", userID); err != nil {
+		return nil, fmt.Errorf("统计股票数量失败: %w", err)
+	}
+	if stats.WatchCount, err = countRows("SELECT COUNT(*) FROM stocks WHERE user_id = ? AND enabled = 1", userID); err != nil {
+		return nil, fmt.Errorf("统计关注股票数量失败: %w", err)
+	}
+	if stats.PlanCount, err = countRows("SELECT COUNT(*) FROM plans WHERE user_id = ?", userID); err != nil {
+		return nil, fmt.Errorf("统计计划数量失败: %w", err)
+	}
+	if stats.LogCount, err = countRows("SELECT COUNT(*) FROM logs WHERE user_id = ?", userID); err != nil {
+		return nil, fmt.Errorf("统计日志数量失败: %w", err)
+	}
+
+	activePlans, err := s.getActivePlans(userID, 5)
+	if err != nil {
+		return nil, err
+	}
+	stats.ActivePlans = *activePlans
+	stats.ActivePlanCount = activePlans.Count
+
+	for _, w := range []struct {
+		window string
+		target *int
+	}{
+		{WindowToday, &stats.TodayTradingCount},
+		{WindowWeek, &stats.WeekTradingCount},
+		{WindowMonth, &stats.MonthTradingCount},
+	} {
+		tc, err := s.GetTradingCount(userID, w.window)
+		if err != nil {
+			return nil, err
+		}
+		*w.target = tc.Count
+	}
+
+	positions, err := s.positionService.ListPositions(userID, &position.PositionListRequest{IncludeClosed: true})
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalProfit = positions.BaseTotalPnL
+	stats.BaseCurrency = positions.BaseCurrency
+
+	return stats, nil
+}
+
+// GetTradingCount 获取时间窗口内已成交的交易次数
+func (s *homeService) GetTradingCount(userID, window string) (*TradingCount, error) {
+	startDate, endDate, err := windowRange(window, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &TradingCount{StartDate: startDate, EndDate: endDate}
+	var buyCount, sellCount sql.NullInt64
+	var amount sql.NullFloat64
+	query := `SELECT COUNT(*),
+		SUM(CASE WHEN type = ? THEN 1 ELSE 0 END),
+		SUM(CASE WHEN type = ? THEN 1 ELSE 0 END),
+		SUM(price * quantity)
+		FROM logs WHERE user_id = ? AND status = ? AND trading_time >= ? AND trading_time <= ?`
+	err = storage.GetDB().QueryRow(query, log.TypeBuy, log.TypeSell, userID, log.StatusCompleted, startDate, endDate+" 23:59:59").
+		Scan(&tc.Count, &buyCount, &sellCount, &amount)
+	if err != nil {
+		return nil, fmt.Errorf("统计交易次数失败: %w", err)
+	}
+	tc.BuyCount = int(buyCount.Int64)
+	tc.SellCount = int(sellCount.Int64)
+	tc.Amount = math.Round(amount.Float64*100) / 100
+
+	return tc, nil
+}
+
+// GetRecentTrades 获取最近的交易日志
+func (s *homeService) GetRecentTrades(userID string, limit int) ([]log.Log, error) {
+	resp, err := s.logService.ListLogs(userID, &log.LogListRequest{Page: 1, PageSize: limit})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Items == nil {
+		return []log.Log{}, nil
+	}
+	return resp.Items, nil
+}
+
+// getActivePlans 获取进行中计划的数量和最近创建的若干条
+func (s *homeService) getActivePlans(userID string, limit int) (*ActivePlanSummary, error) {
+	summary := &ActivePlanSummary{Items: []PlanBrief{}}
+
+	count, err := countRows("SELECT COUNT(*) FROM plans WHERE user_id = ? AND status IN ("+activePlanPlaceholders+")",
+		append([]interface{}{userID}, activePlanStatuses...)...)
+	if err != nil {
+		return nil, fmt.Errorf("统计进行中计划失败: %w", err)
+	}
+	summary.Count = count
+
+	query := `SELECT id, name, type, stock_code, stock_name, target_price, stop_loss, take_profit, end_time, status
+		FROM plans WHERE user_id = ? AND status IN (` + activePlanPlaceholders + `) ORDER BY created_at DESC LIMIT ?`
+	args := append(append([]interface{}{userID}, activePlanStatuses...), limit)
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询进行中计划失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p PlanBrief
+		if err := rows.Scan(&p.ID, &p.Name, &p.Type, &p.StockCode, &p.StockName,
+			&p.TargetPrice, &p.StopLoss, &p.TakeProfit, &p.EndTime, &p.Status); err != nil {
+			return nil, fmt.Errorf("扫描计划数据失败: %w", err)
+		}
+		summary.Items = append(summary.Items, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历计划数据失败: %w", err)
+	}
+
+	return summary, nil
+}
+
+// GetQuickActions 获取快速操作入口
+func (s *homeService) GetQuickActions(userID string) ([]QuickAction, error) {
+	activePlanCount, err := countRows("SELECT COUNT(*) FROM plans WHERE user_id = ? AND status IN ("+activePlanPlaceholders+")",
+		append([]interface{}{userID}, activePlanStatuses...)...)
+	if err != nil {
+		return nil, fmt.Errorf("统计进行中计划失败: %w", err)
+	}
+	pendingLogCount, err := countRows("SELECT COUNT(*) FROM logs WHERE user_id = ? AND status = ?", userID, log.StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("统计进行中日志失败: %w", err)
+	}
+
+	return []QuickAction{
+		{Key: "trading-plan", Title: "交易计划", Icon: "money", Path: "/trading-plan", Badge: activePlanCount},
+		{Key: "trading-log", Title: "交易日志", Icon: "file", Path: "/trading-log", Badge: pendingLogCount},
+		{Key: "trading-review", Title: "交易复盘", Icon: "chart", Path: "/trading-review"},
+		{Key: "stock", Title: "股票管理", Icon: "view-list", Path: "/stock"},
+	}, nil
+}
+
+// GetMarketOverview 获取市场概览，按地区汇总股票数量和持仓
+func (s *homeService) GetMarketOverview(userID string) (*MarketOverview, error) {
+	overview := &MarketOverview{
+		Regions:   []RegionOverview{},
+		UpdatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	index := map[string]*RegionOverview{}
+	regionOf := map[string]string{}
+
+	rows, err := storage.GetDB().Query("SELECT code, COALESCE(region, '') FROM stocks WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询股票失败: %w", err)
+	}
+	defer rows.Close()
+
+	var order []string
+	for rows.Next() {
+		var code, region string
+		if err := rows.Scan(&code, &region); err != nil {
+			return nil, fmt.Errorf("扫描股票数据失败: %w", err)
+		}
+		regionOf[code] = region
+		r, ok := index[region]
+		if !ok {
+			r = &RegionOverview{Region: region}
+			index[region] = r
+			order = append(order, region)
+		}
+		r.StockCount++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历股票数据失败: %w", err)
+	}
+
+	positions, err := s.positionService.ListPositions(userID, &position.PositionListRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions.Items {
+		region := regionOf[p.StockCode]
+		r, ok := index[region]
+		if !ok {
+			r = &RegionOverview{Region: region}
+			index[region] = r
+			order = append(order, region)
+		}
+		r.HoldingCount++
+		r.MarketValue += p.MarketValue
+		r.UnrealizedPnL += p.UnrealizedPnL
+	}
+	overview.HoldingCount = positions.Total
+
+	for _, region := range order {
+		r := index[region]
+		r.MarketValue = math.Round(r.MarketValue*100) / 100
+		r.UnrealizedPnL = math.Round(r.UnrealizedPnL*100) / 100
+		overview.Regions = append(overview.Regions, *r)
+	}
+
+	return overview, nil
+}
@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportHandler 券商成交导入处理器
+type ImportHandler struct {
+	importService ImportService
+}
+
+// NewImportHandler 创建券商成交导入处理器
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		importService: NewImportService(),
+	}
+}
+
+// RegisterImportRoutes 注册券商成交导入路由
+func RegisterImportRoutes(r *gin.RouterGroup) {
+	handler := NewImportHandler()
+
+	g := r.Group("/imports")
+	{
+		g.POST("/mappings/create", handler.createMapping)
+		g.GET("/mappings/getList", handler.listMappings)
+		g.GET("/mappings/getDetail/:id", handler.getMapping)
+		g.PUT("/mappings/update/:id", handler.updateMapping)
+		g.DELETE("/mappings/delete/:id", handler.deleteMapping)
+		g.POST("/preview", handler.preview)
+		g.POST("/commit", handler.commit)
+	}
+}
+
+// createMapping 创建券商导入映射
+func (h *ImportHandler) createMapping(c *gin.Context) {
+	var req MappingCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	mapping, err := h.importService.CreateMapping(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, mapping)
+}
+
+// listMappings 获取券商导入映射列表
+func (h *ImportHandler) listMappings(c *gin.Context) {
+	mappings, err := h.importService.ListMappings(middleware.GetCurrentUserID(c))
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, gin.H{"list": mappings, "total": len(mappings)})
+}
+
+// getMapping 获取券商导入映射详情
+func (h *ImportHandler) getMapping(c *gin.Context) {
+	mapping, err := h.importService.GetMapping(middleware.GetCurrentUserID(c), c.Param("id"))
+	if err != nil {
+		handler.Error(c, handler.CodeNotFound, err.Error())
+		return
+	}
+
+	handler.Success(c, mapping)
+}
+
+// updateMapping 更新券商导入映射
+func (h *ImportHandler) updateMapping(c *gin.Context) {
+	var req MappingUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	mapping, err := h.importService.UpdateMapping(middleware.GetCurrentUserID(c), c.Param("id"), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, mapping)
+}
+
+// deleteMapping 删除券商导入映射
+func (h *ImportHandler) deleteMapping(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.importService.DeleteMapping(middleware.GetCurrentUserID(c), id); err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, gin.H{"id": id})
+}
+
+// preview 预览导入结果，不写入数据
+func (h *ImportHandler) preview(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.importService.Preview(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, result)
+}
+
+// commit 导入校验通过的成交
+func (h *ImportHandler) commit(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.importService.Commit(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, result)
+}
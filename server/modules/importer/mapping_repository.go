@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"server/storage"
+)
+
+// MappingRepository 导入映射数据访问层
+type MappingRepository struct{}
+
+// NewMappingRepository 创建导入映射仓库
+func NewMappingRepository() *MappingRepository {
+	return &MappingRepository{}
+}
+
+const mappingColumns = `id, user_id, broker, columns, buy_values, sell_values, time_format, header_row, sheet,
+	created_at, updated_at`
+
+// Create 创建映射
+func (r *MappingRepository) Create(m *Mapping) error {
+	columns, buyValues, sellValues, err := encodeMapping(m)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`INSERT INTO import_mappings (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, mappingColumns)
+	_, err = storage.GetDB().Exec(query,
+		m.ID, m.UserID, m.Broker, columns, buyValues, sellValues, m.TimeFormat, m.HeaderRow, m.Sheet,
+		m.CreatedAt, m.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID 根据ID获取映射
+func (r *MappingRepository) GetByID(userID, id string) (*Mapping, error) {
+	query := fmt.Sprintf("SELECT %s FROM import_mappings WHERE id = ? AND user_id = ?", mappingColumns)
+	m, err := scanMapping(storage.GetDB().QueryRow(query, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("导入映射不存在")
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExistsByBroker 检查用户是否已有该券商的映射，excludeID 为更新时排除的映射自身
+func (r *MappingRepository) ExistsByBroker(userID, broker, excludeID string) (bool, error) {
+	var count int
+	err := storage.GetDB().QueryRow(
+		"SELECT COUNT(*) FROM import_mappings WHERE user_id = ? AND broker = ? AND id != ?",
+		userID, broker, excludeID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// List 获取用户的映射列表，按券商名称排序
+func (r *MappingRepository) List(userID string) ([]Mapping, error) {
+	query := fmt.Sprintf("SELECT %s FROM import_mappings WHERE user_id = ? ORDER BY broker ASC", mappingColumns)
+	rows, err := storage.GetDB().Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := []Mapping{}
+	for rows.Next() {
+		m, err := scanMapping(rows)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, *m)
+	}
+	return mappings, rows.Err()
+}
+
+// Update 更新映射
+func (r *MappingRepository) Update(m *Mapping) error {
+	columns, buyValues, sellValues, err := encodeMapping(m)
+	if err != nil {
+		return err
+	}
+	_, err = storage.GetDB().Exec(`UPDATE import_mappings SET broker = ?, columns = ?, buy_values = ?, sell_values = ?,
+		time_format = ?, header_row = ?, sheet = ?, updated_at = ? WHERE id = ? AND user_id = ?`,
+		m.Broker, columns, buyValues, sellValues, m.TimeFormat, m.HeaderRow, m.Sheet, m.UpdatedAt, m.ID, m.UserID,
+	)
+	return err
+}
+
+// Delete 删除映射
+func (r *MappingRepository) Delete(userID, id string) error {
+	result, err := storage.GetDB().Exec("DELETE FROM import_mappings WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("导入映射不存在")
+	}
+	return nil
+}
+
+// encodeMapping 将映射的列配置和方向取值编码为 JSON 文本
+func encodeMapping(m *Mapping) (string, string, string, error) {
+	columns, err := json.Marshal(m.Columns)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码列映射失败: %w", err)
+	}
+	buyValues, err := json.Marshal(m.BuyValues)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码买入取值失败: %w", err)
+	}
+	sellValues, err := json.Marshal(m.SellValues)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码卖出取值失败: %w", err)
+	}
+	return string(columns), string(buyValues), string(sellValues), nil
+}
+
+// rowScanner 兼容 *sql.Row 和 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMapping 扫描一行映射数据
+func scanMapping(row rowScanner) (*Mapping, error) {
+	m := &Mapping{}
+	var columns string
+	var buyValues, sellValues, timeFormat, sheet sql.NullString
+	var headerRow sql.NullInt64
+	err := row.Scan(&m.ID, &m.UserID, &m.Broker, &columns, &buyValues, &sellValues, &timeFormat, &headerRow, &sheet,
+		&m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.TimeFormat = timeFormat.String
+	m.HeaderRow = int(headerRow.Int64)
+	m.Sheet = sheet.String
+	if err := json.Unmarshal([]byte(columns), &m.Columns); err != nil {
+		return nil, fmt.Errorf("解析列映射失败: %w", err)
+	}
+	if buyValues.String != "" {
+		_ = json.Unmarshal([]byte(buyValues.String), &m.BuyValues)
+	}
+	if sellValues.String != "" {
+		_ = json.Unmarshal([]byte(sellValues.String), &m.SellValues)
+	}
+	if m.BuyValues == nil {
+		m.BuyValues = []string{}
+	}
+	if m.SellValues == nil {
+		m.SellValues = []string{}
+	}
+	return m, nil
+}
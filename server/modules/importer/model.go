@@ -0,0 +1,99 @@
+package importer
+
+import "time"
+
+// 映射字段，值为文件中对应的表头名称
+const (
+	FieldTradeID     = "tradeId"     // 券商成交编号（必填）
+	FieldStockCode   = "stockCode"   // 股票代码（必填）
+	FieldStockName   = "stockName"   // 股票名称
+	FieldType        = "type"        // 买卖方向，未映射时按数量正负判断
+	FieldPrice       = "price"       // 成交价（必填）
+	FieldQuantity    = "quantity"    // 成交数量（必填）
+	FieldTradingTime = "tradingTime" // 成交时间（必填），只有时间时需同时映射成交日期
+	FieldTradingDate = "tradingDate" // 成交日期，日期和时间分列时使用
+	FieldFees        = "fees"        // 费用合计，未映射时按费率表计算
+	FieldRemark      = "remark"      // 备注
+)
+
+// requiredFields 必须映射的字段
+var requiredFields = []string{FieldTradeID, FieldStockCode, FieldPrice, FieldQuantity, FieldTradingTime}
+
+// 预览行状态
+const (
+	RowValid     = "valid"     // 可以导入
+	RowInvalid   = "invalid"   // 校验失败
+	RowDuplicate = "duplicate" // 成交编号已导入或在文件中重复
+)
+
+// Mapping 券商导入映射，每个用户每个券商一份
+type Mapping struct {
+	ID         string            `json:"id" db:"id"`
+	UserID     string            `json:"userId" db:"user_id"`
+	Broker     string            `json:"broker" db:"broker"`
+	Columns    map[string]string `json:"columns" db:"columns"`        // 字段 -> 表头名称
+	BuyValues  []string          `json:"buyValues" db:"buy_values"`   // 方向列中表示买入的取值，为空时为 买入/证券买入/buy/b
+	SellValues []string          `json:"sellValues" db:"sell_values"` // 方向列中表示卖出的取值
+	TimeFormat string            `json:"timeFormat" db:"time_format"` // 时间格式，如 YYYY/MM/DD HH:mm:ss，为空时自动识别常见格式
+	HeaderRow  int               `json:"headerRow" db:"header_row"`   // 表头所在行，从 1 开始
+	Sheet      string            `json:"sheet" db:"sheet"`            // xlsx 工作表名称，为空时读取第一个
+	CreatedAt  time.Time         `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time         `json:"updatedAt" db:"updated_at"`
+}
+
+// MappingCreateRequest 创建映射请求
+type MappingCreateRequest struct {
+	Broker     string            `json:"broker" binding:"required"`
+	Columns    map[string]string `json:"columns" binding:"required"`
+	BuyValues  []string          `json:"buyValues"`
+	SellValues []string          `json:"sellValues"`
+	TimeFormat string            `json:"timeFormat"`
+	HeaderRow  int               `json:"headerRow" binding:"omitempty,min=1"`
+	Sheet      string            `json:"sheet"`
+}
+
+// MappingUpdateRequest 更新映射请求
+type MappingUpdateRequest struct {
+	Broker     *string           `json:"broker,omitempty"`
+	Columns    map[string]string `json:"columns,omitempty"`
+	BuyValues  []string          `json:"buyValues,omitempty"`
+	SellValues []string          `json:"sellValues,omitempty"`
+	TimeFormat *string           `json:"timeFormat,omitempty"`
+	HeaderRow  *int              `json:"headerRow,omitempty" binding:"omitempty,min=1"`
+	Sheet      *string           `json:"sheet,omitempty"`
+}
+
+// ImportRequest 导入请求，文件需先通过分片上传接口上传，支持 .csv 和 .xlsx
+type ImportRequest struct {
+	FileName  string `json:"fileName" binding:"required"`
+	MappingID string `json:"mappingId" binding:"required"`
+	// SkipInvalid 提交时跳过校验失败的行，为 false 时存在失败行则整体不导入
+	SkipInvalid bool `json:"skipInvalid"`
+}
+
+// RowPreview 一行数据的解析结果
+type RowPreview struct {
+	Line        int      `json:"line"` // 文件中的行号
+	Status      string   `json:"status"`
+	Errors      []string `json:"errors,omitempty"`
+	TradeID     string   `json:"tradeId"`
+	StockCode   string   `json:"stockCode"`
+	StockName   string   `json:"stockName"`
+	Type        string   `json:"type"`
+	TradingTime string   `json:"tradingTime"`
+	Price       float64  `json:"price"`
+	Quantity    int      `json:"quantity"`
+	Fees        float64  `json:"fees"`
+}
+
+// ImportResult 预览或导入结果
+type ImportResult struct {
+	Broker     string       `json:"broker"`
+	DryRun     bool         `json:"dryRun"`
+	Total      int          `json:"total"`
+	Valid      int          `json:"valid"`
+	Invalid    int          `json:"invalid"`
+	Duplicates int          `json:"duplicates"`
+	Imported   int          `json:"imported"`       // 实际写入条数，预览时为 0
+	Rows       []RowPreview `json:"rows,omitempty"` // 预览时返回全部行，导入时只返回失败行
+}
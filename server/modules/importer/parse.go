@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/handler"
+	"server/modules/log"
+	"server/utils"
+)
+
+// 未配置方向取值时使用的默认值，比较时不区分大小写
+var (
+	defaultBuyValues  = []string{"买入", "证券买入", "买", "buy", "b", "bought"}
+	defaultSellValues = []string{"卖出", "证券卖出", "卖", "sell", "s", "sold"}
+)
+
+// timeLayouts 未配置时间格式时依次尝试的格式
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04",
+	"20060102 15:04:05",
+	"20060102150405",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"20060102",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+}
+
+// timeTokens 映射中的时间格式占位符与 Go 时间格式的对应关系
+var timeTokens = strings.NewReplacer(
+	"YYYY", "2006", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05",
+)
+
+// readRows 读取用户上传目录下的 CSV 或 xlsx 文件
+func readRows(userID, fileName, sheet string) ([][]string, error) {
+	path, err := handler.ResolveUpload(userID, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return utils.ReadXLSX(path, sheet)
+	case ".csv", ".txt":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开上传文件失败: %w", err)
+		}
+		defer file.Close()
+
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		reader.LazyQuotes = true
+		var rows [][]string
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("读取 CSV 失败: %w", err)
+			}
+			rows = append(rows, record)
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("不支持的文件类型，仅支持 .csv 和 .xlsx")
+}
+
+// mapColumns 根据映射在表头中查找各字段所在列，表头比较时忽略首尾空白和大小写
+func mapColumns(header []string, columns map[string]string) (map[string]int, error) {
+	position := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := position[name]; !ok {
+			position[name] = i
+		}
+	}
+
+	index := map[string]int{}
+	for field, column := range columns {
+		i, ok := position[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("文件表头中找不到 %s 对应的列: %s", field, column)
+		}
+		index[field] = i
+	}
+	return index, nil
+}
+
+// validateColumns 校验映射包含全部必填字段且没有未知字段
+func validateColumns(columns map[string]string) error {
+	known := map[string]bool{
+		FieldTradeID: true, FieldStockCode: true, FieldStockName: true, FieldType: true, FieldPrice: true,
+		FieldQuantity: true, FieldTradingTime: true, FieldTradingDate: true, FieldFees: true, FieldRemark: true,
+	}
+	for field, column := range columns {
+		if !known[field] {
+			return fmt.Errorf("不支持的映射字段: %s", field)
+		}
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("映射字段 %s 的列名不能为空", field)
+		}
+	}
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			return fmt.Errorf("缺少必填映射字段: %s", field)
+		}
+	}
+	return nil
+}
+
+// parseRow 按映射解析一行数据，返回预览信息和日志创建请求，校验错误记录在预览的 Errors 中
+func parseRow(m *Mapping, index map[string]int, record []string, line int) (*RowPreview, *log.LogCreateRequest) {
+	field := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := &RowPreview{
+		Line:      line,
+		TradeID:   field(FieldTradeID),
+		StockCode: field(FieldStockCode),
+		StockName: field(FieldStockName),
+	}
+	fail := func(format string, args ...interface{}) {
+		row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+	}
+
+	if row.TradeID == "" {
+		fail("成交编号为空")
+	}
+	if row.StockCode == "" {
+		fail("股票代码为空")
+	}
+
+	price, err := parseNumber(field(FieldPrice))
+	if err != nil || price <= 0 {
+		fail("成交价无效: %s", field(FieldPrice))
+	}
+	row.Price = price
+
+	quantity, err := parseNumber(field(FieldQuantity))
+	if err != nil || quantity == 0 || quantity != math.Trunc(quantity) {
+		fail("成交数量无效: %s", field(FieldQuantity))
+	}
+	row.Quantity = int(math.Abs(quantity))
+
+	if _, ok := index[FieldType]; ok {
+		row.Type = parseType(m, field(FieldType))
+		if row.Type == "" {
+			fail("无法识别的买卖方向: %s", field(FieldType))
+		}
+	} else if quantity < 0 {
+		row.Type = log.TypeSell
+	} else {
+		row.Type = log.TypeBuy
+	}
+
+	value := field(FieldTradingTime)
+	if date := field(FieldTradingDate); date != "" {
+		value = date + " " + value
+	}
+	tradingTime, err := parseTime(value, m.TimeFormat)
+	if err != nil {
+		fail("%s", err.Error())
+	}
+	row.TradingTime = tradingTime
+
+	var fees *float64
+	if raw := field(FieldFees); raw != "" {
+		v, err := parseNumber(raw)
+		if err != nil || v < 0 {
+			fail("费用无效: %s", raw)
+		} else {
+			fees = &v
+			row.Fees = v
+		}
+	}
+
+	req := &log.LogCreateRequest{
+		StockCode:     row.StockCode,
+		StockName:     row.StockName,
+		Type:          row.Type,
+		TradingTime:   row.TradingTime,
+		Price:         row.Price,
+		Quantity:      row.Quantity,
+		Remark:        field(FieldRemark),
+		Status:        log.StatusCompleted, // 对账单中的记录均为已成交
+		Fees:          fees,
+		Broker:        m.Broker,
+		BrokerTradeID: row.TradeID,
+	}
+	return row, req
+}
+
+// parseNumber 解析数字，忽略千分位逗号、货币符号和空格
+func parseNumber(value string) (float64, error) {
+	value = strings.NewReplacer(",", "", "¥", "", "$", "", " ", "").Replace(value)
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("不是有效数字: %s", value)
+	}
+	return v, nil
+}
+
+// parseType 根据映射的方向取值识别买卖方向，无法识别时返回空
+func parseType(m *Mapping, value string) string {
+	buyValues, sellValues := m.BuyValues, m.SellValues
+	if len(buyValues) == 0 {
+		buyValues = defaultBuyValues
+	}
+	if len(sellValues) == 0 {
+		sellValues = defaultSellValues
+	}
+	for _, v := range buyValues {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return log.TypeBuy
+		}
+	}
+	for _, v := range sellValues {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return log.TypeSell
+		}
+	}
+	return ""
+}
+
+// parseTime 解析成交时间并统一为 YYYY-MM-DD HH:mm:ss
+// 配置了格式时只按该格式解析；xlsx 中的日期单元格为 Excel 序列号，也会被识别
+func parseTime(value, format string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("成交时间为空")
+	}
+
+	layouts := timeLayouts
+	if format != "" {
+		layouts = []string{timeTokens.Replace(format)}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02 15:04:05"), nil
+		}
+	}
+
+	// Excel 序列号：1899-12-30 起的天数，小数部分为一天内的时间
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 1 && serial < 200000 {
+		days := math.Floor(serial)
+		seconds := math.Round((serial - days) * 86400)
+		t := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).
+			AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+		return t.Format("2006-01-02 15:04:05"), nil
+	}
+	return "", fmt.Errorf("无法识别的成交时间: %s", value)
+}
@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"server/modules/log"
+	"server/utils"
+)
+
+// ImportService 券商成交导入服务接口
+type ImportService interface {
+	CreateMapping(userID string, req *MappingCreateRequest) (*Mapping, error)
+	GetMapping(userID, id string) (*Mapping, error)
+	ListMappings(userID string) ([]Mapping, error)
+	UpdateMapping(userID, id string, req *MappingUpdateRequest) (*Mapping, error)
+	DeleteMapping(userID, id string) error
+	Preview(userID string, req *ImportRequest) (*ImportResult, error)
+	Commit(userID string, req *ImportRequest) (*ImportResult, error)
+}
+
+// importService 券商成交导入服务实现
+type importService struct {
+	mappingRepo *MappingRepository
+	logService  log.LogService
+}
+
+// NewImportService 创建券商成交导入服务
+func NewImportService() ImportService {
+	return &importService{
+		mappingRepo: NewMappingRepository(),
+		logService:  log.NewLogService(),
+	}
+}
+
+// CreateMapping 创建券商导入映射，同一券商只能有一个映射
+func (s *importService) CreateMapping(userID string, req *MappingCreateRequest) (*Mapping, error) {
+	broker := strings.TrimSpace(req.Broker)
+	if broker == "" {
+		return nil, fmt.Errorf("券商名称不能为空")
+	}
+	if err := validateColumns(req.Columns); err != nil {
+		return nil, err
+	}
+	if exists, err := s.mappingRepo.ExistsByBroker(userID, broker, ""); err != nil {
+		return nil, fmt.Errorf("检查导入映射失败: %w", err)
+	} else if exists {
+		return nil, fmt.Errorf("券商 %s 已有导入映射", broker)
+	}
+	headerRow := req.HeaderRow
+	if headerRow <= 0 {
+		headerRow = 1
+	}
+
+	mapping := &Mapping{
+		ID:         utils.GenerateID(),
+		UserID:     userID,
+		Broker:     broker,
+		Columns:    req.Columns,
+		BuyValues:  req.BuyValues,
+		SellValues: req.SellValues,
+		TimeFormat: req.TimeFormat,
+		HeaderRow:  headerRow,
+		Sheet:      req.Sheet,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.mappingRepo.Create(mapping); err != nil {
+		return nil, fmt.Errorf("创建导入映射失败: %w", err)
+	}
+
+	utils.LogInfo("导入映射创建成功，券商: %s", broker)
+	return s.mappingRepo.GetByID(userID, mapping.ID)
+}
+
+// GetMapping 获取券商导入映射
+func (s *importService) GetMapping(userID, id string) (*Mapping, error) {
+	return s.mappingRepo.GetByID(userID, id)
+}
+
+// ListMappings 获取券商导入映射列表
+func (s *importService) ListMappings(userID string) ([]Mapping, error) {
+	mappings, err := s.mappingRepo.List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取导入映射列表失败: %w", err)
+	}
+	return mappings, nil
+}
+
+// UpdateMapping 更新券商导入映射
+// 修改券商名称后，已导入日志仍按原券商名称去重
+func (s *importService) UpdateMapping(userID, id string, req *MappingUpdateRequest) (*Mapping, error) {
+	mapping, err := s.mappingRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Broker != nil {
+		mapping.Broker = strings.TrimSpace(*req.Broker)
+		if mapping.Broker == "" {
+			return nil, fmt.Errorf("券商名称不能为空")
+		}
+		if exists, err := s.mappingRepo.ExistsByBroker(userID, mapping.Broker, id); err != nil {
+			return nil, fmt.Errorf("检查导入映射失败: %w", err)
+		} else if exists {
+			return nil, fmt.Errorf("券商 %s 已有导入映射", mapping.Broker)
+		}
+	}
+	if req.Columns != nil {
+		if err := validateColumns(req.Columns); err != nil {
+			return nil, err
+		}
+		mapping.Columns = req.Columns
+	}
+	if req.BuyValues != nil {
+		mapping.BuyValues = req.BuyValues
+	}
+	if req.SellValues != nil {
+		mapping.SellValues = req.SellValues
+	}
+	if req.TimeFormat != nil {
+		mapping.TimeFormat = *req.TimeFormat
+	}
+	if req.HeaderRow != nil {
+		mapping.HeaderRow = *req.HeaderRow
+	}
+	if req.Sheet != nil {
+		mapping.Sheet = *req.Sheet
+	}
+	mapping.UpdatedAt = time.Now()
+
+	if err := s.mappingRepo.Update(mapping); err != nil {
+		return nil, fmt.Errorf("更新导入映射失败: %w", err)
+	}
+	return s.mappingRepo.GetByID(userID, id)
+}
+
+// DeleteMapping 删除券商导入映射，已导入的日志不受影响
+func (s *importService) DeleteMapping(userID, id string) error {
+	return s.mappingRepo.Delete(userID, id)
+}
+
+// Preview 解析文件并校验每一行，不写入数据库
+func (s *importService) Preview(userID string, req *ImportRequest) (*ImportResult, error) {
+	result, _, err := s.evaluate(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = true
+	return result, nil
+}
+
+// Commit 在一个事务中写入校验通过的行，已导入过的成交编号跳过
+func (s *importService) Commit(userID string, req *ImportRequest) (*ImportResult, error) {
+	result, logs, err := s.evaluate(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if result.Invalid > 0 && !req.SkipInvalid {
+		return nil, fmt.Errorf("有 %d 行校验失败，请修正后重新导入，或设置 skipInvalid 跳过失败行", result.Invalid)
+	}
+
+	if len(logs) > 0 {
+		imported, err := s.logService.ImportLogs(userID, logs)
+		if err != nil {
+			utils.LogError("导入券商成交失败，券商: %s, 文件: %s, 错误: %v", result.Broker, req.FileName, err)
+			return nil, fmt.Errorf("导入券商成交失败: %w", err)
+		}
+		result.Imported = imported
+		// 预览后其他请求可能已导入相同成交
+		result.Duplicates += len(logs) - imported
+		result.Valid = imported
+	}
+
+	failed := []RowPreview{}
+	for _, row := range result.Rows {
+		if row.Status == RowInvalid {
+			failed = append(failed, row)
+		}
+	}
+	result.Rows = failed
+
+	utils.LogInfo("券商成交导入完成，券商: %s, 文件: %s, 共 %d 行, 导入 %d 行, 重复 %d 行, 失败 %d 行",
+		result.Broker, req.FileName, result.Total, result.Imported, result.Duplicates, result.Invalid)
+	return result, nil
+}
+
+// evaluate 按映射解析文件的每一行，返回预览结果和可以导入的日志
+func (s *importService) evaluate(userID string, req *ImportRequest) (*ImportResult, []log.Log, error) {
+	mapping, err := s.mappingRepo.GetByID(userID, req.MappingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := readRows(userID, req.FileName, mapping.Sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerRow := mapping.HeaderRow
+	if headerRow <= 0 {
+		headerRow = 1
+	}
+	if len(rows) < headerRow {
+		return nil, nil, fmt.Errorf("文件不足 %d 行，找不到表头", headerRow)
+	}
+	index, err := mapColumns(rows[headerRow-1], mapping.Columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := s.logService.ListBrokerTradeIDs(userID, mapping.Broker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &ImportResult{Broker: mapping.Broker, Rows: []RowPreview{}}
+	logs := []log.Log{}
+	seen := map[string]bool{}
+	for i := headerRow; i < len(rows); i++ {
+		record := rows[i]
+		if isBlank(record) {
+			continue
+		}
+		result.Total++
+
+		row, createReq := parseRow(mapping, index, record, i+1)
+		switch {
+		case len(row.Errors) > 0:
+			row.Status = RowInvalid
+		case existing[row.TradeID] || seen[row.TradeID]:
+			row.Status = RowDuplicate
+		default:
+			prepared, err := s.logService.PrepareLog(userID, createReq)
+			if err != nil {
+				row.Status = RowInvalid
+				row.Errors = append(row.Errors, err.Error())
+				break
+			}
+			row.Status = RowValid
+			row.StockName = prepared.StockName
+			row.Fees = prepared.Fees
+			logs = append(logs, *prepared)
+			seen[row.TradeID] = true
+		}
+
+		switch row.Status {
+		case RowValid:
+			result.Valid++
+		case RowInvalid:
+			result.Invalid++
+		case RowDuplicate:
+			result.Duplicates++
+		}
+		result.Rows = append(result.Rows, *row)
+	}
+	return result, logs, nil
+}
+
+// isBlank 判断是否为空行
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
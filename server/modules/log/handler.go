@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"server/handler"
+	"server/middleware"
+	"server/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,7 +23,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			log, err := logService.CreateLog(&req)
+			log, err := logService.CreateLog(middleware.GetCurrentUserID(c), &req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -36,6 +38,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				Type:      c.Query("type"),
 				Status:    c.Query("status"),
 				StockCode: c.Query("stockCode"),
+				PlanID:    c.Query("planId"),
 				PlanName:  c.Query("planName"),
 				StartDate: c.Query("startDate"),
 				EndDate:   c.Query("endDate"),
@@ -53,7 +56,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				}
 			}
 
-			response, err := logService.ListLogs(req)
+			response, err := logService.ListLogs(middleware.GetCurrentUserID(c), req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -62,6 +65,24 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 			handler.Success(c, response)
 		})
 
+		g.GET("/export", func(c *gin.Context) {
+			req := &LogListRequest{
+				Keyword:   c.Query("keyword"),
+				Type:      c.Query("type"),
+				Status:    c.Query("status"),
+				StockCode: c.Query("stockCode"),
+				PlanID:    c.Query("planId"),
+				PlanName:  c.Query("planName"),
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+			}
+
+			userID := middleware.GetCurrentUserID(c)
+			handler.Export(c, "logs", "交易日志", func(w utils.RowWriter) error {
+				return logService.ExportLogs(userID, req, w)
+			})
+		})
+
 		g.GET("/getDetail/:id", func(c *gin.Context) {
 			id := c.Param("id")
 			if id == "" {
@@ -69,7 +90,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			log, err := logService.GetLog(id)
+			log, err := logService.GetLog(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeNotFound, err.Error())
 				return
@@ -91,7 +112,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			log, err := logService.UpdateLog(id, &req)
+			log, err := logService.UpdateLog(middleware.GetCurrentUserID(c), id, &req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -107,7 +128,7 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			err := logService.DeleteLog(id)
+			err := logService.DeleteLog(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -115,5 +136,15 @@ func RegisterLogRoutes(r *gin.RouterGroup) {
 
 			handler.Success(c, gin.H{"id": id})
 		})
+
+		g.POST("/recalculateFees", func(c *gin.Context) {
+			response, err := logService.RecalculateFees(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
 	}
 }
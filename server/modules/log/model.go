@@ -1,53 +1,99 @@
 package log
 
-import "time"
+import (
+	"time"
+
+	"server/modules/fee"
+)
+
+// 交易类型
+const (
+	TypeBuy  = "buy"  // 买入
+	TypeSell = "sell" // 卖出
+)
+
+// 日志状态，只有已完成的日志视为成交，计入持仓、盈亏和各项统计
+const (
+	StatusPending   = "pending"   // 计划中
+	StatusCompleted = "completed" // 已完成（已成交）
+	StatusCancelled = "cancelled" // 已取消
+	StatusFailed    = "failed"    // 执行失败
+)
 
 // Log 交易日志模型
 type Log struct {
-	ID          string    `json:"id" db:"id"`
-	Title       string    `json:"title" db:"title"`
-	PlanName    string    `json:"planName" db:"plan_name"`
-	StockCode   string    `json:"stockCode" db:"stock_code"`
-	StockName   string    `json:"stockName" db:"stock_name"`
-	Type        string    `json:"type" db:"type"`
-	TradingTime string    `json:"tradingTime" db:"trading_time"`
-	Price       float64   `json:"price" db:"price"`
-	Quantity    int       `json:"quantity" db:"quantity"`
-	Strategy    string    `json:"strategy" db:"strategy"`
-	Remark      string    `json:"remark" db:"remark"`
-	Status      string    `json:"status" db:"status"`
-	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+	ID            string    `json:"id" db:"id"`
+	UserID        string    `json:"userId" db:"user_id"`
+	Title         string    `json:"title" db:"title"`
+	PlanID        string    `json:"planId" db:"plan_id"`
+	PlanName      string    `json:"planName" db:"plan_name"`
+	StockCode     string    `json:"stockCode" db:"stock_code"`
+	StockName     string    `json:"stockName" db:"stock_name"`
+	Type          string    `json:"type" db:"type"`
+	TradingTime   string    `json:"tradingTime" db:"trading_time"`
+	Price         float64   `json:"price" db:"price"`
+	Quantity      int       `json:"quantity" db:"quantity"`
+	Strategy      string    `json:"strategy" db:"strategy"`
+	Remark        string    `json:"remark" db:"remark"`
+	Status        string    `json:"status" db:"status"`
+	RiskWarning   string    `json:"riskWarning" db:"risk_warning"`      // 创建或最近一次修改成交要素时违反的风险限制，为空表示未违反
+	Fees          float64   `json:"fees" db:"fees"`                     // 交易费用合计（佣金、印花税、过户费等）
+	FeeManual     bool      `json:"feeManual" db:"fee_manual"`          // 费用为手动填写，不随费率表重新计算
+	Broker        string    `json:"broker" db:"broker"`                 // 导入来源券商，手动录入为空
+	BrokerTradeID string    `json:"brokerTradeId" db:"broker_trade_id"` // 券商成交编号，用于导入去重
+	LotRemaining  *int      `json:"lotRemaining" db:"lot_remaining"`    // 开仓批次剩余未平仓数量，按默认成本计算方法回放得出，非开仓日志为空
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"updated_at"`
+
+	FeeDetail *fee.Breakdown `json:"feeDetail,omitempty" db:"fee_detail"` // 费用明细
+
+	// 查询时按交易日汇率换算到用户本位币，缺少汇率时 fxRate 及换算金额为 0
+	Currency     string  `json:"currency" db:"-"` // 股票计价币种
+	Amount       float64 `json:"amount" db:"-"`   // 成交金额（原币）
+	BaseCurrency string  `json:"baseCurrency" db:"-"`
+	FxRate       float64 `json:"fxRate" db:"-"`
+	BaseAmount   float64 `json:"baseAmount" db:"-"`
+	BaseFees     float64 `json:"baseFees" db:"-"`
 }
 
 // LogCreateRequest 创建日志请求
 type LogCreateRequest struct {
 	Title       string  `json:"title"`
+	PlanID      string  `json:"planId"`
 	PlanName    string  `json:"planName"`
 	StockCode   string  `json:"stockCode" binding:"required"`
 	StockName   string  `json:"stockName"`
-	Type        string  `json:"type" binding:"required"`
+	Type        string  `json:"type" binding:"required,oneof=buy sell"`
 	TradingTime string  `json:"tradingTime" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	Quantity    int     `json:"quantity" binding:"required,gt=0"`
 	Strategy    string  `json:"strategy"`
 	Remark      string  `json:"remark"`
-	Status      string  `json:"status"`
+	Status      string  `json:"status" binding:"omitempty,oneof=pending completed cancelled failed"` // 默认 completed
+	// Fees 手动填写的费用合计，为空时按股票所属地区和板块的费率表计算
+	Fees *float64 `json:"fees" binding:"omitempty,min=0"`
+
+	// 券商导入时设置
+	Broker        string `json:"-"`
+	BrokerTradeID string `json:"-"`
 }
 
 // LogUpdateRequest 更新日志请求
 type LogUpdateRequest struct {
 	Title       *string  `json:"title,omitempty"`
+	PlanID      *string  `json:"planId,omitempty"`
 	PlanName    *string  `json:"planName,omitempty"`
 	StockCode   *string  `json:"stockCode,omitempty"`
 	StockName   *string  `json:"stockName,omitempty"`
-	Type        *string  `json:"type,omitempty"`
+	Type        *string  `json:"type,omitempty" binding:"omitempty,oneof=buy sell"`
 	TradingTime *string  `json:"tradingTime,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Quantity    *int     `json:"quantity,omitempty"`
+	Price       *float64 `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Quantity    *int     `json:"quantity,omitempty" binding:"omitempty,gt=0"`
 	Strategy    *string  `json:"strategy,omitempty"`
 	Remark      *string  `json:"remark,omitempty"`
-	Status      *string  `json:"status,omitempty"`
+	Status      *string  `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled failed"`
+	Fees        *float64 `json:"fees,omitempty" binding:"omitempty,min=0"` // 手动填写费用
+	ResetFees   bool     `json:"resetFees,omitempty"`                      // 取消手动费用，按费率表重新计算
 }
 
 // LogListRequest 日志列表请求
@@ -56,6 +102,7 @@ type LogListRequest struct {
 	Type      string `form:"type"`
 	Status    string `form:"status"`
 	StockCode string `form:"stockCode"`
+	PlanID    string `form:"planId"`
 	PlanName  string `form:"planName"`
 	StartDate string `form:"startDate"`
 	EndDate   string `form:"endDate"`
@@ -70,3 +117,9 @@ type LogListResponse struct {
 	Page     int   `json:"page"`
 	PageSize int   `json:"pageSize"`
 }
+
+// FeeRecalculateResponse 重新计算费用响应
+type FeeRecalculateResponse struct {
+	Total   int `json:"total"`   // 参与计算的日志数（不含手动填写费用的日志）
+	Updated int `json:"updated"` // 费用发生变化的日志数
+}
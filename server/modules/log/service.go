@@ -2,7 +2,15 @@ package log
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"server/modules/fee"
+	"server/modules/fx"
+	"server/modules/plan"
+	"server/modules/stock"
+	"server/modules/strategy"
+	"server/modules/user"
 	"server/storage"
 	"server/utils"
 	"strings"
@@ -11,82 +19,388 @@ import (
 
 // LogService 日志服务接口
 type LogService interface {
-	CreateLog(req *LogCreateRequest) (*Log, error)
-	GetLog(id string) (*Log, error)
-	ListLogs(req *LogListRequest) (*LogListResponse, error)
-	UpdateLog(id string, req *LogUpdateRequest) (*Log, error)
-	DeleteLog(id string) error
+	CreateLog(userID string, req *LogCreateRequest) (*Log, error)
+	GetLog(userID, id string) (*Log, error)
+	ListLogs(userID string, req *LogListRequest) (*LogListResponse, error)
+	ListAllLogs(userID string, req *LogListRequest) ([]Log, error)
+	UpdateLog(userID, id string, req *LogUpdateRequest) (*Log, error)
+	DeleteLog(userID, id string) error
+	RecalculateFees(userID string) (*FeeRecalculateResponse, error)
+	PrepareLog(userID string, req *LogCreateRequest) (*Log, error)
+	ImportLogs(userID string, logs []Log) (int, error)
+	ListBrokerTradeIDs(userID, broker string) (map[string]bool, error)
+	ExportLogs(userID string, req *LogListRequest, w utils.RowWriter) error
+	ConvertLogs(userID string, logs []Log) *fx.Converter
+}
+
+// RiskGuard 创建或修改日志前的风险限制检查
+// 返回错误表示拒绝保存，返回的警告信息会记录在日志的 riskWarning 中
+type RiskGuard interface {
+	Check(userID string, log *Log) ([]string, error)
+}
+
+// riskGuard 由风险模块在启动时注入，避免日志模块反向依赖持仓计算
+var riskGuard RiskGuard
+
+// SetRiskGuard 设置创建和修改日志时使用的风险检查
+func SetRiskGuard(guard RiskGuard) {
+	riskGuard = guard
+}
+
+// LotTracker 已成交日志变更后重新计算并保存开仓批次的剩余数量
+type LotTracker interface {
+	SyncLots(userID string, stockCodes ...string)
+}
+
+// lotTracker 由持仓模块在启动时注入
+var lotTracker LotTracker
+
+// SetLotTracker 设置日志变更后使用的批次跟踪
+func SetLotTracker(tracker LotTracker) {
+	lotTracker = tracker
 }
 
 // logService 日志服务实现
-type logService struct{}
+type logService struct {
+	planService     plan.PlanService
+	stockService    stock.StockService
+	strategyService strategy.StrategyService
+	feeService      fee.FeeService
+	userService     user.UserService
+}
 
 // NewLogService 创建日志服务
 func NewLogService() LogService {
-	return &logService{}
+	return &logService{
+		planService:     plan.NewPlanService(),
+		stockService:    stock.NewStockService(),
+		strategyService: strategy.NewStrategyService(),
+		feeService:      fee.NewFeeService(),
+		userService:     user.NewUserService(),
+	}
+}
+
+// resolvePlan 校验关联的计划属于当前用户且股票代码一致
+func (s *logService) resolvePlan(userID, planID, stockCode string) (*plan.Plan, error) {
+	p, err := s.planService.GetPlan(userID, planID)
+	if err != nil {
+		return nil, fmt.Errorf("关联的计划不存在")
+	}
+	if p.StockCode != "" && p.StockCode != stockCode {
+		return nil, fmt.Errorf("日志股票代码 %s 与计划股票代码 %s 不一致", stockCode, p.StockCode)
+	}
+	return p, nil
+}
+
+// syncPlanStatus 日志变更后同步关联计划的状态，失败不影响日志本身的写入
+func (s *logService) syncPlanStatus(userID string, planIDs ...string) {
+	synced := map[string]bool{}
+	for _, planID := range planIDs {
+		if planID == "" || synced[planID] {
+			continue
+		}
+		synced[planID] = true
+		if err := s.planService.SyncStatus(userID, planID); err != nil {
+			utils.LogWarning("同步计划状态失败，计划ID: %s, 错误: %v", planID, err)
+		}
+	}
+}
+
+// applyFees 设置日志费用，manual 不为空时使用手动填写的费用，否则按股票所属地区和板块的费率表计算
+func (s *logService) applyFees(userID string, log *Log, st *stock.Stock, manual *float64) error {
+	if manual != nil {
+		log.Fees = *manual
+		log.FeeManual = true
+		log.FeeDetail = &fee.Breakdown{Total: *manual}
+		return nil
+	}
+	breakdown, err := s.feeService.Calculate(userID, st.EffectiveRegion(), st.Category, log.Type, log.Price, log.Quantity)
+	if err != nil {
+		return err
+	}
+	log.Fees = breakdown.Total
+	log.FeeManual = false
+	log.FeeDetail = breakdown
+	return nil
+}
+
+// lookupStock 查找计算费用和换算币种所用的股票，股票已停用或不在列表中时按代码推断地区
+func (s *logService) lookupStock(userID, code string) *stock.Stock {
+	st, err := s.stockService.ResolveStock(userID, code)
+	if err != nil {
+		return &stock.Stock{Code: code}
+	}
+	return st
+}
+
+// ConvertLogs 按交易日汇率将日志的成交金额和费用换算为用户本位币，返回使用的换算器
+func (s *logService) ConvertLogs(userID string, logs []Log) *fx.Converter {
+	base := user.DefaultBaseCurrency
+	if settings, err := s.userService.GetSettings(userID); err != nil {
+		utils.LogWarning("获取用户本位币失败: %v", err)
+	} else if settings.BaseCurrency != "" {
+		base = settings.BaseCurrency
+	}
+	conv := fx.NewConverter(userID, base)
+	currencies := map[string]string{}
+	for i := range logs {
+		log := &logs[i]
+		currency, ok := currencies[log.StockCode]
+		if !ok {
+			currency = s.lookupStock(userID, log.StockCode).EffectiveCurrency()
+			currencies[log.StockCode] = currency
+		}
+		log.Currency = currency
+		log.Amount = math.Round(log.Price*float64(log.Quantity)*100) / 100
+		log.BaseCurrency = conv.Base()
+		if rate, ok := conv.Rate(currency, log.TradingTime); ok {
+			log.FxRate = rate
+			log.BaseAmount = math.Round(log.Amount*rate*100) / 100
+			log.BaseFees = math.Round(log.Fees*rate*100) / 100
+		}
+	}
+	return conv
+}
+
+// encodeFeeDetail 将费用明细编码为 JSON 文本
+func encodeFeeDetail(detail *fee.Breakdown) (sql.NullString, error) {
+	if detail == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("编码费用明细失败: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// decodeFeeDetail 解析费用明细，无法解析时忽略
+func decodeFeeDetail(raw sql.NullString) *fee.Breakdown {
+	if raw.String == "" {
+		return nil
+	}
+	detail := &fee.Breakdown{}
+	if err := json.Unmarshal([]byte(raw.String), detail); err != nil {
+		utils.LogWarning("解析费用明细失败: %v", err)
+		return nil
+	}
+	return detail
+}
+
+// nullString 空字符串写入为 NULL
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+// syncLots 日志变更后更新涉及股票的开仓批次，失败不影响日志本身的写入
+func syncLots(userID string, stockCodes ...string) {
+	if lotTracker != nil {
+		lotTracker.SyncLots(userID, stockCodes...)
+	}
+}
+
+// nullInt 将可空整数列转换为指针，NULL 时返回 nil
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
 }
 
 // CreateLog 创建日志
-func (s *logService) CreateLog(req *LogCreateRequest) (*Log, error) {
-	id := fmt.Sprintf("%d", time.Now().UnixNano())
+func (s *logService) CreateLog(userID string, req *LogCreateRequest) (*Log, error) {
 	utils.LogInfo("正在创建交易日志，股票代码: %s, 类型: %s", req.StockCode, req.Type)
 
-	// 设置默认状态
+	log, err := s.PrepareLog(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if riskGuard != nil {
+		warnings, err := riskGuard.Check(userID, log)
+		if err != nil {
+			return nil, err
+		}
+		log.RiskWarning = strings.Join(warnings, "；")
+	}
+
+	if _, err := insertLog(storage.GetDB(), log); err != nil {
+		utils.LogError("创建交易日志失败，ID: %s, 错误: %v", log.ID, err)
+		return nil, fmt.Errorf("创建日志失败: %w", err)
+	}
+
+	utils.LogInfo("交易日志创建成功，ID: %s", log.ID)
+	s.syncPlanStatus(userID, log.PlanID)
+	syncLots(userID, log.StockCode)
+	return log, nil
+}
+
+// PrepareLog 校验创建请求并生成日志（含费用），不写入数据库
+func (s *logService) PrepareLog(userID string, req *LogCreateRequest) (*Log, error) {
+	// 未传状态时视为已成交，记录尚未成交的委托需显式传 pending
 	status := req.Status
 	if status == "" {
-		status = "pending"
+		status = StatusCompleted
+	}
+
+	st, err := s.stockService.ResolveStock(userID, req.StockCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.strategyService.ValidateStrategy(userID, strategy.KindTrading, req.Strategy); err != nil {
+		return nil, err
+	}
+
+	planName := req.PlanName
+	if req.PlanID != "" {
+		p, err := s.resolvePlan(userID, req.PlanID, req.StockCode)
+		if err != nil {
+			return nil, err
+		}
+		planName = p.Name
 	}
 
 	log := &Log{
-		ID:          id,
-		Title:       req.Title,
-		PlanName:    req.PlanName,
-		StockCode:   req.StockCode,
-		StockName:   req.StockName,
-		Type:        req.Type,
-		TradingTime: req.TradingTime,
-		Price:       req.Price,
-		Quantity:    req.Quantity,
-		Strategy:    req.Strategy,
-		Remark:      req.Remark,
-		Status:      status,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:            fmt.Sprintf("%d", time.Now().UnixNano()),
+		UserID:        userID,
+		Title:         req.Title,
+		PlanID:        req.PlanID,
+		PlanName:      planName,
+		StockCode:     req.StockCode,
+		StockName:     st.Name,
+		Type:          req.Type,
+		TradingTime:   req.TradingTime,
+		Price:         req.Price,
+		Quantity:      req.Quantity,
+		Strategy:      req.Strategy,
+		Remark:        req.Remark,
+		Status:        status,
+		Broker:        req.Broker,
+		BrokerTradeID: req.BrokerTradeID,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.applyFees(userID, log, st, req.Fees); err != nil {
+		return nil, err
 	}
+	return log, nil
+}
 
-	query := `INSERT INTO logs (
-		id, title, plan_name, stock_code, stock_name, type, trading_time,
-		price, quantity, strategy, remark, status, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+// execer 可执行写入语句的数据库或事务
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertLog 写入一条日志，同一券商成交编号已存在时忽略并返回 false
+func insertLog(db execer, log *Log) (bool, error) {
+	feeDetail, err := encodeFeeDetail(log.FeeDetail)
+	if err != nil {
+		return false, err
+	}
 
-	_, err := storage.GetDB().Exec(query,
-		log.ID, log.Title, log.PlanName, log.StockCode, log.StockName, log.Type,
+	query := `INSERT INTO logs (
+		id, user_id, title, plan_id, plan_name, stock_code, stock_name, type, trading_time,
+		price, quantity, strategy, remark, status, risk_warning, fees, fee_detail, fee_manual,
+		broker, broker_trade_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (user_id, broker, broker_trade_id) WHERE broker_trade_id IS NOT NULL DO NOTHING`
+
+	result, err := db.Exec(query,
+		log.ID, log.UserID, log.Title, nullString(log.PlanID), log.PlanName, log.StockCode, log.StockName, log.Type,
 		log.TradingTime, log.Price, log.Quantity, log.Strategy, log.Remark,
-		log.Status, log.CreatedAt, log.UpdatedAt,
+		log.Status, nullString(log.RiskWarning), log.Fees, feeDetail, log.FeeManual,
+		nullString(log.Broker), nullString(log.BrokerTradeID), log.CreatedAt, log.UpdatedAt,
 	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
 
+// ImportLogs 在一个事务中批量写入导入的日志，已导入过的券商成交编号跳过，返回实际写入的条数
+// 导入的是历史成交，不做风险限制检查
+func (s *logService) ImportLogs(userID string, logs []Log) (int, error) {
+	tx, err := storage.GetDB().SQL.Begin()
 	if err != nil {
-		utils.LogError("创建交易日志失败，ID: %s, 错误: %v", id, err)
-		return nil, fmt.Errorf("创建日志失败: %w", err)
+		return 0, fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 批量生成时纳秒时间戳可能重复，按序号递增保证ID唯一
+	base := time.Now().UnixNano()
+	imported := 0
+	for i := range logs {
+		log := &logs[i]
+		log.ID = fmt.Sprintf("%d", base+int64(i))
+		log.UserID = userID
+		ok, err := insertLog(tx, log)
+		if err != nil {
+			return 0, fmt.Errorf("写入第 %d 条日志失败: %w", i+1, err)
+		}
+		if ok {
+			imported++
+		}
 	}
 
-	utils.LogInfo("交易日志创建成功，ID: %s", id)
-	return log, nil
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+	utils.LogInfo("批量导入交易日志完成，共 %d 条，写入 %d 条", len(logs), imported)
+	codes := []string{}
+	seen := map[string]bool{}
+	for _, log := range logs {
+		if !seen[log.StockCode] {
+			seen[log.StockCode] = true
+			codes = append(codes, log.StockCode)
+		}
+	}
+	syncLots(userID, codes...)
+	return imported, nil
+}
+
+// ListBrokerTradeIDs 获取用户在指定券商下已导入的成交编号
+func (s *logService) ListBrokerTradeIDs(userID, broker string) (map[string]bool, error) {
+	rows, err := storage.GetDB().Query(
+		"SELECT broker_trade_id FROM logs WHERE user_id = ? AND broker = ? AND broker_trade_id IS NOT NULL",
+		userID, broker,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("查询已导入成交失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("扫描已导入成交失败: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
 }
 
 // GetLog 获取日志详情
-func (s *logService) GetLog(id string) (*Log, error) {
+func (s *logService) GetLog(userID, id string) (*Log, error) {
 	utils.LogDebug("正在获取交易日志详情，ID: %s", id)
-	query := `SELECT id, plan_name, stock_code, stock_name, type, trading_time,
-		price, quantity, strategy, remark, created_at, updated_at, title, status
-		FROM logs WHERE id = ?`
+	query := `SELECT id, user_id, plan_name, stock_code, stock_name, type, trading_time,
+		price, quantity, strategy, remark, created_at, updated_at, title, status, plan_id, risk_warning,
+		fees, fee_detail, fee_manual, broker, broker_trade_id, lot_remaining
+		FROM logs WHERE id = ? AND user_id = ?`
 
 	log := &Log{}
-	var title, planName, stockName, strategy, remark, status sql.NullString
-	err := storage.GetDB().QueryRow(query, id).Scan(
-		&log.ID, &planName, &log.StockCode, &stockName, &log.Type,
+	var title, planID, planName, stockName, strategy, remark, status, riskWarning, feeDetail, broker, brokerTradeID sql.NullString
+	var lotRemaining sql.NullInt64
+	err := storage.GetDB().QueryRow(query, id, userID).Scan(
+		&log.ID, &log.UserID, &planName, &log.StockCode, &stockName, &log.Type,
 		&log.TradingTime, &log.Price, &log.Quantity, &strategy, &remark,
-		&log.CreatedAt, &log.UpdatedAt, &title, &status,
+		&log.CreatedAt, &log.UpdatedAt, &title, &status, &planID, &riskWarning,
+		&log.Fees, &feeDetail, &log.FeeManual, &broker, &brokerTradeID, &lotRemaining,
 	)
 
 	if err != nil {
@@ -100,21 +414,30 @@ func (s *logService) GetLog(id string) (*Log, error) {
 
 	// 处理 NULL 值
 	log.Title = title.String
+	log.PlanID = planID.String
 	log.PlanName = planName.String
 	log.StockName = stockName.String
 	log.Strategy = strategy.String
 	log.Remark = remark.String
 	log.Status = status.String
+	log.RiskWarning = riskWarning.String
+	log.FeeDetail = decodeFeeDetail(feeDetail)
+	log.Broker = broker.String
+	log.BrokerTradeID = brokerTradeID.String
+	log.LotRemaining = nullInt(lotRemaining)
+
+	converted := []Log{*log}
+	s.ConvertLogs(userID, converted)
+	log = &converted[0]
 
 	utils.LogDebug("成功获取交易日志详情，ID: %s", id)
 	return log, nil
 }
 
-// ListLogs 获取日志列表
-func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
-	// 构建查询条件
-	where := []string{"1=1"}
-	args := []interface{}{}
+// buildLogFilter 根据列表请求构建查询条件
+func buildLogFilter(userID string, req *LogListRequest) (string, []interface{}) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
 
 	if req.Keyword != "" {
 		where = append(where, "(title LIKE ? OR plan_name LIKE ? OR stock_name LIKE ? OR stock_code LIKE ?)")
@@ -137,6 +460,11 @@ func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
 		args = append(args, req.StockCode)
 	}
 
+	if req.PlanID != "" {
+		where = append(where, "plan_id = ?")
+		args = append(args, req.PlanID)
+	}
+
 	if req.PlanName != "" {
 		where = append(where, "plan_name LIKE ?")
 		args = append(args, "%"+req.PlanName+"%")
@@ -152,7 +480,63 @@ func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
 		args = append(args, req.EndDate)
 	}
 
-	whereClause := strings.Join(where, " AND ")
+	return strings.Join(where, " AND "), args
+}
+
+// scanLog 扫描一行日志数据
+func scanLog(rows *sql.Rows) (*Log, error) {
+	log := &Log{}
+	var title, planID, planName, stockName, strategy, remark, status, riskWarning, feeDetail, broker, brokerTradeID sql.NullString
+	var lotRemaining sql.NullInt64
+	err := rows.Scan(
+		&log.ID, &log.UserID, &planName, &log.StockCode, &stockName, &log.Type,
+		&log.TradingTime, &log.Price, &log.Quantity, &strategy, &remark,
+		&log.CreatedAt, &log.UpdatedAt, &title, &status, &planID, &riskWarning,
+		&log.Fees, &feeDetail, &log.FeeManual, &broker, &brokerTradeID, &lotRemaining,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("扫描日志数据失败: %w", err)
+	}
+
+	// 处理 NULL 值
+	log.Title = title.String
+	log.PlanID = planID.String
+	log.PlanName = planName.String
+	log.StockName = stockName.String
+	log.Strategy = strategy.String
+	log.Remark = remark.String
+	log.Status = status.String
+	log.RiskWarning = riskWarning.String
+	log.FeeDetail = decodeFeeDetail(feeDetail)
+	log.Broker = broker.String
+	log.BrokerTradeID = brokerTradeID.String
+	log.LotRemaining = nullInt(lotRemaining)
+	return log, nil
+}
+
+// scanLogs 扫描日志查询结果
+func scanLogs(rows *sql.Rows) ([]Log, error) {
+	var logs []Log
+	for rows.Next() {
+		log, err := scanLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, *log)
+	}
+
+	// 检查遍历过程中是否有错误
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历日志数据失败: %w", err)
+	}
+
+	return logs, nil
+}
+
+// ListLogs 获取日志列表
+func (s *logService) ListLogs(userID string, req *LogListRequest) (*LogListResponse, error) {
+	// 构建查询条件
+	whereClause, args := buildLogFilter(userID, req)
 
 	// 获取总数
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM logs WHERE %s", whereClause)
@@ -174,8 +558,9 @@ func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
 	offset := (page - 1) * pageSize
 
 	// 获取数据
-	query := fmt.Sprintf(`SELECT id, plan_name, stock_code, stock_name, type, trading_time,
-		price, quantity, strategy, remark, created_at, updated_at, title, status
+	query := fmt.Sprintf(`SELECT id, user_id, plan_name, stock_code, stock_name, type, trading_time,
+		price, quantity, strategy, remark, created_at, updated_at, title, status, plan_id, risk_warning,
+		fees, fee_detail, fee_manual, broker, broker_trade_id, lot_remaining
 		FROM logs WHERE %s ORDER BY trading_time DESC LIMIT ? OFFSET ?`, whereClause)
 
 	args = append(args, pageSize, offset)
@@ -186,34 +571,11 @@ func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
 	}
 	defer rows.Close()
 
-	var logs []Log
-	for rows.Next() {
-		log := &Log{}
-		var title, planName, stockName, strategy, remark, status sql.NullString
-		err = rows.Scan(
-			&log.ID, &planName, &log.StockCode, &stockName, &log.Type,
-			&log.TradingTime, &log.Price, &log.Quantity, &strategy, &remark,
-			&log.CreatedAt, &log.UpdatedAt, &title, &status,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("扫描日志数据失败: %w", err)
-		}
-
-		// 处理 NULL 值
-		log.Title = title.String
-		log.PlanName = planName.String
-		log.StockName = stockName.String
-		log.Strategy = strategy.String
-		log.Remark = remark.String
-		log.Status = status.String
-
-		logs = append(logs, *log)
-	}
-
-	// 检查遍历过程中是否有错误
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历日志数据失败: %w", err)
+	logs, err := scanLogs(rows)
+	if err != nil {
+		return nil, err
 	}
+	s.ConvertLogs(userID, logs)
 
 	return &LogListResponse{
 		Items:    logs,
@@ -223,11 +585,107 @@ func (s *logService) ListLogs(req *LogListRequest) (*LogListResponse, error) {
 	}, nil
 }
 
+// exportBatchSize 导出时每批换算汇率的日志条数
+const exportBatchSize = 500
+
+// exportHeader 日志导出的表头
+var exportHeader = []interface{}{
+	"交易时间", "股票代码", "股票名称", "方向", "价格", "数量", "成交金额", "费用", "币种", "本位币", "汇率", "本位币金额",
+	"策略", "计划", "标题", "状态", "备注", "券商", "成交编号", "风险提示", "创建时间",
+}
+
+// ExportLogs 按列表筛选条件导出全部日志，按交易时间倒序逐批写出，不在内存中保留全部数据
+func (s *logService) ExportLogs(userID string, req *LogListRequest, w utils.RowWriter) error {
+	whereClause, args := buildLogFilter(userID, req)
+	query := fmt.Sprintf(`SELECT id, user_id, plan_name, stock_code, stock_name, type, trading_time,
+		price, quantity, strategy, remark, created_at, updated_at, title, status, plan_id, risk_warning,
+		fees, fee_detail, fee_manual, broker, broker_trade_id, lot_remaining
+		FROM logs WHERE %s ORDER BY trading_time DESC`, whereClause)
+
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("查询日志列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	if err := w.WriteRow(exportHeader); err != nil {
+		return err
+	}
+	batch := make([]Log, 0, exportBatchSize)
+	flush := func() error {
+		s.ConvertLogs(userID, batch)
+		for _, l := range batch {
+			typeName := "买入"
+			if l.Type == TypeSell {
+				typeName = "卖出"
+			}
+			// 缺少汇率时本位币列留空，避免与金额为 0 混淆
+			var fxRate, baseAmount interface{}
+			if l.FxRate > 0 {
+				fxRate, baseAmount = l.FxRate, l.BaseAmount
+			}
+			err := w.WriteRow([]interface{}{
+				l.TradingTime, l.StockCode, l.StockName, typeName, l.Price, l.Quantity, l.Amount, l.Fees,
+				l.Currency, l.BaseCurrency, fxRate, baseAmount,
+				l.Strategy, l.PlanName, l.Title, l.Status, l.Remark, l.Broker, l.BrokerTradeID, l.RiskWarning, l.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	count := 0
+	for rows.Next() {
+		log, err := scanLog(rows)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, *log)
+		count++
+		if len(batch) == exportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历日志数据失败: %w", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	utils.LogInfo("导出交易日志完成，共 %d 条", count)
+	return nil
+}
+
+// ListAllLogs 获取满足条件的全部日志（不分页），按交易时间升序排列，供持仓、统计等按时间回放使用
+// 回放和统计时需传入 Status: StatusCompleted，只计入已成交的日志
+func (s *logService) ListAllLogs(userID string, req *LogListRequest) ([]Log, error) {
+	whereClause, args := buildLogFilter(userID, req)
+
+	query := fmt.Sprintf(`SELECT id, user_id, plan_name, stock_code, stock_name, type, trading_time,
+		price, quantity, strategy, remark, created_at, updated_at, title, status, plan_id, risk_warning,
+		fees, fee_detail, fee_manual, broker, broker_trade_id, lot_remaining
+		FROM logs WHERE %s ORDER BY trading_time ASC, created_at ASC`, whereClause)
+
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询日志列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLogs(rows)
+}
+
 // UpdateLog 更新日志
-func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
+func (s *logService) UpdateLog(userID, id string, req *LogUpdateRequest) (*Log, error) {
 	utils.LogInfo("正在更新交易日志，ID: %s", id)
 	// 检查日志是否存在
-	_, err := s.GetLog(id)
+	existing, err := s.GetLog(userID, id)
 	if err != nil {
 		utils.LogWarning("更新交易日志失败，日志不存在，ID: %s", id)
 		return nil, fmt.Errorf("日志不存在: %w", err)
@@ -241,6 +699,37 @@ func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
 		setParts = append(setParts, "title = ?")
 		args = append(args, *req.Title)
 	}
+
+	var st *stock.Stock
+	if req.StockCode != nil {
+		st, err = s.stockService.ResolveStock(userID, *req.StockCode)
+		if err != nil {
+			return nil, err
+		}
+		req.StockName = &st.Name
+	}
+
+	// 更换关联计划或股票代码时重新校验计划
+	planID := existing.PlanID
+	if req.PlanID != nil {
+		planID = *req.PlanID
+		setParts = append(setParts, "plan_id = ?")
+		args = append(args, nullString(planID))
+	}
+	if planID != "" && (req.PlanID != nil || req.StockCode != nil) {
+		stockCode := existing.StockCode
+		if req.StockCode != nil {
+			stockCode = *req.StockCode
+		}
+		p, err := s.resolvePlan(userID, planID, stockCode)
+		if err != nil {
+			return nil, err
+		}
+		if req.PlanID != nil {
+			name := p.Name
+			req.PlanName = &name
+		}
+	}
 	if req.PlanName != nil {
 		setParts = append(setParts, "plan_name = ?")
 		args = append(args, *req.PlanName)
@@ -270,6 +759,11 @@ func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
 		args = append(args, *req.Quantity)
 	}
 	if req.Strategy != nil {
+		if *req.Strategy != existing.Strategy {
+			if err := s.strategyService.ValidateStrategy(userID, strategy.KindTrading, *req.Strategy); err != nil {
+				return nil, err
+			}
+		}
 		setParts = append(setParts, "strategy = ?")
 		args = append(args, *req.Strategy)
 	}
@@ -282,6 +776,57 @@ func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
 		args = append(args, *req.Status)
 	}
 
+	// 修改后的日志，用于重新计算费用和检查风险限制
+	merged := *existing
+	if req.StockCode != nil {
+		merged.StockCode = *req.StockCode
+		merged.StockName = st.Name
+	}
+	if req.Type != nil {
+		merged.Type = *req.Type
+	}
+	if req.TradingTime != nil {
+		merged.TradingTime = *req.TradingTime
+	}
+	if req.Price != nil {
+		merged.Price = *req.Price
+	}
+	if req.Quantity != nil {
+		merged.Quantity = *req.Quantity
+	}
+	if req.Status != nil {
+		merged.Status = *req.Status
+	}
+
+	// 成交要素变化时按费率表重新计算费用，手动填写的费用保持不变
+	tradeChanged := req.StockCode != nil || req.Type != nil || req.Price != nil || req.Quantity != nil
+	if req.Fees != nil || req.ResetFees || (tradeChanged && !existing.FeeManual) {
+		if st == nil {
+			st = s.lookupStock(userID, existing.StockCode)
+		}
+		if err := s.applyFees(userID, &merged, st, req.Fees); err != nil {
+			return nil, err
+		}
+		feeDetail, err := encodeFeeDetail(merged.FeeDetail)
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, "fees = ?", "fee_detail = ?", "fee_manual = ?")
+		args = append(args, merged.Fees, feeDetail, merged.FeeManual)
+	}
+
+	// 成交要素、交易时间或状态变化时按修改后的日志重新检查风险限制，改为取消或失败的日志不再占用风险敞口
+	statusChanged := req.Status != nil && *req.Status != existing.Status
+	if riskGuard != nil && (tradeChanged || req.TradingTime != nil || statusChanged) &&
+		merged.Status != StatusCancelled && merged.Status != StatusFailed {
+		warnings, err := riskGuard.Check(userID, &merged)
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, "risk_warning = ?")
+		args = append(args, nullString(strings.Join(warnings, "；")))
+	}
+
 	if len(setParts) == 0 {
 		return nil, fmt.Errorf("没有要更新的字段")
 	}
@@ -291,9 +836,9 @@ func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
 	args = append(args, time.Now())
 
 	// 添加WHERE条件
-	args = append(args, id)
+	args = append(args, id, userID)
 
-	query := fmt.Sprintf("UPDATE logs SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	query := fmt.Sprintf("UPDATE logs SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
 	_, err = storage.GetDB().Exec(query, args...)
 	if err != nil {
 		utils.LogError("更新交易日志失败，ID: %s, 错误: %v", id, err)
@@ -301,22 +846,28 @@ func (s *logService) UpdateLog(id string, req *LogUpdateRequest) (*Log, error) {
 	}
 
 	utils.LogInfo("交易日志更新成功，ID: %s", id)
+	s.syncPlanStatus(userID, existing.PlanID, planID)
+	if req.StockCode != nil {
+		syncLots(userID, existing.StockCode, *req.StockCode)
+	} else {
+		syncLots(userID, existing.StockCode)
+	}
 	// 返回更新后的日志
-	return s.GetLog(id)
+	return s.GetLog(userID, id)
 }
 
 // DeleteLog 删除日志
-func (s *logService) DeleteLog(id string) error {
+func (s *logService) DeleteLog(userID, id string) error {
 	utils.LogInfo("正在删除交易日志，ID: %s", id)
 	// 检查日志是否存在
-	_, err := s.GetLog(id)
+	existing, err := s.GetLog(userID, id)
 	if err != nil {
 		utils.LogWarning("删除交易日志失败，日志不存在，ID: %s", id)
 		return fmt.Errorf("日志不存在: %w", err)
 	}
 
-	query := "DELETE FROM logs WHERE id = ?"
-	result, err := storage.GetDB().Exec(query, id)
+	query := "DELETE FROM logs WHERE id = ? AND user_id = ?"
+	result, err := storage.GetDB().Exec(query, id, userID)
 	if err != nil {
 		utils.LogError("删除交易日志失败，ID: %s, 错误: %v", id, err)
 		return fmt.Errorf("删除日志失败: %w", err)
@@ -334,5 +885,50 @@ func (s *logService) DeleteLog(id string) error {
 	}
 
 	utils.LogInfo("交易日志删除成功，ID: %s", id)
+	s.syncPlanStatus(userID, existing.PlanID)
+	syncLots(userID, existing.StockCode)
 	return nil
 }
+
+// RecalculateFees 按当前费率表重新计算用户全部日志的费用，手动填写费用的日志不变
+func (s *logService) RecalculateFees(userID string) (*FeeRecalculateResponse, error) {
+	logs, err := s.ListAllLogs(userID, &LogListRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &FeeRecalculateResponse{}
+	stocks := map[string]*stock.Stock{}
+	for i := range logs {
+		log := &logs[i]
+		if log.FeeManual {
+			continue
+		}
+		resp.Total++
+
+		st, ok := stocks[log.StockCode]
+		if !ok {
+			st = s.lookupStock(userID, log.StockCode)
+			stocks[log.StockCode] = st
+		}
+		previous, hadDetail := log.Fees, log.FeeDetail != nil
+		if err := s.applyFees(userID, log, st, nil); err != nil {
+			return nil, err
+		}
+		if log.Fees == previous && hadDetail {
+			continue
+		}
+		feeDetail, err := encodeFeeDetail(log.FeeDetail)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := storage.GetDB().Exec("UPDATE logs SET fees = ?, fee_detail = ?, updated_at = ? WHERE id = ? AND user_id = ?",
+			log.Fees, feeDetail, time.Now(), log.ID, userID); err != nil {
+			return nil, fmt.Errorf("更新日志费用失败: %w", err)
+		}
+		resp.Updated++
+	}
+
+	utils.LogInfo("日志费用重新计算完成，共 %d 条，更新 %d 条", resp.Total, resp.Updated)
+	return resp, nil
+}
@@ -1,25 +1,85 @@
 package modules
 
 import (
+	"server/modules/alert"
+	"server/modules/analytics"
+	"server/modules/fee"
+	"server/modules/fx"
+	"server/modules/home"
+	"server/modules/importer"
 	"server/modules/log"
+	"server/modules/notification"
 	"server/modules/plan"
+	"server/modules/position"
+	"server/modules/price"
+	"server/modules/quote"
 	"server/modules/review"
+	"server/modules/risk"
 	"server/modules/stock"
+	"server/modules/strategy"
+	"server/modules/user"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RegisterAllRoutes 注册所有模块的路由
+// RegisterPublicRoutes 注册无需登录即可访问的路由
+func RegisterPublicRoutes(r *gin.RouterGroup) {
+	// 注册用户模块路由（注册、登录、小程序登录）
+	user.RegisterUserRoutes(r)
+}
+
+// RegisterAllRoutes 注册所有需要登录的模块路由
 func RegisterAllRoutes(r *gin.RouterGroup) {
+	// 创建日志前检查风险限制
+	log.SetRiskGuard(risk.NewRiskService())
+	// 日志变更后更新开仓批次
+	log.SetLotTracker(position.NewLotTracker())
+
 	// 注册股票模块路由
 	stock.RegisterStockRoutes(r)
 
+	// 注册策略模块路由
+	strategy.RegisterStrategyRoutes(r)
+
+	// 注册费率模块路由
+	fee.RegisterFeeRoutes(r)
+
 	// 注册计划模块路由
 	plan.RegisterPlanRoutes(r)
 
 	// 注册日志模块路由
 	log.RegisterLogRoutes(r)
 
+	// 注册券商成交导入路由
+	importer.RegisterImportRoutes(r)
+
 	// 注册复盘模块路由
 	review.RegisterReviewRoutes(r)
+
+	// 注册持仓模块路由
+	position.RegisterPositionRoutes(r)
+
+	// 注册交易绩效分析路由
+	analytics.RegisterAnalyticsRoutes(r)
+
+	// 注册风险限制路由
+	risk.RegisterRiskRoutes(r)
+
+	// 注册首页模块路由
+	home.RegisterHomeRoutes(r)
+
+	// 注册行情模块路由
+	price.RegisterPriceRoutes(r)
+
+	// 注册计划价格提醒路由
+	alert.RegisterAlertRoutes(r)
+
+	// 注册通知路由
+	notification.RegisterNotificationRoutes(r)
+
+	// 注册汇率路由
+	fx.RegisterFxRoutes(r)
+
+	// 注册行情报价路由
+	quote.RegisterQuoteRoutes(r)
 }
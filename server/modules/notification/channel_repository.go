@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"database/sql"
+	"encoding/json"
+	"server/storage"
+)
+
+// ChannelRepository 通知渠道数据访问层
+type ChannelRepository struct{}
+
+// NewChannelRepository 创建通知渠道仓库
+func NewChannelRepository() *ChannelRepository {
+	return &ChannelRepository{}
+}
+
+// Save 保存渠道设置，同一用户的同一渠道已存在时更新
+func (r *ChannelRepository) Save(ch *Channel) error {
+	events, err := json.Marshal(ch.Events)
+	if err != nil {
+		return err
+	}
+	_, err = storage.GetDB().Exec(`INSERT INTO notification_channels (
+		id, user_id, channel, target, secret, events, enabled, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (user_id, channel) DO UPDATE SET
+		target = excluded.target, secret = excluded.secret, events = excluded.events,
+		enabled = excluded.enabled, updated_at = excluded.updated_at`,
+		ch.ID, ch.UserID, ch.Channel, ch.Target, ch.Secret, string(events), ch.Enabled, ch.CreatedAt, ch.UpdatedAt,
+	)
+	return err
+}
+
+// Get 获取用户的某个渠道，不存在时返回 nil
+func (r *ChannelRepository) Get(userID, channel string) (*Channel, error) {
+	rows, err := storage.GetDB().Query(channelSelect+" WHERE user_id = ? AND channel = ?", userID, channel)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := scanChannels(rows)
+	if err != nil || len(channels) == 0 {
+		return nil, err
+	}
+	return &channels[0], nil
+}
+
+// List 获取用户的全部渠道
+func (r *ChannelRepository) List(userID string) ([]Channel, error) {
+	rows, err := storage.GetDB().Query(channelSelect+" WHERE user_id = ? ORDER BY channel", userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanChannels(rows)
+}
+
+// ListEnabled 获取已启用的渠道，userID 为空时返回全部用户
+func (r *ChannelRepository) ListEnabled(userID string) ([]Channel, error) {
+	query := channelSelect + " WHERE enabled = 1"
+	args := []interface{}{}
+	if userID != "" {
+		query += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	rows, err := storage.GetDB().Query(query+" ORDER BY user_id, channel", args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanChannels(rows)
+}
+
+// Delete 删除渠道设置，返回是否存在
+func (r *ChannelRepository) Delete(userID, channel string) (bool, error) {
+	result, err := storage.GetDB().Exec("DELETE FROM notification_channels WHERE user_id = ? AND channel = ?", userID, channel)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+const channelSelect = `SELECT id, user_id, channel, target, secret, events, enabled, created_at, updated_at
+	FROM notification_channels`
+
+// scanChannels 读取渠道查询结果并关闭 rows
+func scanChannels(rows *sql.Rows) ([]Channel, error) {
+	defer rows.Close()
+	channels := []Channel{}
+	for rows.Next() {
+		var ch Channel
+		var target, secret, events sql.NullString
+		if err := rows.Scan(
+			&ch.ID, &ch.UserID, &ch.Channel, &target, &secret, &events, &ch.Enabled, &ch.CreatedAt, &ch.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ch.Target = target.String
+		ch.Secret = secret.String
+		ch.HasSecret = ch.Secret != ""
+		ch.Events = []string{}
+		if events.String != "" {
+			if err := json.Unmarshal([]byte(events.String), &ch.Events); err != nil {
+				return nil, err
+			}
+		}
+		channels = append(channels, ch)
+	}
+	return channels, rows.Err()
+}
@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"server/utils"
+)
+
+// Dispatcher 后台定时发送发件箱中到期的通知
+type Dispatcher struct {
+	service  NotificationService
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher 创建通知发送任务，interval 不大于 0 时不启动
+func NewDispatcher(interval time.Duration) *Dispatcher {
+	return &Dispatcher{service: NewNotificationService(), interval: interval}
+}
+
+// Start 在后台按间隔发送，启动后立即处理一次
+func (d *Dispatcher) Start() {
+	if d.interval <= 0 {
+		utils.LogInfo("通知发送任务未启用")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			d.runOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	utils.LogInfo("通知发送任务已启动，处理间隔: %s", d.interval)
+}
+
+// Stop 停止发送并等待正在发送的通知结束
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+	utils.LogInfo("通知发送任务已停止")
+}
+
+// runOnce 处理一批到期通知
+func (d *Dispatcher) runOnce(ctx context.Context) {
+	// 单次处理出错不影响后续处理
+	defer func() {
+		if r := recover(); r != nil {
+			utils.LogError("通知发送异常: %v", r)
+		}
+	}()
+
+	processed, err := d.service.Dispatch(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			utils.LogError("通知发送失败: %v", err)
+		}
+		return
+	}
+	if processed > 0 {
+		utils.LogInfo("通知发送完成，处理 %d 条", processed)
+	}
+}
@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"server/config"
+)
+
+// EmailNotifier 通过 SMTP 发送纯文本邮件，465 端口使用 TLS 直连，其他端口在服务器支持时使用 STARTTLS
+type EmailNotifier struct {
+	cfg *config.AppConfig
+}
+
+// NewEmailNotifier 创建邮件发送后端
+func NewEmailNotifier() *EmailNotifier {
+	return &EmailNotifier{cfg: config.Load()}
+}
+
+// Channel 渠道名称
+func (n *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+// Send 发送邮件
+func (n *EmailNotifier) Send(ctx context.Context, ch *Channel, msg *Message) error {
+	if n.cfg.SMTPHost == "" {
+		return fmt.Errorf("未配置 SMTP 服务器")
+	}
+	if ch.Target == "" {
+		return fmt.Errorf("未设置收件邮箱")
+	}
+	from := n.cfg.SMTPFrom
+	if from == "" {
+		from = n.cfg.SMTPUsername
+	}
+
+	addr := net.JoinHostPort(n.cfg.SMTPHost, strconv.Itoa(n.cfg.SMTPPort))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if n.cfg.SMTPPort == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: n.cfg.SMTPHost})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && n.cfg.SMTPPort != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.SMTPHost}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS 失败: %w", err)
+		}
+	}
+	if n.cfg.SMTPUsername != "" {
+		auth := smtp.PlainAuth("", n.cfg.SMTPUsername, n.cfg.SMTPPassword, n.cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP 认证失败: %w", err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("SMTP 发件人被拒绝: %w", err)
+	}
+	if err := client.Rcpt(ch.Target); err != nil {
+		return fmt.Errorf("SMTP 收件人被拒绝: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildEmail(from, ch.Target, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildEmail 生成 UTF-8 纯文本邮件，正文使用 base64 编码
+func buildEmail(from, to string, msg *Message) []byte {
+	body := msg.Content
+	if msg.Link != "" {
+		body += "\n\n" + msg.Link
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.CreatedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterNotificationRoutes 注册通知路由
+func RegisterNotificationRoutes(r *gin.RouterGroup) {
+	notificationService := NewNotificationService()
+
+	g := r.Group("/notifications")
+	{
+		g.GET("/channels/getList", func(c *gin.Context) {
+			channels, err := notificationService.ListChannels(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, gin.H{"list": channels, "total": len(channels), "events": Events})
+		})
+
+		g.PUT("/channels/save/:channel", func(c *gin.Context) {
+			var req ChannelSaveRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, err.Error())
+				return
+			}
+
+			ch, err := notificationService.SaveChannel(middleware.GetCurrentUserID(c), c.Param("channel"), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeInvalid, err.Error())
+				return
+			}
+
+			handler.Success(c, ch)
+		})
+
+		g.DELETE("/channels/delete/:channel", func(c *gin.Context) {
+			if err := notificationService.DeleteChannel(middleware.GetCurrentUserID(c), c.Param("channel")); err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, nil)
+		})
+
+		// 立即发送测试通知，返回渠道后端的错误信息
+		g.POST("/channels/test/:channel", func(c *gin.Context) {
+			if err := notificationService.TestChannel(c.Request.Context(), middleware.GetCurrentUserID(c), c.Param("channel")); err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, nil)
+		})
+
+		g.GET("/outbox/getList", func(c *gin.Context) {
+			req := &OutboxListRequest{
+				Channel: c.Query("channel"),
+				Event:   c.Query("event"),
+				Status:  c.Query("status"),
+			}
+			if pageStr := c.Query("page"); pageStr != "" {
+				if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+					req.Page = page
+				}
+			}
+			if pageSizeStr := c.Query("pageSize"); pageSizeStr != "" {
+				if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
+					req.PageSize = pageSize
+				}
+			}
+
+			response, err := notificationService.ListOutbox(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.POST("/outbox/retry/:id", func(c *gin.Context) {
+			item, err := notificationService.RetryOutbox(middleware.GetCurrentUserID(c), c.Param("id"))
+			if err != nil {
+				handler.Error(c, handler.CodeInvalid, err.Error())
+				return
+			}
+
+			handler.Success(c, item)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package notification
+
+import "time"
+
+// 通知渠道
+const (
+	ChannelWebhook = "webhook" // HTTP 回调
+	ChannelEmail   = "email"   // SMTP 邮件
+	ChannelWechat  = "wechat"  // 微信小程序订阅消息
+)
+
+// 通知事件
+const (
+	EventPlanAlert      = "plan_alert"      // 计划价格提醒
+	EventRiskBreach     = "risk_breach"     // 违反风险限制
+	EventReviewReminder = "review_reminder" // 复盘提醒
+)
+
+// Events 支持订阅的全部事件
+var Events = []string{EventPlanAlert, EventRiskBreach, EventReviewReminder}
+
+// 发件箱状态
+const (
+	StatusPending = "pending" // 等待发送（含等待重试）
+	StatusSent    = "sent"    // 已发送
+	StatusFailed  = "failed"  // 超过重试次数
+)
+
+// Channel 用户的通知渠道设置，每个渠道一条
+type Channel struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"-" db:"user_id"`
+	Channel   string    `json:"channel" db:"channel"`
+	Target    string    `json:"target" db:"target"` // webhook 地址、邮箱或微信 openid；为空时使用账号邮箱或小程序登录绑定的 openid
+	Secret    string    `json:"-" db:"secret"`      // webhook 签名密钥
+	HasSecret bool      `json:"hasSecret" db:"-"`
+	Events    []string  `json:"events" db:"events"` // 订阅的事件，为空表示全部
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ChannelSaveRequest 保存通知渠道请求，渠道不存在时创建
+type ChannelSaveRequest struct {
+	Target  *string  `json:"target,omitempty"`
+	Secret  *string  `json:"secret,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+// Message 一条通知的内容
+type Message struct {
+	Event     string    `json:"event"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Link      string    `json:"link,omitempty"` // 小程序页面路径或网页地址
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OutboxItem 发件箱中的一条通知
+type OutboxItem struct {
+	ID            string     `json:"id" db:"id"`
+	UserID        string     `json:"-" db:"user_id"`
+	Channel       string     `json:"channel" db:"channel"`
+	Event         string     `json:"event" db:"event"`
+	DedupeKey     string     `json:"-" db:"dedupe_key"`
+	Title         string     `json:"title" db:"title"`
+	Content       string     `json:"content" db:"content"`
+	Link          string     `json:"link" db:"link"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastError     string     `json:"lastError" db:"last_error"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	SentAt        *time.Time `json:"sentAt" db:"sent_at"`
+}
+
+// OutboxListRequest 发件箱列表请求
+type OutboxListRequest struct {
+	Channel  string `form:"channel"`
+	Event    string `form:"event"`
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
+
+// OutboxListResponse 发件箱列表响应
+type OutboxListResponse struct {
+	Items    []OutboxItem `json:"list"`
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"pageSize"`
+}
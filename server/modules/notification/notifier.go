@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Notifier 通知发送后端，每个渠道一个
+type Notifier interface {
+	// Channel 渠道名称
+	Channel() string
+	// Send 发送一条通知，target 为渠道设置中的接收方
+	Send(ctx context.Context, ch *Channel, msg *Message) error
+}
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   = map[string]Notifier{}
+	setupOnce   sync.Once
+)
+
+// RegisterNotifier 注册或替换渠道的发送后端，可在本地调试时替换为不真正发送的实现
+func RegisterNotifier(n Notifier) {
+	setupOnce.Do(setupNotifiers)
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers[n.Channel()] = n
+}
+
+// notifierFor 获取渠道的发送后端
+func notifierFor(channel string) (Notifier, error) {
+	setupOnce.Do(setupNotifiers)
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	n, ok := notifiers[channel]
+	if !ok {
+		return nil, fmt.Errorf("不支持的通知渠道: %s", channel)
+	}
+	return n, nil
+}
+
+// setupNotifiers 注册内置的发送后端
+func setupNotifiers() {
+	for _, n := range []Notifier{NewWebhookNotifier(), NewEmailNotifier(), NewWechatNotifier()} {
+		notifiers[n.Channel()] = n
+	}
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+	"time"
+)
+
+// OutboxRepository 通知发件箱数据访问层
+type OutboxRepository struct{}
+
+// NewOutboxRepository 创建发件箱仓库
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+// Create 写入待发送通知，同一用户同一渠道的去重键已存在时不写入并返回 false
+func (r *OutboxRepository) Create(item *OutboxItem) (bool, error) {
+	var dedupeKey interface{}
+	if item.DedupeKey != "" {
+		dedupeKey = item.DedupeKey
+	}
+	result, err := storage.GetDB().Exec(`INSERT INTO notification_outbox (
+		id, user_id, channel, event, dedupe_key, title, content, link, status, attempts, next_attempt_at, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (user_id, channel, dedupe_key) WHERE dedupe_key IS NOT NULL DO NOTHING`,
+		item.ID, item.UserID, item.Channel, item.Event, dedupeKey, item.Title, item.Content, item.Link,
+		item.Status, item.Attempts, item.NextAttemptAt.Unix(), item.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetByID 获取用户的一条通知，不存在时返回 nil
+func (r *OutboxRepository) GetByID(userID, id string) (*OutboxItem, error) {
+	rows, err := storage.GetDB().Query(outboxSelect+" WHERE user_id = ? AND id = ?", userID, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := scanOutbox(rows)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// ListDue 获取到期待发送的通知，按计划发送时间排序
+func (r *OutboxRepository) ListDue(now time.Time, limit int) ([]OutboxItem, error) {
+	rows, err := storage.GetDB().Query(outboxSelect+` WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at LIMIT ?`, StatusPending, now.Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+// MarkSent 标记为已发送
+func (r *OutboxRepository) MarkSent(id string, attempts int, sentAt time.Time) error {
+	_, err := storage.GetDB().Exec(`UPDATE notification_outbox SET status = ?, attempts = ?, last_error = NULL, sent_at = ?
+		WHERE id = ?`, StatusSent, attempts, sentAt, id)
+	return err
+}
+
+// MarkFailed 记录发送失败，status 为 pending 时在 nextAttemptAt 后重试
+func (r *OutboxRepository) MarkFailed(id, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := storage.GetDB().Exec(`UPDATE notification_outbox SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?
+		WHERE id = ?`, status, attempts, nextAttemptAt.Unix(), lastError, id)
+	return err
+}
+
+// Reset 将失败的通知重新放入发送队列并清零发送次数
+func (r *OutboxRepository) Reset(id string, now time.Time) error {
+	_, err := storage.GetDB().Exec(`UPDATE notification_outbox SET status = ?, attempts = 0, next_attempt_at = ?
+		WHERE id = ?`, StatusPending, now.Unix(), id)
+	return err
+}
+
+// List 分页查询发件箱，按创建时间倒序
+func (r *OutboxRepository) List(userID string, req *OutboxListRequest, page, pageSize int) ([]OutboxItem, int, error) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
+
+	if req.Channel != "" {
+		where = append(where, "channel = ?")
+		args = append(args, req.Channel)
+	}
+	if req.Event != "" {
+		where = append(where, "event = ?")
+		args = append(args, req.Event)
+	}
+	if req.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, req.Status)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := storage.GetDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM notification_outbox WHERE %s", whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := storage.GetDB().Query(fmt.Sprintf("%s WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?", outboxSelect, whereClause), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := scanOutbox(rows)
+	return items, total, err
+}
+
+const outboxSelect = `SELECT id, user_id, channel, event, dedupe_key, title, content, link, status, attempts,
+	next_attempt_at, last_error, created_at, sent_at
+	FROM notification_outbox`
+
+// scanOutbox 读取发件箱查询结果并关闭 rows
+func scanOutbox(rows *sql.Rows) ([]OutboxItem, error) {
+	defer rows.Close()
+	items := []OutboxItem{}
+	for rows.Next() {
+		var item OutboxItem
+		var dedupeKey, content, link, lastError sql.NullString
+		var nextAttemptAt int64
+		var sentAt sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.UserID, &item.Channel, &item.Event, &dedupeKey, &item.Title, &content, &link,
+			&item.Status, &item.Attempts, &nextAttemptAt, &lastError, &item.CreatedAt, &sentAt,
+		); err != nil {
+			return nil, err
+		}
+		item.DedupeKey = dedupeKey.String
+		item.Content = content.String
+		item.Link = link.String
+		item.LastError = lastError.String
+		item.NextAttemptAt = time.Unix(nextAttemptAt, 0)
+		if sentAt.Valid {
+			item.SentAt = &sentAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
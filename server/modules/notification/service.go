@@ -0,0 +1,404 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"server/config"
+	"server/modules/user"
+	"server/utils"
+)
+
+// 发送失败后的重试间隔，每次翻倍直到上限
+const (
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+// dispatchBatchSize 每次处理的到期通知数量上限
+const dispatchBatchSize = 50
+
+// sendTimeout 单条通知的发送超时时间
+const sendTimeout = 15 * time.Second
+
+// resolveTimeout 保存 webhook 时解析域名的超时时间
+const resolveTimeout = 5 * time.Second
+
+// NotificationService 通知服务接口
+type NotificationService interface {
+	ListChannels(userID string) ([]Channel, error)
+	SaveChannel(userID, channel string, req *ChannelSaveRequest) (*Channel, error)
+	DeleteChannel(userID, channel string) error
+	// TestChannel 立即向渠道发送一条测试通知，不经过发件箱
+	TestChannel(ctx context.Context, userID, channel string) error
+	ListOutbox(userID string, req *OutboxListRequest) (*OutboxListResponse, error)
+	RetryOutbox(userID, id string) (*OutboxItem, error)
+	// Notify 为用户订阅了该事件的每个启用渠道写入一条待发送通知，dedupeKey 相同的通知只写入一次
+	Notify(userID, event, dedupeKey string, msg *Message) error
+	// Subscribers 获取至少有一个启用渠道订阅了该事件的用户
+	Subscribers(event string) ([]string, error)
+	// Dispatch 发送到期的通知，返回处理的数量
+	Dispatch(ctx context.Context) (int, error)
+}
+
+// notificationService 通知服务实现
+type notificationService struct {
+	channelRepo *ChannelRepository
+	outboxRepo  *OutboxRepository
+	userService user.UserService
+	maxAttempts int
+}
+
+// NewNotificationService 创建通知服务
+func NewNotificationService() NotificationService {
+	maxAttempts := config.Load().NotifyMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &notificationService{
+		channelRepo: NewChannelRepository(),
+		outboxRepo:  NewOutboxRepository(),
+		userService: user.NewUserService(),
+		maxAttempts: maxAttempts,
+	}
+}
+
+// ListChannels 获取通知渠道列表
+func (s *notificationService) ListChannels(userID string) ([]Channel, error) {
+	channels, err := s.channelRepo.List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+	return channels, nil
+}
+
+// SaveChannel 创建或更新通知渠道，未传的字段保持不变；新建的渠道默认启用并订阅全部事件
+func (s *notificationService) SaveChannel(userID, channel string, req *ChannelSaveRequest) (*Channel, error) {
+	if !validChannel(channel) {
+		return nil, fmt.Errorf("不支持的通知渠道: %s", channel)
+	}
+
+	ch, err := s.channelRepo.Get(userID, channel)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+	now := time.Now()
+	if ch == nil {
+		ch = &Channel{
+			ID:        utils.GenerateID(),
+			UserID:    userID,
+			Channel:   channel,
+			Events:    []string{},
+			Enabled:   true,
+			CreatedAt: now,
+		}
+	}
+
+	if req.Target != nil {
+		ch.Target = *req.Target
+	}
+	if req.Secret != nil {
+		ch.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		for _, event := range req.Events {
+			if !validEvent(event) {
+				return nil, fmt.Errorf("不支持的通知事件: %s", event)
+			}
+		}
+		ch.Events = req.Events
+	}
+	if req.Enabled != nil {
+		ch.Enabled = *req.Enabled
+	}
+	if err := validateTarget(ch); err != nil {
+		return nil, err
+	}
+	ch.HasSecret = ch.Secret != ""
+	ch.UpdatedAt = now
+
+	if err := s.channelRepo.Save(ch); err != nil {
+		return nil, fmt.Errorf("保存通知渠道失败: %w", err)
+	}
+	return ch, nil
+}
+
+// DeleteChannel 删除通知渠道
+func (s *notificationService) DeleteChannel(userID, channel string) error {
+	deleted, err := s.channelRepo.Delete(userID, channel)
+	if err != nil {
+		return fmt.Errorf("删除通知渠道失败: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("通知渠道不存在")
+	}
+	return nil
+}
+
+// TestChannel 发送测试通知，渠道停用时也可测试
+func (s *notificationService) TestChannel(ctx context.Context, userID, channel string) error {
+	ch, err := s.channelRepo.Get(userID, channel)
+	if err != nil {
+		return fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+	if ch == nil {
+		return fmt.Errorf("通知渠道不存在")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	msg := &Message{
+		Event:     "test",
+		Title:     "测试通知",
+		Content:   "这是一条测试通知，收到说明通知渠道设置正确。",
+		CreatedAt: time.Now(),
+	}
+	if err := s.send(ctx, ch, msg); err != nil {
+		return fmt.Errorf("发送测试通知失败: %w", err)
+	}
+	return nil
+}
+
+// ListOutbox 获取发件箱列表
+func (s *notificationService) ListOutbox(userID string, req *OutboxListRequest) (*OutboxListResponse, error) {
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	items, total, err := s.outboxRepo.List(userID, req, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取发件箱失败: %w", err)
+	}
+
+	return &OutboxListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// RetryOutbox 将未发送的通知重新放入队列，立即由后台发送
+func (s *notificationService) RetryOutbox(userID, id string) (*OutboxItem, error) {
+	item, err := s.outboxRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知失败: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("通知不存在")
+	}
+	if item.Status == StatusSent {
+		return nil, fmt.Errorf("通知已发送")
+	}
+
+	now := time.Now()
+	if err := s.outboxRepo.Reset(id, now); err != nil {
+		return nil, fmt.Errorf("重试通知失败: %w", err)
+	}
+	item.Status = StatusPending
+	item.Attempts = 0
+	item.NextAttemptAt = now
+	return item, nil
+}
+
+// Notify 写入待发送通知，由后台按渠道发送
+func (s *notificationService) Notify(userID, event, dedupeKey string, msg *Message) error {
+	channels, err := s.channelRepo.ListEnabled(userID)
+	if err != nil {
+		return fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+
+	now := time.Now()
+	for _, ch := range channels {
+		if !subscribed(&ch, event) {
+			continue
+		}
+		item := &OutboxItem{
+			ID:            utils.GenerateID(),
+			UserID:        userID,
+			Channel:       ch.Channel,
+			Event:         event,
+			DedupeKey:     dedupeKey,
+			Title:         msg.Title,
+			Content:       msg.Content,
+			Link:          msg.Link,
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if _, err := s.outboxRepo.Create(item); err != nil {
+			return fmt.Errorf("写入通知失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// Subscribers 获取订阅了事件的用户
+func (s *notificationService) Subscribers(event string) ([]string, error) {
+	channels, err := s.channelRepo.ListEnabled("")
+	if err != nil {
+		return nil, fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+
+	seen := map[string]bool{}
+	userIDs := []string{}
+	for _, ch := range channels {
+		if subscribed(&ch, event) && !seen[ch.UserID] {
+			seen[ch.UserID] = true
+			userIDs = append(userIDs, ch.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+// Dispatch 逐条发送到期的通知，失败时按退避间隔重试，超过最大次数标记为失败
+func (s *notificationService) Dispatch(ctx context.Context) (int, error) {
+	items, err := s.outboxRepo.ListDue(time.Now(), dispatchBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("获取待发送通知失败: %w", err)
+	}
+
+	processed := 0
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		s.deliver(ctx, &items[i])
+		processed++
+	}
+	return processed, nil
+}
+
+// deliver 发送一条通知并记录结果
+func (s *notificationService) deliver(ctx context.Context, item *OutboxItem) {
+	attempts := item.Attempts + 1
+
+	ch, err := s.channelRepo.Get(item.UserID, item.Channel)
+	if err == nil && (ch == nil || !ch.Enabled) {
+		// 渠道已删除或停用，不再重试
+		if err := s.outboxRepo.MarkFailed(item.ID, StatusFailed, attempts, time.Now(), "通知渠道已停用"); err != nil {
+			utils.LogError("更新通知状态失败，通知ID: %s, 错误: %v", item.ID, err)
+		}
+		return
+	}
+	if err == nil {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = s.send(sendCtx, ch, &Message{
+			Event:     item.Event,
+			Title:     item.Title,
+			Content:   item.Content,
+			Link:      item.Link,
+			CreatedAt: item.CreatedAt,
+		})
+		cancel()
+	}
+
+	now := time.Now()
+	if err == nil {
+		if err := s.outboxRepo.MarkSent(item.ID, attempts, now); err != nil {
+			utils.LogError("更新通知状态失败，通知ID: %s, 错误: %v", item.ID, err)
+		}
+		return
+	}
+
+	status := StatusPending
+	if attempts >= s.maxAttempts {
+		status = StatusFailed
+	}
+	utils.LogWarning("发送通知失败，通知ID: %s, 渠道: %s, 第 %d 次, 错误: %v", item.ID, item.Channel, attempts, err)
+	if err := s.outboxRepo.MarkFailed(item.ID, status, attempts, now.Add(retryDelay(attempts)), err.Error()); err != nil {
+		utils.LogError("更新通知状态失败，通知ID: %s, 错误: %v", item.ID, err)
+	}
+}
+
+// send 补全接收方后交给渠道后端发送
+func (s *notificationService) send(ctx context.Context, ch *Channel, msg *Message) error {
+	n, err := notifierFor(ch.Channel)
+	if err != nil {
+		return err
+	}
+	resolved := *ch
+	if resolved.Target == "" {
+		switch resolved.Channel {
+		case ChannelEmail:
+			account, err := s.userService.GetUser(ch.UserID)
+			if err != nil {
+				return err
+			}
+			resolved.Target = account.Email
+		case ChannelWechat:
+			binding, err := s.userService.GetWechatBinding(ch.UserID)
+			if err != nil {
+				return err
+			}
+			if binding != nil {
+				resolved.Target = binding.OpenID
+			}
+		}
+	}
+	return n.Send(ctx, &resolved, msg)
+}
+
+// retryDelay 第 attempts 次失败后的重试间隔
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// subscribed 判断渠道是否订阅了事件，未设置订阅事件时接收全部事件
+func subscribed(ch *Channel, event string) bool {
+	if len(ch.Events) == 0 {
+		return true
+	}
+	for _, e := range ch.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// validChannel 判断渠道是否支持
+func validChannel(channel string) bool {
+	return channel == ChannelWebhook || channel == ChannelEmail || channel == ChannelWechat
+}
+
+// validEvent 判断事件是否支持订阅
+func validEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTarget 校验渠道的接收方格式
+func validateTarget(ch *Channel) error {
+	switch ch.Channel {
+	case ChannelWebhook:
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		defer cancel()
+		return checkWebhookURL(ctx, ch.Target)
+	case ChannelEmail:
+		if ch.Target != "" {
+			if _, err := mail.ParseAddress(ch.Target); err != nil {
+				return fmt.Errorf("邮箱格式不正确")
+			}
+		}
+	}
+	return nil
+}
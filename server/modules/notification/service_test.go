@@ -0,0 +1,296 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"server/storage"
+)
+
+// publicHook 测试用的公网 webhook 地址，使用 IP 避免依赖域名解析
+const publicHook = "https://93.184.216.34/hook"
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "notification-test")
+	if err != nil {
+		panic(err)
+	}
+	db, err := storage.OpenSQLite(filepath.Join(dir, "test.db"))
+	if err == nil {
+		err = db.Migrate()
+	}
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	db.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// stubNotifier 记录发送的通知，前 fail 次发送返回错误
+type stubNotifier struct {
+	channel string
+	mu      sync.Mutex
+	fail    int
+	sent    []Message
+}
+
+func (n *stubNotifier) Channel() string {
+	return n.channel
+}
+
+func (n *stubNotifier) Send(ctx context.Context, ch *Channel, msg *Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, *msg)
+	if n.fail > 0 {
+		n.fail--
+		return errors.New("stub failure")
+	}
+	return nil
+}
+
+// useStub 用 stub 替换渠道的发送后端，测试结束后恢复内置后端
+func useStub(t *testing.T, stub *stubNotifier) {
+	t.Helper()
+	RegisterNotifier(stub)
+	t.Cleanup(func() {
+		for _, n := range []Notifier{NewWebhookNotifier(), NewEmailNotifier(), NewWechatNotifier()} {
+			RegisterNotifier(n)
+		}
+	})
+}
+
+// newTestService 创建不依赖用户服务的通知服务，并清空渠道和发件箱
+func newTestService(t *testing.T, maxAttempts int) *notificationService {
+	t.Helper()
+	for _, table := range []string{"notification_channels", "notification_outbox"} {
+		if _, err := storage.GetDB().Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("清空 %s 失败: %v", table, err)
+		}
+	}
+	return &notificationService{
+		channelRepo: NewChannelRepository(),
+		outboxRepo:  NewOutboxRepository(),
+		maxAttempts: maxAttempts,
+	}
+}
+
+// saveChannel 保存渠道，失败时终止测试
+func saveChannel(t *testing.T, s *notificationService, userID, channel string, req *ChannelSaveRequest) {
+	t.Helper()
+	if _, err := s.SaveChannel(userID, channel, req); err != nil {
+		t.Fatalf("保存 %s 渠道失败: %v", channel, err)
+	}
+}
+
+// outbox 获取用户的全部通知
+func outbox(t *testing.T, s *notificationService, userID string) []OutboxItem {
+	t.Helper()
+	resp, err := s.ListOutbox(userID, &OutboxListRequest{PageSize: 100})
+	if err != nil {
+		t.Fatalf("获取发件箱失败: %v", err)
+	}
+	return resp.Items
+}
+
+// makeDue 把全部待发送通知的计划发送时间提前到现在
+func makeDue(t *testing.T) {
+	t.Helper()
+	if _, err := storage.GetDB().Exec("UPDATE notification_outbox SET next_attempt_at = 0"); err != nil {
+		t.Fatalf("更新计划发送时间失败: %v", err)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDispatchRetriesWithBackoff(t *testing.T) {
+	s := newTestService(t, 3)
+	stub := &stubNotifier{channel: ChannelWebhook, fail: 3}
+	useStub(t, stub)
+	saveChannel(t, s, "u1", ChannelWebhook, &ChannelSaveRequest{Target: strPtr(publicHook)})
+
+	if err := s.Notify("u1", EventPlanAlert, "", &Message{Title: "价格提醒"}); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	items := outbox(t, s, "u1")
+	if len(items) != 1 {
+		t.Fatalf("发件箱应有 1 条通知，实际 %d 条", len(items))
+	}
+	id := items[0].ID
+
+	dispatch := func(wantProcessed int) *OutboxItem {
+		t.Helper()
+		processed, err := s.Dispatch(context.Background())
+		if err != nil {
+			t.Fatalf("Dispatch 失败: %v", err)
+		}
+		if processed != wantProcessed {
+			t.Fatalf("Dispatch 处理了 %d 条，want %d", processed, wantProcessed)
+		}
+		item, err := s.outboxRepo.GetByID("u1", id)
+		if err != nil || item == nil {
+			t.Fatalf("获取通知失败: %v", err)
+		}
+		return item
+	}
+
+	// 前两次失败按 1 分钟、2 分钟退避，仍为待发送
+	for attempt, delay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		start := time.Now()
+		item := dispatch(1)
+		if item.Status != StatusPending || item.Attempts != attempt+1 || item.LastError != "stub failure" {
+			t.Fatalf("第 %d 次失败后: status=%s attempts=%d lastError=%q", attempt+1, item.Status, item.Attempts, item.LastError)
+		}
+		wait := item.NextAttemptAt.Sub(start)
+		if wait < delay-time.Second || wait > delay+time.Second {
+			t.Fatalf("第 %d 次失败后的重试间隔为 %v，want %v", attempt+1, wait, delay)
+		}
+		// 未到重试时间不会发送
+		dispatch(0)
+		makeDue(t)
+	}
+
+	// 达到最大次数后标记为失败，不再重试
+	item := dispatch(1)
+	if item.Status != StatusFailed || item.Attempts != 3 {
+		t.Fatalf("超过最大次数后: status=%s attempts=%d", item.Status, item.Attempts)
+	}
+	makeDue(t)
+	dispatch(0)
+
+	// 手动重试后重新发送并成功
+	if _, err := s.RetryOutbox("u1", id); err != nil {
+		t.Fatalf("RetryOutbox 失败: %v", err)
+	}
+	item = dispatch(1)
+	if item.Status != StatusSent || item.Attempts != 1 || item.LastError != "" || item.SentAt == nil {
+		t.Fatalf("重试成功后: status=%s attempts=%d lastError=%q sentAt=%v", item.Status, item.Attempts, item.LastError, item.SentAt)
+	}
+	if _, err := s.RetryOutbox("u1", id); err == nil {
+		t.Fatal("已发送的通知不应允许重试")
+	}
+	if len(stub.sent) != 4 || stub.sent[3].Title != "价格提醒" {
+		t.Fatalf("stub 收到 %d 条通知: %+v", len(stub.sent), stub.sent)
+	}
+}
+
+func TestDispatchDisabledChannel(t *testing.T) {
+	s := newTestService(t, 3)
+	stub := &stubNotifier{channel: ChannelWebhook}
+	useStub(t, stub)
+	saveChannel(t, s, "u1", ChannelWebhook, &ChannelSaveRequest{Target: strPtr(publicHook)})
+	if err := s.Notify("u1", EventRiskBreach, "", &Message{Title: "风险"}); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	saveChannel(t, s, "u1", ChannelWebhook, &ChannelSaveRequest{Enabled: new(bool)})
+
+	if _, err := s.Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch 失败: %v", err)
+	}
+	items := outbox(t, s, "u1")
+	if len(items) != 1 || items[0].Status != StatusFailed || len(stub.sent) != 0 {
+		t.Fatalf("渠道停用后应直接失败且不发送: %+v, 发送 %d 条", items, len(stub.sent))
+	}
+}
+
+func TestNotifyDedupe(t *testing.T) {
+	s := newTestService(t, 3)
+	saveChannel(t, s, "u1", ChannelWebhook, &ChannelSaveRequest{Target: strPtr(publicHook)})
+	saveChannel(t, s, "u1", ChannelEmail, &ChannelSaveRequest{
+		Target: strPtr("u1@example.com"),
+		Events: []string{EventPlanAlert},
+	})
+	saveChannel(t, s, "u2", ChannelWebhook, &ChannelSaveRequest{Target: strPtr(publicHook)})
+
+	tests := []struct {
+		name      string
+		userID    string
+		event     string
+		dedupeKey string
+		want      map[string]int // 每个用户的通知总数
+	}{
+		{"首次通知写入每个订阅的渠道", "u1", EventPlanAlert, "plan:1:2024-01-02", map[string]int{"u1": 2}},
+		{"相同去重键不重复写入", "u1", EventPlanAlert, "plan:1:2024-01-02", map[string]int{"u1": 2}},
+		{"不同去重键单独写入", "u1", EventPlanAlert, "plan:1:2024-01-03", map[string]int{"u1": 4}},
+		{"去重键按用户区分", "u2", EventPlanAlert, "plan:1:2024-01-02", map[string]int{"u1": 4, "u2": 1}},
+		{"未订阅事件的渠道不写入", "u1", EventRiskBreach, "risk:1", map[string]int{"u1": 5}},
+		{"空去重键不去重", "u1", EventRiskBreach, "", map[string]int{"u1": 6}},
+		{"空去重键再次写入", "u1", EventRiskBreach, "", map[string]int{"u1": 7, "u2": 1}},
+	}
+	for _, tt := range tests {
+		if err := s.Notify(tt.userID, tt.event, tt.dedupeKey, &Message{Title: tt.name}); err != nil {
+			t.Fatalf("%s: Notify 失败: %v", tt.name, err)
+		}
+		for userID, want := range tt.want {
+			if got := len(outbox(t, s, userID)); got != want {
+				t.Fatalf("%s: 用户 %s 有 %d 条通知，want %d", tt.name, userID, got, want)
+			}
+		}
+	}
+}
+
+func TestValidateWebhookTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		ok     bool
+	}{
+		{publicHook, true},
+		{"http://93.184.216.34:8080/hook?a=1", true},
+		{"", false},
+		{"ftp://93.184.216.34/hook", false},
+		{"http:///hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://localhost:8080/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://172.16.3.4/hook", false},
+		{"http://192.168.1.10/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+	}
+	for _, tt := range tests {
+		err := validateTarget(&Channel{Channel: ChannelWebhook, Target: tt.target})
+		if (err == nil) != tt.ok {
+			t.Errorf("validateTarget(%q) error = %v, want ok=%v", tt.target, err, tt.ok)
+		}
+	}
+
+	s := newTestService(t, 3)
+	if _, err := s.SaveChannel("u1", ChannelWebhook, &ChannelSaveRequest{Target: strPtr("http://127.0.0.1/hook")}); err == nil {
+		t.Fatal("保存内网 webhook 地址应失败")
+	}
+	if channels, _ := s.ListChannels("u1"); len(channels) != 0 {
+		t.Fatalf("保存失败时不应写入渠道: %+v", channels)
+	}
+}
@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// webhookIPAllowed 判断 webhook 是否允许访问该地址，测试中替换以访问本机的测试服务器
+var webhookIPAllowed = publicIP
+
+// publicIP 判断是否为公网地址，回环、链路本地、内网等地址不允许作为 webhook 目标，防止借 webhook 访问服务器所在的内网
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// checkWebhookURL 保存渠道时校验 webhook 地址，域名解析出的任一地址不是公网地址时拒绝
+func checkWebhookURL(ctx context.Context, target string) error {
+	u, err := url.Parse(target)
+	if target == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook 地址必须是 http 或 https 地址")
+	}
+	host := u.Hostname()
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("无法解析 webhook 地址: %s", host)
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if !webhookIPAllowed(ip) {
+			return fmt.Errorf("webhook 地址不能是本机或内网地址")
+		}
+	}
+	return nil
+}
+
+// dialControl 连接前校验解析后的实际地址，保存后域名改为解析到内网或重定向到内网地址时同样拒绝
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !webhookIPAllowed(ip) {
+		return fmt.Errorf("webhook 地址不能是本机或内网地址: %s", host)
+	}
+	return nil
+}
+
+// WebhookNotifier 以 JSON POST 到用户设置的地址，设置了密钥时在 X-Signature 头中附带 HMAC-SHA256 签名
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建 webhook 发送后端，直接连接目标地址而不经过代理，以便校验实际连接的地址
+func NewWebhookNotifier() *WebhookNotifier {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialControl}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &WebhookNotifier{client: &http.Client{Timeout: 10 * time.Second, Transport: transport}}
+}
+
+// Channel 渠道名称
+func (n *WebhookNotifier) Channel() string {
+	return ChannelWebhook
+}
+
+// Send 发送通知，非 2xx 响应视为失败
+func (n *WebhookNotifier) Send(ctx context.Context, ch *Channel, msg *Message) error {
+	if ch.Target == "" {
+		return fmt.Errorf("未设置 webhook 地址")
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ch.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook 地址无效: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event", msg.Event)
+	if ch.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(ch.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 webhook 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook 返回 %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// allowLoopback 允许 webhook 访问本机，httptest 服务器监听在 127.0.0.1
+func allowLoopback(t *testing.T) {
+	t.Helper()
+	old := webhookIPAllowed
+	webhookIPAllowed = func(net.IP) bool { return true }
+	t.Cleanup(func() { webhookIPAllowed = old })
+}
+
+// received webhook 服务器收到的请求
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer 启动记录请求的 webhook 服务器，返回 status 作为响应状态码
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, chan received) {
+	t.Helper()
+	requests := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(status)
+		w.Write([]byte("  server says no  "))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func TestWebhookSignature(t *testing.T) {
+	allowLoopback(t)
+	srv, requests := newWebhookServer(t, http.StatusNoContent)
+	msg := &Message{
+		Event:     EventPlanAlert,
+		Title:     "价格提醒",
+		Content:   "600000 触及 10.50",
+		CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{"有密钥时附带签名", "s3cret"},
+		{"无密钥时不签名", ""},
+	}
+	for _, tt := range tests {
+		ch := &Channel{Channel: ChannelWebhook, Target: srv.URL + "/hook", Secret: tt.secret}
+		if err := NewWebhookNotifier().Send(context.Background(), ch, msg); err != nil {
+			t.Fatalf("%s: Send 失败: %v", tt.name, err)
+		}
+		req := <-requests
+
+		var got Message
+		if err := json.Unmarshal(req.body, &got); err != nil || got.Title != msg.Title || !got.CreatedAt.Equal(msg.CreatedAt) {
+			t.Fatalf("%s: 请求体 %s 解析为 %+v, err=%v", tt.name, req.body, got, err)
+		}
+		if req.header.Get("X-Event") != EventPlanAlert || req.header.Get("Content-Type") != "application/json" {
+			t.Fatalf("%s: 请求头不正确: %v", tt.name, req.header)
+		}
+
+		signature := req.header.Get("X-Signature")
+		if tt.secret == "" {
+			if signature != "" {
+				t.Fatalf("%s: 不应有签名，实际 %s", tt.name, signature)
+			}
+			continue
+		}
+		mac := hmac.New(sha256.New, []byte(tt.secret))
+		mac.Write(req.body)
+		if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+			t.Fatalf("%s: 签名 %s，want %s", tt.name, signature, want)
+		}
+	}
+}
+
+func TestWebhookNon2xx(t *testing.T) {
+	allowLoopback(t)
+	srv, requests := newWebhookServer(t, http.StatusInternalServerError)
+
+	err := NewWebhookNotifier().Send(context.Background(), &Channel{Channel: ChannelWebhook, Target: srv.URL}, &Message{Title: "x"})
+	<-requests
+	if err == nil || !strings.Contains(err.Error(), "500: server says no") {
+		t.Fatalf("非 2xx 响应应返回错误，实际 %v", err)
+	}
+}
+
+func TestWebhookRejectsLoopbackAtDial(t *testing.T) {
+	srv, requests := newWebhookServer(t, http.StatusOK)
+
+	err := NewWebhookNotifier().Send(context.Background(), &Channel{Channel: ChannelWebhook, Target: srv.URL}, &Message{Title: "x"})
+	if err == nil || !strings.Contains(err.Error(), "内网地址") {
+		t.Fatalf("连接本机地址应被拒绝，实际 %v", err)
+	}
+	select {
+	case <-requests:
+		t.Fatal("被拒绝的地址不应收到请求")
+	default:
+	}
+}
@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"server/config"
+)
+
+// thingMaxRunes 订阅消息 thing 类型字段的长度上限
+const thingMaxRunes = 20
+
+// WechatNotifier 发送微信小程序订阅消息
+type WechatNotifier struct {
+	cfg    *config.WechatConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewWechatNotifier 创建微信订阅消息发送后端
+func NewWechatNotifier() *WechatNotifier {
+	return &WechatNotifier{cfg: config.GetWechatConfig(), client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Channel 渠道名称
+func (n *WechatNotifier) Channel() string {
+	return ChannelWechat
+}
+
+// wechatResponse 微信接口的公共返回字段
+type wechatResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// Send 发送订阅消息，target 为用户的 openid
+func (n *WechatNotifier) Send(ctx context.Context, ch *Channel, msg *Message) error {
+	if n.cfg.AppID == "" || n.cfg.AppSecret == "" || n.cfg.SubscribeTemplateID == "" {
+		return fmt.Errorf("未配置微信小程序订阅消息")
+	}
+	if ch.Target == "" {
+		return fmt.Errorf("未设置微信 openid")
+	}
+	token, err := n.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := msg.Link
+	if page == "" {
+		page = n.cfg.SubscribePage
+	}
+	payload := map[string]interface{}{
+		"touser":      ch.Target,
+		"template_id": n.cfg.SubscribeTemplateID,
+		"data":        n.templateData(msg),
+	}
+	if page != "" {
+		payload["page"] = page
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := n.cfg.APIBaseURL + "/cgi-bin/message/subscribe/send?access_token=" + url.QueryEscape(token)
+	var result wechatResponse
+	if err := n.call(ctx, http.MethodPost, endpoint, body, &result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		// access_token 失效时清除缓存，下次发送重新获取
+		if result.ErrCode == 40001 || result.ErrCode == 42001 {
+			n.mu.Lock()
+			n.accessToken = ""
+			n.mu.Unlock()
+		}
+		return fmt.Errorf("发送订阅消息失败: %d %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// templateData 按字段映射生成模板数据
+func (n *WechatNotifier) templateData(msg *Message) map[string]interface{} {
+	values := map[string]string{
+		"event":   msg.Event,
+		"title":   msg.Title,
+		"content": msg.Content,
+		"time":    msg.CreatedAt.Format("2006-01-02 15:04"),
+	}
+	data := map[string]interface{}{}
+	for _, pair := range strings.Split(n.cfg.SubscribeFields, ",") {
+		name, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || key == "" {
+			continue
+		}
+		value := values[name]
+		if strings.HasPrefix(key, "thing") {
+			value = truncateRunes(value, thingMaxRunes)
+		}
+		data[key] = map[string]string{"value": value}
+	}
+	return data
+}
+
+// token 获取 access_token，过期前复用
+func (n *WechatNotifier) token(ctx context.Context) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.accessToken != "" && time.Now().Before(n.expiresAt) {
+		return n.accessToken, nil
+	}
+
+	query := url.Values{}
+	query.Set("grant_type", "client_credential")
+	query.Set("appid", n.cfg.AppID)
+	query.Set("secret", n.cfg.AppSecret)
+	var result struct {
+		wechatResponse
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := n.call(ctx, http.MethodGet, n.cfg.APIBaseURL+"/cgi-bin/token?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if result.ErrCode != 0 || result.AccessToken == "" {
+		return "", fmt.Errorf("获取微信 access_token 失败: %d %s", result.ErrCode, result.ErrMsg)
+	}
+
+	// 提前一分钟过期，避免临界时刻使用失效的 token
+	n.accessToken = result.AccessToken
+	n.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return n.accessToken, nil
+}
+
+// call 请求微信接口并解析 JSON 返回
+func (n *WechatNotifier) call(ctx context.Context, method, endpoint string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求微信接口失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("微信接口返回 %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析微信接口返回失败: %w", err)
+	}
+	return nil
+}
+
+// truncateRunes 按字符截断
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
@@ -0,0 +1,201 @@
+package plan
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"server/modules/price"
+	"server/modules/stock"
+)
+
+// runBacktest 按K线逐根模拟计划
+// 开仓：设置了目标价时以限价单处理，K线触及目标价时成交，跳空越过目标价时以开盘价成交；未设置目标价时以第一根K线开盘价成交
+// 平仓：开仓后（含开仓K线）依次检查止损和止盈，同一根K线同时触及时按止损处理；到期或数据结束时以最后收盘价计算
+// expired 表示回测区间之后仍有行情，即区间因计划到期而结束
+func runBacktest(plan *Plan, bars []price.Price, interval string, expired bool) *BacktestResult {
+	long := entryType(plan) == TypeBuy
+	quantity := plan.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	result := &BacktestResult{
+		PlanID:     plan.ID,
+		StockCode:  plan.StockCode,
+		Type:       entryType(plan),
+		Interval:   interval,
+		BarCount:   len(bars),
+		Quantity:   quantity,
+		ExitReason: ExitNotFilled,
+		Fills:      []BacktestFill{},
+	}
+	if len(bars) == 0 {
+		return result
+	}
+	result.StartTime = bars[0].TradeTime
+	result.EndTime = bars[len(bars)-1].TradeTime
+
+	entryIndex := -1
+	for i, bar := range bars {
+		fill, ok := entryFill(plan, bar, long)
+		if ok {
+			entryIndex = i
+			result.Entered = true
+			result.EntryTime = bar.TradeTime
+			result.EntryPrice = fill
+			result.Fills = append(result.Fills, BacktestFill{
+				Type: result.Type, Time: bar.TradeTime, Price: fill, Quantity: quantity, Reason: "entry",
+			})
+			break
+		}
+	}
+	if entryIndex < 0 {
+		return result
+	}
+
+	worst, best := result.EntryPrice, result.EntryPrice
+	exitIndex := len(bars) - 1
+	for i := entryIndex; i < len(bars); i++ {
+		bar := bars[i]
+		if long {
+			worst = math.Min(worst, bar.Low)
+			best = math.Max(best, bar.High)
+		} else {
+			worst = math.Max(worst, bar.High)
+			best = math.Min(best, bar.Low)
+		}
+
+		if fill, reason, ok := exitFill(plan, bar, long); ok {
+			exitIndex = i
+			result.ExitPrice = fill
+			result.ExitReason = reason
+			// 止损/止盈价之外的波动不计入，以成交价为界
+			if reason == ExitStopLoss {
+				worst = fill
+			}
+			break
+		}
+	}
+	if result.ExitReason == ExitNotFilled {
+		result.ExitPrice = bars[exitIndex].Close
+		result.ExitReason = ExitEndOfData
+		if expired {
+			result.ExitReason = ExitExpiry
+		}
+	}
+	result.ExitTime = bars[exitIndex].TradeTime
+
+	exitType := TypeSell
+	if !long {
+		exitType = TypeBuy
+	}
+	result.Fills = append(result.Fills, BacktestFill{
+		Type: exitType, Time: result.ExitTime, Price: result.ExitPrice, Quantity: quantity, Reason: result.ExitReason,
+	})
+
+	direction := 1.0
+	if !long {
+		direction = -1
+	}
+	perShare := (result.ExitPrice - result.EntryPrice) * direction
+	result.PnL = round2(perShare * float64(quantity))
+	result.PnLPct = round2(perShare / result.EntryPrice * 100)
+	result.MaxAdverseExcursion = round2(math.Max(0, (result.EntryPrice-worst)*direction))
+	result.MaxAdverseExcursionPct = round2(result.MaxAdverseExcursion / result.EntryPrice * 100)
+	result.MaxFavorableExcursion = round2(math.Max(0, (best-result.EntryPrice)*direction))
+	result.MaxFavorableExcursionPct = round2(result.MaxFavorableExcursion / result.EntryPrice * 100)
+	result.HoldingBars = exitIndex - entryIndex + 1
+	result.HoldingDays = daysBetween(result.EntryTime, result.ExitTime)
+
+	return result
+}
+
+// entryFill 判断K线是否触发开仓并返回成交价
+func entryFill(plan *Plan, bar price.Price, long bool) (float64, bool) {
+	if plan.TargetPrice <= 0 {
+		return bar.Open, true
+	}
+	if long && bar.Low <= plan.TargetPrice {
+		return math.Min(bar.Open, plan.TargetPrice), true
+	}
+	if !long && bar.High >= plan.TargetPrice {
+		return math.Max(bar.Open, plan.TargetPrice), true
+	}
+	return 0, false
+}
+
+// exitFill 判断K线是否触发止损或止盈并返回成交价，跳空越过时以开盘价成交
+func exitFill(plan *Plan, bar price.Price, long bool) (float64, string, bool) {
+	if long {
+		if plan.StopLoss > 0 && bar.Low <= plan.StopLoss {
+			return math.Min(bar.Open, plan.StopLoss), ExitStopLoss, true
+		}
+		if plan.TakeProfit > 0 && bar.High >= plan.TakeProfit {
+			return math.Max(bar.Open, plan.TakeProfit), ExitTakeProfit, true
+		}
+		return 0, "", false
+	}
+	if plan.StopLoss > 0 && bar.High >= plan.StopLoss {
+		return math.Max(bar.Open, plan.StopLoss), ExitStopLoss, true
+	}
+	if plan.TakeProfit > 0 && bar.Low <= plan.TakeProfit {
+		return math.Min(bar.Open, plan.TakeProfit), ExitTakeProfit, true
+	}
+	return 0, "", false
+}
+
+// normalizeBarTime 将计划时间转换为K线交易时间格式，日线只保留日期
+func normalizeBarTime(value, interval string) string {
+	value = strings.Replace(strings.TrimSpace(value), "T", " ", 1)
+	if len(value) < len("2006-01-02") {
+		return value
+	}
+	if interval == price.IntervalDaily {
+		return value[:len("2006-01-02")]
+	}
+	if len(value) > len("2006-01-02 15:04") {
+		return value[:len("2006-01-02 15:04")]
+	}
+	return value
+}
+
+// daysBetween 计算两个交易时间之间的自然日天数
+func daysBetween(start, end string) int {
+	if len(start) < len("2006-01-02") || len(end) < len("2006-01-02") {
+		return 0
+	}
+	s, err1 := time.Parse("2006-01-02", start[:len("2006-01-02")])
+	e, err2 := time.Parse("2006-01-02", end[:len("2006-01-02")])
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(e.Sub(s).Hours() / 24)
+}
+
+// applyBacktestFees 按股票所属地区和板块的费率表计算回测成交费用，并从盈亏中扣除
+func (s *planService) applyBacktestFees(userID string, plan *Plan, result *BacktestResult) error {
+	if !result.Entered {
+		return nil
+	}
+	st, err := s.stockService.ResolveStock(userID, plan.StockCode)
+	if err != nil {
+		st = &stock.Stock{Code: plan.StockCode}
+	}
+
+	for i := range result.Fills {
+		f := &result.Fills[i]
+		breakdown, err := s.feeService.Calculate(userID, st.EffectiveRegion(), st.Category, f.Type, f.Price, f.Quantity)
+		if err != nil {
+			return err
+		}
+		f.Fees = breakdown.Total
+		result.Fees += breakdown.Total
+	}
+	result.Fees = round2(result.Fees)
+	result.PnL = round2(result.PnL - result.Fees)
+	if amount := result.EntryPrice * float64(result.Quantity); amount > 0 {
+		result.PnLPct = round2(result.PnL / amount * 100)
+	}
+	return nil
+}
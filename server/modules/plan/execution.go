@@ -0,0 +1,134 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+
+	"server/storage"
+)
+
+// logStatusCompleted 已成交日志的状态，与 log.StatusCompleted 一致（log 模块依赖 plan，这里不能直接引用）
+const logStatusCompleted = "completed"
+
+// loadFills 读取关联到计划的已成交日志，按交易时间升序
+func loadFills(userID, planID string) ([]PlanFill, error) {
+	query := `SELECT id, type, trading_time, price, quantity, fees FROM logs
+		WHERE user_id = ? AND plan_id = ? AND status = ? ORDER BY trading_time ASC, created_at ASC`
+	rows, err := storage.GetDB().Query(query, userID, planID, logStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("查询计划成交失败: %w", err)
+	}
+	defer rows.Close()
+
+	fills := []PlanFill{}
+	for rows.Next() {
+		var f PlanFill
+		if err := rows.Scan(&f.LogID, &f.Type, &f.TradingTime, &f.Price, &f.Quantity, &f.Fees); err != nil {
+			return nil, fmt.Errorf("扫描计划成交失败: %w", err)
+		}
+		fills = append(fills, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历计划成交失败: %w", err)
+	}
+	return fills, nil
+}
+
+// entryType 计划的开仓方向，未设置方向时视为买多
+func entryType(plan *Plan) string {
+	if plan.Type == TypeSell {
+		return TypeSell
+	}
+	return TypeBuy
+}
+
+// computeExecution 根据关联成交汇总计划执行情况
+func computeExecution(plan *Plan, fills []PlanFill) *PlanExecution {
+	exec := &PlanExecution{
+		PlanID:          plan.ID,
+		PlanName:        plan.Name,
+		Type:            plan.Type,
+		StockCode:       plan.StockCode,
+		StockName:       plan.StockName,
+		Status:          plan.Status,
+		PlannedQuantity: plan.Quantity,
+		TargetPrice:     plan.TargetPrice,
+		StopLoss:        plan.StopLoss,
+		TakeProfit:      plan.TakeProfit,
+		Fills:           fills,
+	}
+
+	long := entryType(plan) == TypeBuy
+	var entryAmount, exitAmount float64
+	for i := range fills {
+		f := &fills[i]
+		f.Entry = f.Type == entryType(plan)
+		if f.Entry {
+			exec.ExecutedQuantity += f.Quantity
+			entryAmount += f.Price * float64(f.Quantity)
+		} else {
+			exec.ClosedQuantity += f.Quantity
+			exitAmount += f.Price * float64(f.Quantity)
+
+			// 平仓价触及止损/止盈位
+			if plan.StopLoss > 0 && ((long && f.Price <= plan.StopLoss) || (!long && f.Price >= plan.StopLoss)) {
+				exec.StopLossHit = true
+			}
+			if plan.TakeProfit > 0 && ((long && f.Price >= plan.TakeProfit) || (!long && f.Price <= plan.TakeProfit)) {
+				exec.TakeProfitHit = true
+			}
+		}
+		exec.TotalFees += f.Fees
+		if exec.FirstTradeTime == "" {
+			exec.FirstTradeTime = f.TradingTime
+		}
+		exec.LastTradeTime = f.TradingTime
+	}
+
+	if exec.ExecutedQuantity > 0 {
+		exec.AvgFillPrice = round2(entryAmount / float64(exec.ExecutedQuantity))
+		if plan.TargetPrice > 0 {
+			exec.PriceDeviation = round2(exec.AvgFillPrice - plan.TargetPrice)
+			exec.PriceDeviationPct = round2(exec.PriceDeviation / plan.TargetPrice * 100)
+		}
+	}
+	exec.TotalFees = round2(exec.TotalFees)
+	if exec.ClosedQuantity > 0 {
+		exec.AvgExitPrice = round2(exitAmount / float64(exec.ClosedQuantity))
+	}
+	if plan.Quantity > 0 {
+		if exec.ExecutedQuantity < plan.Quantity {
+			exec.RemainingQuantity = plan.Quantity - exec.ExecutedQuantity
+		}
+		exec.FillRate = round2(float64(exec.ExecutedQuantity) / float64(plan.Quantity) * 100)
+	}
+
+	return exec
+}
+
+// deriveStatus 由执行情况推导计划状态，已取消的计划保持不变
+// 未开仓为未执行，已开仓但仍有持仓为执行中（需继续监控止损止盈），已全部平仓为已完成
+func deriveStatus(plan *Plan, exec *PlanExecution) string {
+	if plan.Status == StatusCancelled {
+		return plan.Status
+	}
+	switch {
+	case exec.ExecutedQuantity == 0:
+		if plan.Status == StatusExecuting || plan.Status == StatusCompleted {
+			return StatusActive
+		}
+		return plan.Status
+	case exec.ClosedQuantity >= exec.ExecutedQuantity:
+		return StatusCompleted
+	}
+	return StatusExecuting
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	r := math.Round(v*100) / 100
+	if r == 0 {
+		return 0
+	}
+	return r
+}
@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"server/handler"
+	"server/middleware"
+	"server/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,7 +34,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
 				return
 			}
-			plan, err := planService.CreatePlan(&req)
+			plan, err := planService.CreatePlan(middleware.GetCurrentUserID(c), &req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -47,6 +49,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				RiskLevel: c.Query("riskLevel"),
 				StockCode: c.Query("stockCode"),
 			}
+			req.RiskFlagged, _ = strconv.ParseBool(c.Query("riskFlagged"))
 
 			// 解析分页参数
 			if pageStr := c.Query("page"); pageStr != "" {
@@ -60,7 +63,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				}
 			}
 
-			response, err := planService.ListPlans(req)
+			response, err := planService.ListPlans(middleware.GetCurrentUserID(c), req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -68,13 +71,28 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 
 			handler.Success(c, response)
 		})
+		g.GET("/export", func(c *gin.Context) {
+			req := &PlanListRequest{
+				Keyword:   c.Query("keyword"),
+				Type:      c.Query("type"),
+				Status:    c.Query("status"),
+				RiskLevel: c.Query("riskLevel"),
+				StockCode: c.Query("stockCode"),
+			}
+			req.RiskFlagged, _ = strconv.ParseBool(c.Query("riskFlagged"))
+
+			userID := middleware.GetCurrentUserID(c)
+			handler.Export(c, "plans", "交易计划", func(w utils.RowWriter) error {
+				return planService.ExportPlans(userID, req, w)
+			})
+		})
 		g.GET("/getDetail/:id", func(c *gin.Context) {
 			id := c.Param("id")
 			if id == "" {
 				handler.Error(c, handler.CodeInvalid, "计划ID不能为空")
 				return
 			}
-			plan, err := planService.GetPlan(id)
+			plan, err := planService.GetPlan(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeNotFound, err.Error())
 				return
@@ -82,6 +100,48 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 
 			handler.Success(c, plan)
 		})
+		g.GET("/getExecution/:id", func(c *gin.Context) {
+			id := c.Param("id")
+			if id == "" {
+				handler.Error(c, handler.CodeInvalid, "计划ID不能为空")
+				return
+			}
+			execution, err := planService.GetExecution(middleware.GetCurrentUserID(c), id)
+			if err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, execution)
+		})
+		g.GET("/sizing", func(c *gin.Context) {
+			var req SizingRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+			result, err := planService.CalculateSize(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
+		g.GET("/backtest/:id", func(c *gin.Context) {
+			var req BacktestRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+			result, err := planService.Backtest(middleware.GetCurrentUserID(c), c.Param("id"), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
 		g.PUT("/update/:id", func(c *gin.Context) {
 			id := c.Param("id")
 			if id == "" {
@@ -95,7 +155,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			plan, err := planService.UpdatePlan(id, &req)
+			plan, err := planService.UpdatePlan(middleware.GetCurrentUserID(c), id, &req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -110,7 +170,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			err := planService.DeletePlan(id)
+			err := planService.DeletePlan(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -133,7 +193,7 @@ func RegisterPlanRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			plan, err := planService.UpdatePlanStatus(id, req.Status)
+			plan, err := planService.UpdatePlanStatus(middleware.GetCurrentUserID(c), id, req.Status)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -153,6 +213,7 @@ func (h *PlanHandler) listPlans(c *gin.Context) {
 		RiskLevel: c.Query("riskLevel"),
 		StockCode: c.Query("stockCode"),
 	}
+	req.RiskFlagged, _ = strconv.ParseBool(c.Query("riskFlagged"))
 
 	// 解析分页参数
 	if pageStr := c.Query("page"); pageStr != "" {
@@ -166,7 +227,7 @@ func (h *PlanHandler) listPlans(c *gin.Context) {
 		}
 	}
 
-	response, err := h.planService.ListPlans(req)
+	response, err := h.planService.ListPlans(middleware.GetCurrentUserID(c), req)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -189,7 +250,7 @@ func (h *PlanHandler) updatePlan(c *gin.Context) {
 		return
 	}
 
-	plan, err := h.planService.UpdatePlan(id, &req)
+	plan, err := h.planService.UpdatePlan(middleware.GetCurrentUserID(c), id, &req)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -206,7 +267,7 @@ func (h *PlanHandler) deletePlan(c *gin.Context) {
 		return
 	}
 
-	err := h.planService.DeletePlan(id)
+	err := h.planService.DeletePlan(middleware.GetCurrentUserID(c), id)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -231,7 +292,7 @@ func (h *PlanHandler) updatePlanStatus(c *gin.Context) {
 		return
 	}
 
-	plan, err := h.planService.UpdatePlanStatus(id, req.Status)
+	plan, err := h.planService.UpdatePlanStatus(middleware.GetCurrentUserID(c), id, req.Status)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
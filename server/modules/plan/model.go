@@ -2,9 +2,26 @@ package plan
 
 import "time"
 
+// 计划状态
+const (
+	StatusActive    = "active"    // 未执行（默认）
+	StatusPending   = "pending"   // 未执行
+	StatusTriggered = "triggered" // 行情已触及目标价，等待执行
+	StatusExecuting = "executing" // 执行中
+	StatusCompleted = "completed" // 已完成
+	StatusCancelled = "cancelled" // 已取消
+)
+
+// 计划方向，买多计划以买入开仓、卖出平仓，买空计划相反
+const (
+	TypeBuy  = "buy"
+	TypeSell = "sell"
+)
+
 // Plan 交易计划模型
 type Plan struct {
 	ID              string    `json:"id" db:"id"`
+	UserID          string    `json:"userId" db:"user_id"`
 	Name            string    `json:"name" db:"name"`
 	Type            string    `json:"type" db:"type"`
 	StockCode       string    `json:"stockCode" db:"stock_code"`
@@ -18,6 +35,8 @@ type Plan struct {
 	StartTime       string    `json:"startTime" db:"start_time"`
 	EndTime         string    `json:"endTime" db:"end_time"`
 	RiskLevel       string    `json:"riskLevel" db:"risk_level"`
+	RewardRiskRatio float64   `json:"rewardRiskRatio" db:"reward_risk_ratio"` // 盈亏比，止盈/止损未设置时为 0
+	RiskFlagged     bool      `json:"riskFlagged" db:"risk_flagged"`          // 盈亏比低于配置的最低值
 	Description     string    `json:"description" db:"description"`
 	Remark          string    `json:"remark" db:"remark"`
 	Status          string    `json:"status" db:"status"`
@@ -30,7 +49,7 @@ type PlanCreateRequest struct {
 	Name            string  `json:"name" binding:"required"`
 	Type            string  `json:"type" binding:"required"`
 	StockCode       string  `json:"stockCode" binding:"required"`
-	StockName       string  `json:"stockName" binding:"required"`
+	StockName       string  `json:"stockName"` // 以股票列表中的名称为准
 	Strategy        string  `json:"strategy"`
 	TradingStrategy string  `json:"tradingStrategy"`
 	TargetPrice     float64 `json:"targetPrice"`
@@ -39,7 +58,7 @@ type PlanCreateRequest struct {
 	TakeProfit      float64 `json:"takeProfit"`
 	StartTime       string  `json:"startTime"`
 	EndTime         string  `json:"endTime"`
-	RiskLevel       string  `json:"riskLevel"`
+	RiskLevel       string  `json:"riskLevel"` // 由选股策略和交易策略计算
 	Description     string  `json:"description"`
 	Remark          string  `json:"remark"`
 }
@@ -58,10 +77,14 @@ type PlanUpdateRequest struct {
 	TakeProfit      *float64 `json:"takeProfit,omitempty"`
 	StartTime       *string  `json:"startTime,omitempty"`
 	EndTime         *string  `json:"endTime,omitempty"`
-	RiskLevel       *string  `json:"riskLevel,omitempty"`
+	RiskLevel       *string  `json:"riskLevel,omitempty"` // 由选股策略和交易策略计算
 	Description     *string  `json:"description,omitempty"`
 	Remark          *string  `json:"remark,omitempty"`
 	Status          *string  `json:"status,omitempty"`
+
+	// 以下字段由服务端计算
+	RewardRiskRatio *float64 `json:"-"`
+	RiskFlagged     *bool    `json:"-"`
 }
 
 // PlanListRequest 计划列表请求
@@ -71,8 +94,10 @@ type PlanListRequest struct {
 	Status    string `form:"status"`
 	RiskLevel string `form:"riskLevel"`
 	StockCode string `form:"stockCode"`
-	Page      int    `form:"page"`
-	PageSize  int    `form:"pageSize"`
+	// RiskFlagged 为 true 时只返回盈亏比低于最低值的计划
+	RiskFlagged bool `form:"riskFlagged"`
+	Page        int  `form:"page"`
+	PageSize    int  `form:"pageSize"`
 }
 
 // PlanListResponse 计划列表响应
@@ -82,3 +107,127 @@ type PlanListResponse struct {
 	Page     int    `json:"page"`
 	PageSize int    `json:"pageSize"`
 }
+
+// PlanFill 关联到计划的成交记录
+type PlanFill struct {
+	LogID       string  `json:"logId"`
+	Type        string  `json:"type"`
+	TradingTime string  `json:"tradingTime"`
+	Price       float64 `json:"price"`
+	Quantity    int     `json:"quantity"`
+	Fees        float64 `json:"fees"`
+	// Entry 为 true 表示开仓成交，否则为平仓成交
+	Entry bool `json:"entry"`
+}
+
+// PlanExecution 计划执行情况
+type PlanExecution struct {
+	PlanID    string `json:"planId"`
+	PlanName  string `json:"planName"`
+	Type      string `json:"type"`
+	StockCode string `json:"stockCode"`
+	StockName string `json:"stockName"`
+	Status    string `json:"status"`
+
+	PlannedQuantity   int     `json:"plannedQuantity"`
+	ExecutedQuantity  int     `json:"executedQuantity"`  // 开仓成交数量
+	ClosedQuantity    int     `json:"closedQuantity"`    // 平仓成交数量
+	RemainingQuantity int     `json:"remainingQuantity"` // 计划中尚未开仓的数量
+	FillRate          float64 `json:"fillRate"`          // 开仓完成比例（%）
+
+	TargetPrice       float64    `json:"targetPrice"`
+	AvgFillPrice      float64    `json:"avgFillPrice"`
+	AvgExitPrice      float64    `json:"avgExitPrice"`
+	PriceDeviation    float64    `json:"priceDeviation"`    // 平均开仓价 - 目标价
+	PriceDeviationPct float64    `json:"priceDeviationPct"` // 相对目标价的偏离（%）
+	StopLoss          float64    `json:"stopLoss"`
+	TakeProfit        float64    `json:"takeProfit"`
+	StopLossHit       bool       `json:"stopLossHit"`
+	TakeProfitHit     bool       `json:"takeProfitHit"`
+	FirstTradeTime    string     `json:"firstTradeTime"`
+	LastTradeTime     string     `json:"lastTradeTime"`
+	TotalFees         float64    `json:"totalFees"` // 关联成交的交易费用合计
+	Fills             []PlanFill `json:"fills"`
+}
+
+// 回测平仓原因
+const (
+	ExitStopLoss   = "stop_loss"   // 触及止损
+	ExitTakeProfit = "take_profit" // 触及止盈
+	ExitExpiry     = "expiry"      // 计划到期，按到期前最后一根K线收盘价平仓
+	ExitEndOfData  = "end_of_data" // 行情数据结束仍未平仓，按最后收盘价计算
+	ExitNotFilled  = "not_filled"  // 价格未触及目标价，未开仓
+)
+
+// BacktestRequest 回测请求，时间为空时使用计划的开始/结束时间
+type BacktestRequest struct {
+	Interval  string `form:"interval"`
+	StartTime string `form:"startTime"`
+	EndTime   string `form:"endTime"`
+}
+
+// BacktestFill 回测成交
+type BacktestFill struct {
+	Type     string  `json:"type"`
+	Time     string  `json:"time"`
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity"`
+	Fees     float64 `json:"fees"` // 按费率表计算的交易费用
+	Reason   string  `json:"reason"`
+}
+
+// BacktestResult 计划回测结果
+type BacktestResult struct {
+	PlanID    string `json:"planId"`
+	StockCode string `json:"stockCode"`
+	Type      string `json:"type"`
+	Interval  string `json:"interval"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	BarCount  int    `json:"barCount"`
+
+	Entered    bool    `json:"entered"`
+	EntryTime  string  `json:"entryTime"`
+	EntryPrice float64 `json:"entryPrice"`
+	ExitTime   string  `json:"exitTime"`
+	ExitPrice  float64 `json:"exitPrice"`
+	ExitReason string  `json:"exitReason"`
+	Quantity   int     `json:"quantity"`
+
+	PnL                      float64 `json:"pnl"`    // 已扣除交易费用
+	PnLPct                   float64 `json:"pnlPct"` // 相对开仓金额（%）
+	Fees                     float64 `json:"fees"`
+	MaxAdverseExcursion      float64 `json:"maxAdverseExcursion"`      // 持仓期间最大不利波动（每股）
+	MaxAdverseExcursionPct   float64 `json:"maxAdverseExcursionPct"`   // 相对开仓价（%）
+	MaxFavorableExcursion    float64 `json:"maxFavorableExcursion"`    // 持仓期间最大有利波动（每股）
+	MaxFavorableExcursionPct float64 `json:"maxFavorableExcursionPct"` // 相对开仓价（%）
+	HoldingBars              int     `json:"holdingBars"`
+	HoldingDays              int     `json:"holdingDays"`
+
+	Fills []BacktestFill `json:"fills"`
+}
+
+// SizingRequest 仓位计算请求，权益和风险比例为空时使用用户设置
+type SizingRequest struct {
+	StockCode   string  `form:"stockCode" binding:"required"`
+	TargetPrice float64 `form:"targetPrice" binding:"gt=0"`
+	StopLoss    float64 `form:"stopLoss" binding:"gt=0"`
+	Equity      float64 `form:"equity" binding:"min=0"`
+	MaxRiskPct  float64 `form:"maxRiskPct" binding:"min=0,max=100"`
+}
+
+// SizingResult 仓位计算结果
+type SizingResult struct {
+	StockCode      string  `json:"stockCode"`
+	LotSize        int     `json:"lotSize"`
+	Equity         float64 `json:"equity"`
+	MaxRiskPct     float64 `json:"maxRiskPct"`
+	RiskBudget     float64 `json:"riskBudget"`     // 单笔允许亏损金额
+	RiskPerShare   float64 `json:"riskPerShare"`   // 目标价到止损价的距离
+	Quantity       int     `json:"quantity"`       // 建议数量，按整手向下取整
+	PositionValue  float64 `json:"positionValue"`  // 按目标价计算的持仓金额
+	PositionPct    float64 `json:"positionPct"`    // 持仓金额占权益的比例（%）
+	ActualRisk     float64 `json:"actualRisk"`     // 按建议数量触及止损时的亏损
+	ActualRiskPct  float64 `json:"actualRiskPct"`  // 实际亏损占权益的比例（%）
+	CappedByEquity bool    `json:"cappedByEquity"` // 数量受权益限制（不使用杠杆）
+}
@@ -19,16 +19,16 @@ func NewPlanRepository() *PlanRepository {
 // Create 创建计划
 func (r *PlanRepository) Create(plan *Plan) error {
 	query := `INSERT INTO plans (
-		id, name, type, stock_code, stock_name, strategy, trading_strategy,
+		id, user_id, name, type, stock_code, stock_name, strategy, trading_strategy,
 		target_price, quantity, stop_loss, take_profit, start_time, end_time,
-		risk_level, description, remark, status, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		risk_level, reward_risk_ratio, risk_flagged, description, remark, status, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := storage.GetDB().Exec(query,
-		plan.ID, plan.Name, plan.Type, plan.StockCode, plan.StockName,
+		plan.ID, plan.UserID, plan.Name, plan.Type, plan.StockCode, plan.StockName,
 		plan.Strategy, plan.TradingStrategy, plan.TargetPrice, plan.Quantity,
 		plan.StopLoss, plan.TakeProfit, plan.StartTime, plan.EndTime,
-		plan.RiskLevel, plan.Description, plan.Remark, plan.Status,
+		plan.RiskLevel, plan.RewardRiskRatio, plan.RiskFlagged, plan.Description, plan.Remark, plan.Status,
 		plan.CreatedAt, plan.UpdatedAt,
 	)
 
@@ -36,18 +36,18 @@ func (r *PlanRepository) Create(plan *Plan) error {
 }
 
 // GetByID 根据ID获取计划
-func (r *PlanRepository) GetByID(id string) (*Plan, error) {
-	query := `SELECT id, name, type, stock_code, stock_name, strategy, trading_strategy,
+func (r *PlanRepository) GetByID(userID, id string) (*Plan, error) {
+	query := `SELECT id, user_id, name, type, stock_code, stock_name, strategy, trading_strategy,
 		target_price, quantity, stop_loss, take_profit, start_time, end_time,
-		risk_level, description, remark, status, created_at, updated_at
-		FROM plans WHERE id = ?`
+		risk_level, reward_risk_ratio, risk_flagged, description, remark, status, created_at, updated_at
+		FROM plans WHERE id = ? AND user_id = ?`
 
 	plan := &Plan{}
-	err := storage.GetDB().QueryRow(query, id).Scan(
-		&plan.ID, &plan.Name, &plan.Type, &plan.StockCode, &plan.StockName,
+	err := storage.GetDB().QueryRow(query, id, userID).Scan(
+		&plan.ID, &plan.UserID, &plan.Name, &plan.Type, &plan.StockCode, &plan.StockName,
 		&plan.Strategy, &plan.TradingStrategy, &plan.TargetPrice, &plan.Quantity,
 		&plan.StopLoss, &plan.TakeProfit, &plan.StartTime, &plan.EndTime,
-		&plan.RiskLevel, &plan.Description, &plan.Remark, &plan.Status,
+		&plan.RiskLevel, &plan.RewardRiskRatio, &plan.RiskFlagged, &plan.Description, &plan.Remark, &plan.Status,
 		&plan.CreatedAt, &plan.UpdatedAt,
 	)
 
@@ -60,11 +60,15 @@ func (r *PlanRepository) GetByID(id string) (*Plan, error) {
 	return plan, nil
 }
 
-// List 获取计划列表
-func (r *PlanRepository) List(req *PlanListRequest) ([]Plan, int, error) {
-	// 构建查询条件
-	where := []string{"1=1"}
-	args := []interface{}{}
+// planColumns 计划查询字段
+const planColumns = `id, user_id, name, type, stock_code, stock_name, strategy, trading_strategy,
+		target_price, quantity, stop_loss, take_profit, start_time, end_time,
+		risk_level, reward_risk_ratio, risk_flagged, description, remark, status, created_at, updated_at`
+
+// buildPlanFilter 根据列表请求构建查询条件
+func buildPlanFilter(userID string, req *PlanListRequest) (string, []interface{}) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
 
 	if req.Keyword != "" {
 		where = append(where, "(name LIKE ? OR stock_name LIKE ? OR stock_code LIKE ?)")
@@ -87,8 +91,32 @@ func (r *PlanRepository) List(req *PlanListRequest) ([]Plan, int, error) {
 		where = append(where, "stock_code = ?")
 		args = append(args, req.StockCode)
 	}
+	if req.RiskFlagged {
+		where = append(where, "risk_flagged = 1")
+	}
+
+	return strings.Join(where, " AND "), args
+}
+
+// scanPlan 扫描一行计划数据
+func scanPlan(rows *sql.Rows) (*Plan, error) {
+	plan := &Plan{}
+	err := rows.Scan(
+		&plan.ID, &plan.UserID, &plan.Name, &plan.Type, &plan.StockCode, &plan.StockName,
+		&plan.Strategy, &plan.TradingStrategy, &plan.TargetPrice, &plan.Quantity,
+		&plan.StopLoss, &plan.TakeProfit, &plan.StartTime, &plan.EndTime,
+		&plan.RiskLevel, &plan.RewardRiskRatio, &plan.RiskFlagged, &plan.Description, &plan.Remark, &plan.Status,
+		&plan.CreatedAt, &plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
 
-	whereClause := strings.Join(where, " AND ")
+// List 获取计划列表
+func (r *PlanRepository) List(userID string, req *PlanListRequest) ([]Plan, int, error) {
+	whereClause, args := buildPlanFilter(userID, req)
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM plans WHERE %s", whereClause)
 	var total int
 	err := storage.GetDB().QueryRow(countQuery, args...).Scan(&total)
@@ -106,10 +134,7 @@ func (r *PlanRepository) List(req *PlanListRequest) ([]Plan, int, error) {
 	}
 	offset := (page - 1) * pageSize
 
-	query := fmt.Sprintf(`SELECT id, name, type, stock_code, stock_name, strategy, trading_strategy,
-		target_price, quantity, stop_loss, take_profit, start_time, end_time,
-		risk_level, description, remark, status, created_at, updated_at
-		FROM plans WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
+	query := fmt.Sprintf(`SELECT %s FROM plans WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, planColumns, whereClause)
 
 	args = append(args, pageSize, offset)
 	rows, err := storage.GetDB().Query(query, args...)
@@ -120,18 +145,11 @@ func (r *PlanRepository) List(req *PlanListRequest) ([]Plan, int, error) {
 
 	var plans []Plan
 	for rows.Next() {
-		plan := Plan{}
-		err := rows.Scan(
-			&plan.ID, &plan.Name, &plan.Type, &plan.StockCode, &plan.StockName,
-			&plan.Strategy, &plan.TradingStrategy, &plan.TargetPrice, &plan.Quantity,
-			&plan.StopLoss, &plan.TakeProfit, &plan.StartTime, &plan.EndTime,
-			&plan.RiskLevel, &plan.Description, &plan.Remark, &plan.Status,
-			&plan.CreatedAt, &plan.UpdatedAt,
-		)
+		plan, err := scanPlan(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		plans = append(plans, plan)
+		plans = append(plans, *plan)
 	}
 
 	// 检查遍历过程中是否有错误
@@ -142,8 +160,53 @@ func (r *PlanRepository) List(req *PlanListRequest) ([]Plan, int, error) {
 	return plans, total, nil
 }
 
+// Each 按列表筛选条件逐条遍历全部计划（不分页），fn 返回错误时停止遍历
+func (r *PlanRepository) Each(userID string, req *PlanListRequest, fn func(*Plan) error) error {
+	whereClause, args := buildPlanFilter(userID, req)
+	query := fmt.Sprintf(`SELECT %s FROM plans WHERE %s ORDER BY created_at DESC`, planColumns, whereClause)
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(plan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ListMonitored 获取全部用户需要监控价格的计划：未完成、未取消且设置了目标价、止损价或止盈价
+func (r *PlanRepository) ListMonitored() ([]Plan, error) {
+	query := fmt.Sprintf(`SELECT %s FROM plans
+		WHERE status IN (?, ?, ?, ?) AND stock_code != ''
+		AND (target_price > 0 OR stop_loss > 0 OR take_profit > 0)
+		ORDER BY user_id, stock_code`, planColumns)
+	rows, err := storage.GetDB().Query(query, StatusActive, StatusPending, StatusTriggered, StatusExecuting)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []Plan{}
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
 // Update 更新计划
-func (r *PlanRepository) Update(id string, req *PlanUpdateRequest) error {
+func (r *PlanRepository) Update(userID, id string, req *PlanUpdateRequest) error {
 	// 构建更新字段
 	setParts := []string{}
 	args := []interface{}{}
@@ -200,6 +263,14 @@ func (r *PlanRepository) Update(id string, req *PlanUpdateRequest) error {
 		setParts = append(setParts, "risk_level = ?")
 		args = append(args, *req.RiskLevel)
 	}
+	if req.RewardRiskRatio != nil {
+		setParts = append(setParts, "reward_risk_ratio = ?")
+		args = append(args, *req.RewardRiskRatio)
+	}
+	if req.RiskFlagged != nil {
+		setParts = append(setParts, "risk_flagged = ?")
+		args = append(args, *req.RiskFlagged)
+	}
 	if req.Description != nil {
 		setParts = append(setParts, "description = ?")
 		args = append(args, *req.Description)
@@ -219,9 +290,9 @@ func (r *PlanRepository) Update(id string, req *PlanUpdateRequest) error {
 
 	setParts = append(setParts, "updated_at = ?")
 	args = append(args, time.Now())
-	args = append(args, id)
+	args = append(args, id, userID)
 
-	query := fmt.Sprintf("UPDATE plans SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	query := fmt.Sprintf("UPDATE plans SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
 	result, err := storage.GetDB().Exec(query, args...)
 	if err != nil {
 		return err
@@ -238,9 +309,9 @@ func (r *PlanRepository) Update(id string, req *PlanUpdateRequest) error {
 }
 
 // Delete 删除计划
-func (r *PlanRepository) Delete(id string) error {
-	query := "DELETE FROM plans WHERE id = ?"
-	result, err := storage.GetDB().Exec(query, id)
+func (r *PlanRepository) Delete(userID, id string) error {
+	query := "DELETE FROM plans WHERE id = ? AND user_id = ?"
+	result, err := storage.GetDB().Exec(query, id, userID)
 	if err != nil {
 		return err
 	}
@@ -254,3 +325,17 @@ func (r *PlanRepository) Delete(id string) error {
 	}
 	return nil
 }
+
+// RenameLinkedLogs 同步关联日志中冗余保存的计划名称
+func (r *PlanRepository) RenameLinkedLogs(userID, id, name string) error {
+	_, err := storage.GetDB().Exec("UPDATE logs SET plan_name = ?, updated_at = ? WHERE plan_id = ? AND user_id = ?",
+		name, time.Now(), id, userID)
+	return err
+}
+
+// UnlinkLogs 解除日志与计划的关联，保留日志中的计划名称
+func (r *PlanRepository) UnlinkLogs(userID, id string) error {
+	_, err := storage.GetDB().Exec("UPDATE logs SET plan_id = NULL, updated_at = ? WHERE plan_id = ? AND user_id = ?",
+		time.Now(), id, userID)
+	return err
+}
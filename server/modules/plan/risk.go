@@ -0,0 +1,103 @@
+package plan
+
+import (
+	"fmt"
+
+	"server/config"
+	"server/modules/strategy"
+)
+
+// 盈亏比低于最低值时的处理方式
+const (
+	RewardRiskPolicyReject = "reject" // 拒绝创建或修改
+	RewardRiskPolicyFlag   = "flag"   // 保存并标记 riskFlagged
+)
+
+// riskWeights 风险等级权重，与前端 riskCalculator.js 一致
+var riskWeights = map[string]float64{
+	strategy.RiskLow:    1,
+	strategy.RiskMedium: 2,
+	strategy.RiskHigh:   3,
+}
+
+// combineRiskLevel 按选股策略 40%、交易策略 60% 的权重计算综合风险等级，任一策略缺失时为中风险
+func combineRiskLevel(selection, trading *strategy.Strategy) string {
+	if selection == nil || trading == nil {
+		return strategy.RiskMedium
+	}
+	weight := func(level string) float64 {
+		if w, ok := riskWeights[level]; ok {
+			return w
+		}
+		return riskWeights[strategy.RiskMedium]
+	}
+
+	score := weight(selection.RiskLevel)*0.4 + weight(trading.RiskLevel)*0.6
+	switch {
+	case score <= 1.5:
+		return strategy.RiskLow
+	case score <= 2.5:
+		return strategy.RiskMedium
+	}
+	return strategy.RiskHigh
+}
+
+// rewardRiskRatio 计算盈亏比（止盈距离 / 止损距离），目标价、止损、止盈任一未设置时返回 0
+// 止损或止盈设置在目标价错误一侧时返回错误
+func rewardRiskRatio(plan *Plan) (float64, error) {
+	if plan.TargetPrice <= 0 {
+		return 0, nil
+	}
+	long := entryType(plan) == TypeBuy
+	if plan.StopLoss > 0 {
+		if long && plan.StopLoss >= plan.TargetPrice {
+			return 0, fmt.Errorf("买多计划的止损价必须低于目标价")
+		}
+		if !long && plan.StopLoss <= plan.TargetPrice {
+			return 0, fmt.Errorf("卖空计划的止损价必须高于目标价")
+		}
+	}
+	if plan.TakeProfit > 0 {
+		if long && plan.TakeProfit <= plan.TargetPrice {
+			return 0, fmt.Errorf("买多计划的止盈价必须高于目标价")
+		}
+		if !long && plan.TakeProfit >= plan.TargetPrice {
+			return 0, fmt.Errorf("卖空计划的止盈价必须低于目标价")
+		}
+	}
+	if plan.StopLoss <= 0 || plan.TakeProfit <= 0 {
+		return 0, nil
+	}
+
+	risk := plan.TargetPrice - plan.StopLoss
+	reward := plan.TakeProfit - plan.TargetPrice
+	if !long {
+		risk, reward = -risk, -reward
+	}
+	return round2(reward / risk), nil
+}
+
+// assessRisk 计算计划的风险等级和盈亏比，盈亏比低于配置的最低值时按策略拒绝或标记
+func (s *planService) assessRisk(plan *Plan) error {
+	var selection, trading *strategy.Strategy
+	if plan.Strategy != "" {
+		selection, _ = s.strategyService.GetStrategy(plan.UserID, plan.Strategy)
+	}
+	if plan.TradingStrategy != "" {
+		trading, _ = s.strategyService.GetStrategy(plan.UserID, plan.TradingStrategy)
+	}
+	plan.RiskLevel = combineRiskLevel(selection, trading)
+
+	ratio, err := rewardRiskRatio(plan)
+	if err != nil {
+		return err
+	}
+	plan.RewardRiskRatio = ratio
+
+	cfg := config.Load()
+	plan.RiskFlagged = ratio > 0 && cfg.MinRewardRiskRatio > 0 && ratio < cfg.MinRewardRiskRatio
+	if plan.RiskFlagged && cfg.RewardRiskPolicy == RewardRiskPolicyReject {
+		return fmt.Errorf("盈亏比 %.2f 低于最低要求 %.2f", ratio, cfg.MinRewardRiskRatio)
+	}
+	return nil
+}
@@ -4,41 +4,70 @@ import (
 	"fmt"
 	"time"
 
+	"server/modules/fee"
+	"server/modules/price"
+	"server/modules/stock"
+	"server/modules/strategy"
+	"server/modules/user"
 	"server/utils"
 )
 
 // PlanService 计划服务接口
 type PlanService interface {
-	CreatePlan(req *PlanCreateRequest) (*Plan, error)
-	GetPlan(id string) (*Plan, error)
-	ListPlans(req *PlanListRequest) (*PlanListResponse, error)
-	UpdatePlan(id string, req *PlanUpdateRequest) (*Plan, error)
-	DeletePlan(id string) error
-	UpdatePlanStatus(id string, status string) (*Plan, error)
+	CreatePlan(userID string, req *PlanCreateRequest) (*Plan, error)
+	GetPlan(userID, id string) (*Plan, error)
+	ListPlans(userID string, req *PlanListRequest) (*PlanListResponse, error)
+	UpdatePlan(userID, id string, req *PlanUpdateRequest) (*Plan, error)
+	DeletePlan(userID, id string) error
+	UpdatePlanStatus(userID, id string, status string) (*Plan, error)
+	GetExecution(userID, id string) (*PlanExecution, error)
+	SyncStatus(userID, id string) error
+	Backtest(userID, id string, req *BacktestRequest) (*BacktestResult, error)
+	CalculateSize(userID string, req *SizingRequest) (*SizingResult, error)
+	ExportPlans(userID string, req *PlanListRequest, w utils.RowWriter) error
 }
 
 // planService 计划服务实现
 type planService struct {
-	planRepo *PlanRepository
+	planRepo        *PlanRepository
+	stockService    stock.StockService
+	priceService    price.PriceService
+	strategyService strategy.StrategyService
+	userService     user.UserService
+	feeService      fee.FeeService
 }
 
 // NewPlanService 创建计划服务
 func NewPlanService() PlanService {
 	return &planService{
-		planRepo: NewPlanRepository(),
+		planRepo:        NewPlanRepository(),
+		stockService:    stock.NewStockService(),
+		priceService:    price.NewPriceService(),
+		strategyService: strategy.NewStrategyService(),
+		userService:     user.NewUserService(),
+		feeService:      fee.NewFeeService(),
 	}
 }
 
 // CreatePlan 创建计划
-func (s *planService) CreatePlan(req *PlanCreateRequest) (*Plan, error) {
+func (s *planService) CreatePlan(userID string, req *PlanCreateRequest) (*Plan, error) {
+	st, err := s.stockService.ResolveStock(userID, req.StockCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateStrategies(userID, req.Strategy, req.TradingStrategy); err != nil {
+		return nil, err
+	}
+
 	id := utils.GenerateID()
 
 	plan := &Plan{
 		ID:              id,
+		UserID:          userID,
 		Name:            req.Name,
 		Type:            req.Type,
 		StockCode:       req.StockCode,
-		StockName:       req.StockName,
+		StockName:       st.Name,
 		Strategy:        req.Strategy,
 		TradingStrategy: req.TradingStrategy,
 		TargetPrice:     req.TargetPrice,
@@ -47,16 +76,21 @@ func (s *planService) CreatePlan(req *PlanCreateRequest) (*Plan, error) {
 		TakeProfit:      req.TakeProfit,
 		StartTime:       req.StartTime,
 		EndTime:         req.EndTime,
-		RiskLevel:       req.RiskLevel,
 		Description:     req.Description,
 		Remark:          req.Remark,
-		Status:          "active",
+		Status:          StatusActive,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+	if err := s.assessRisk(plan); err != nil {
+		return nil, err
+	}
+	if err := s.autoSize(userID, plan, st.EffectiveLotSize()); err != nil {
+		return nil, err
+	}
 
 	repo := NewPlanRepository()
-	err := repo.Create(plan)
+	err = repo.Create(plan)
 	if err != nil {
 		return nil, fmt.Errorf("创建计划失败: %w", err)
 	}
@@ -64,10 +98,18 @@ func (s *planService) CreatePlan(req *PlanCreateRequest) (*Plan, error) {
 	return plan, nil
 }
 
+// validateStrategies 校验选股策略和交易策略，为空时不校验
+func (s *planService) validateStrategies(userID, strategyID, tradingStrategyID string) error {
+	if err := s.strategyService.ValidateStrategy(userID, strategy.KindSelection, strategyID); err != nil {
+		return err
+	}
+	return s.strategyService.ValidateStrategy(userID, strategy.KindTrading, tradingStrategyID)
+}
+
 // GetPlan 获取计划详情
-func (s *planService) GetPlan(id string) (*Plan, error) {
+func (s *planService) GetPlan(userID, id string) (*Plan, error) {
 	repo := NewPlanRepository()
-	plan, err := repo.GetByID(id)
+	plan, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("获取计划失败: %w", err)
 	}
@@ -76,9 +118,9 @@ func (s *planService) GetPlan(id string) (*Plan, error) {
 }
 
 // ListPlans 获取计划列表
-func (s *planService) ListPlans(req *PlanListRequest) (*PlanListResponse, error) {
+func (s *planService) ListPlans(userID string, req *PlanListRequest) (*PlanListResponse, error) {
 	repo := NewPlanRepository()
-	plans, total, err := repo.List(req)
+	plans, total, err := repo.List(userID, req)
 	if err != nil {
 		return nil, fmt.Errorf("获取计划列表失败: %w", err)
 	}
@@ -101,24 +143,110 @@ func (s *planService) ListPlans(req *PlanListRequest) (*PlanListResponse, error)
 	}, nil
 }
 
+// ExportPlans 按列表筛选条件导出全部计划，逐行写出
+func (s *planService) ExportPlans(userID string, req *PlanListRequest, w utils.RowWriter) error {
+	err := w.WriteRow([]interface{}{
+		"计划名称", "方向", "股票代码", "股票名称", "选股策略", "交易策略", "目标价", "数量", "止损价", "止盈价",
+		"开始时间", "结束时间", "风险等级", "盈亏比", "盈亏比偏低", "状态", "描述", "备注", "创建时间",
+	})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = s.planRepo.Each(userID, req, func(p *Plan) error {
+		typeName := "买入"
+		if p.Type == TypeSell {
+			typeName = "卖出"
+		}
+		count++
+		return w.WriteRow([]interface{}{
+			p.Name, typeName, p.StockCode, p.StockName, p.Strategy, p.TradingStrategy, p.TargetPrice, p.Quantity,
+			p.StopLoss, p.TakeProfit, p.StartTime, p.EndTime, p.RiskLevel, p.RewardRiskRatio, p.RiskFlagged,
+			p.Status, p.Description, p.Remark, p.CreatedAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("导出计划失败: %w", err)
+	}
+
+	utils.LogInfo("导出交易计划完成，共 %d 条", count)
+	return nil
+}
+
 // UpdatePlan 更新计划
-func (s *planService) UpdatePlan(id string, req *PlanUpdateRequest) (*Plan, error) {
+func (s *planService) UpdatePlan(userID, id string, req *PlanUpdateRequest) (*Plan, error) {
 	repo := NewPlanRepository()
 
 	// 检查计划是否存在
-	_, err := repo.GetByID(id)
+	existing, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("计划不存在: %w", err)
 	}
 
+	// 只校验修改过的策略，已停用的策略不影响计划其他字段的更新
+	strategyID, tradingStrategyID := "", ""
+	if req.Strategy != nil && *req.Strategy != existing.Strategy {
+		strategyID = *req.Strategy
+	}
+	if req.TradingStrategy != nil && *req.TradingStrategy != existing.TradingStrategy {
+		tradingStrategyID = *req.TradingStrategy
+	}
+	if err := s.validateStrategies(userID, strategyID, tradingStrategyID); err != nil {
+		return nil, err
+	}
+
+	if req.StockCode != nil {
+		st, err := s.stockService.ResolveStock(userID, *req.StockCode)
+		if err != nil {
+			return nil, err
+		}
+		req.StockName = &st.Name
+	}
+
+	// 修改方向、策略或价格时重新计算风险等级和盈亏比，客户端传入的风险等级不生效
+	if req.Type != nil || req.Strategy != nil || req.TradingStrategy != nil || req.RiskLevel != nil ||
+		req.TargetPrice != nil || req.StopLoss != nil || req.TakeProfit != nil {
+		merged := *existing
+		if req.Type != nil {
+			merged.Type = *req.Type
+		}
+		if req.Strategy != nil {
+			merged.Strategy = *req.Strategy
+		}
+		if req.TradingStrategy != nil {
+			merged.TradingStrategy = *req.TradingStrategy
+		}
+		if req.TargetPrice != nil {
+			merged.TargetPrice = *req.TargetPrice
+		}
+		if req.StopLoss != nil {
+			merged.StopLoss = *req.StopLoss
+		}
+		if req.TakeProfit != nil {
+			merged.TakeProfit = *req.TakeProfit
+		}
+		if err := s.assessRisk(&merged); err != nil {
+			return nil, err
+		}
+		req.RiskLevel = &merged.RiskLevel
+		req.RewardRiskRatio = &merged.RewardRiskRatio
+		req.RiskFlagged = &merged.RiskFlagged
+	}
+
 	// 更新计划
-	err = repo.Update(id, req)
+	err = repo.Update(userID, id, req)
 	if err != nil {
 		return nil, fmt.Errorf("更新计划失败: %w", err)
 	}
+	if req.Name != nil {
+		if err := repo.RenameLinkedLogs(userID, id, *req.Name); err != nil {
+			return nil, fmt.Errorf("同步日志中的计划名称失败: %w", err)
+		}
+	}
 
 	// 返回更新后的计划
-	updatedPlan, err := repo.GetByID(id)
+	updatedPlan, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("获取更新后的计划失败: %w", err)
 	}
@@ -127,17 +255,20 @@ func (s *planService) UpdatePlan(id string, req *PlanUpdateRequest) (*Plan, erro
 }
 
 // DeletePlan 删除计划
-func (s *planService) DeletePlan(id string) error {
+func (s *planService) DeletePlan(userID, id string) error {
 	repo := NewPlanRepository()
 
 	// 检查计划是否存在
-	_, err := repo.GetByID(id)
+	_, err := repo.GetByID(userID, id)
 	if err != nil {
 		return fmt.Errorf("计划不存在: %w", err)
 	}
 
-	// 删除计划
-	err = repo.Delete(id)
+	// 删除计划前解除日志关联
+	if err := repo.UnlinkLogs(userID, id); err != nil {
+		return fmt.Errorf("解除日志关联失败: %w", err)
+	}
+	err = repo.Delete(userID, id)
 	if err != nil {
 		return fmt.Errorf("删除计划失败: %w", err)
 	}
@@ -146,7 +277,95 @@ func (s *planService) DeletePlan(id string) error {
 }
 
 // UpdatePlanStatus 更新计划状态
-func (s *planService) UpdatePlanStatus(id string, status string) (*Plan, error) {
+func (s *planService) UpdatePlanStatus(userID, id string, status string) (*Plan, error) {
 	req := &PlanUpdateRequest{Status: &status}
-	return s.UpdatePlan(id, req)
+	return s.UpdatePlan(userID, id, req)
+}
+
+// GetExecution 获取计划执行情况
+func (s *planService) GetExecution(userID, id string) (*PlanExecution, error) {
+	plan, err := s.planRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取计划失败: %w", err)
+	}
+
+	fills, err := loadFills(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return computeExecution(plan, fills), nil
+}
+
+// SyncStatus 根据关联日志重新推导计划状态，在日志新增、修改、删除后调用
+func (s *planService) SyncStatus(userID, id string) error {
+	plan, err := s.planRepo.GetByID(userID, id)
+	if err != nil {
+		return fmt.Errorf("获取计划失败: %w", err)
+	}
+
+	fills, err := loadFills(userID, id)
+	if err != nil {
+		return err
+	}
+	status := deriveStatus(plan, computeExecution(plan, fills))
+	if status == plan.Status {
+		return nil
+	}
+
+	if err := s.planRepo.Update(userID, id, &PlanUpdateRequest{Status: &status}); err != nil {
+		return fmt.Errorf("更新计划状态失败: %w", err)
+	}
+	utils.LogInfo("计划状态已更新，ID: %s, %s -> %s", id, plan.Status, status)
+	return nil
+}
+
+// Backtest 用已导入的K线回测计划，时间范围默认为计划的开始/结束时间
+func (s *planService) Backtest(userID, id string, req *BacktestRequest) (*BacktestResult, error) {
+	plan, err := s.planRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取计划失败: %w", err)
+	}
+
+	startTime, endTime := req.StartTime, req.EndTime
+	if startTime == "" {
+		startTime = plan.StartTime
+	}
+	if endTime == "" {
+		endTime = plan.EndTime
+	}
+	interval := req.Interval
+	if interval == "" {
+		interval = price.IntervalDaily
+	}
+	startTime = normalizeBarTime(startTime, interval)
+	endTime = normalizeBarTime(endTime, interval)
+	if startTime != "" && endTime != "" && startTime > endTime {
+		return nil, fmt.Errorf("开始时间不能晚于结束时间")
+	}
+
+	bars, err := s.priceService.ListPrices(userID, &price.PriceListRequest{
+		StockCode: plan.StockCode,
+		Interval:  interval,
+		StartDate: startTime,
+		EndDate:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 区间之后仍有行情说明回测因计划到期而结束，否则为数据不足
+	expired := false
+	if endTime != "" && len(bars.Items) > 0 {
+		latest, err := s.priceService.GetLatest(userID, plan.StockCode, interval)
+		if err != nil {
+			return nil, fmt.Errorf("查询行情失败: %w", err)
+		}
+		expired = latest != nil && latest.TradeTime > bars.Items[len(bars.Items)-1].TradeTime
+	}
+
+	result := runBacktest(plan, bars.Items, interval, expired)
+	if err := s.applyBacktestFees(userID, plan, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
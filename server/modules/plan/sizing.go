@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+
+	"server/market"
+)
+
+// calculateSize 按固定风险比例计算仓位：数量 = 权益 × 风险比例 / 每股风险，按整手向下取整，且持仓金额不超过权益
+func calculateSize(equity, maxRiskPct, targetPrice, stopLoss float64, lotSize int) *SizingResult {
+	if lotSize <= 0 {
+		lotSize = 1
+	}
+	result := &SizingResult{
+		LotSize:      lotSize,
+		Equity:       equity,
+		MaxRiskPct:   maxRiskPct,
+		RiskBudget:   round2(equity * maxRiskPct / 100),
+		RiskPerShare: round2(math.Abs(targetPrice - stopLoss)),
+	}
+
+	// 加上极小值避免浮点误差导致整除时少算一手
+	riskPerShare := math.Abs(targetPrice - stopLoss)
+	lots := int(math.Floor(equity*maxRiskPct/100/riskPerShare/float64(lotSize) + 1e-9))
+	if maxLots := int(math.Floor(equity/targetPrice/float64(lotSize) + 1e-9)); lots > maxLots {
+		lots = maxLots
+		result.CappedByEquity = true
+	}
+	if lots < 0 {
+		lots = 0
+	}
+
+	result.Quantity = lots * lotSize
+	result.PositionValue = round2(float64(result.Quantity) * targetPrice)
+	result.ActualRisk = round2(float64(result.Quantity) * riskPerShare)
+	if equity > 0 {
+		result.PositionPct = round2(result.PositionValue / equity * 100)
+		result.ActualRiskPct = round2(result.ActualRisk / equity * 100)
+	}
+	return result
+}
+
+// CalculateSize 计算建议仓位，股票不在股票列表中时按代码推断地区的默认每手股数
+func (s *planService) CalculateSize(userID string, req *SizingRequest) (*SizingResult, error) {
+	if req.TargetPrice == req.StopLoss {
+		return nil, fmt.Errorf("止损价不能等于目标价")
+	}
+
+	equity, maxRiskPct := req.Equity, req.MaxRiskPct
+	if equity <= 0 || maxRiskPct <= 0 {
+		settings, err := s.userService.GetSettings(userID)
+		if err != nil {
+			return nil, err
+		}
+		if equity <= 0 {
+			equity = settings.AccountEquity
+		}
+		if maxRiskPct <= 0 {
+			maxRiskPct = settings.MaxRiskPct
+		}
+	}
+	if equity <= 0 {
+		return nil, fmt.Errorf("请先在用户设置中填写账户权益")
+	}
+
+	lotSize := market.DefaultLotSize(market.InferRegion(req.StockCode))
+	if st, err := s.stockService.ResolveStock(userID, req.StockCode); err == nil {
+		lotSize = st.EffectiveLotSize()
+	}
+
+	result := calculateSize(equity, maxRiskPct, req.TargetPrice, req.StopLoss, lotSize)
+	result.StockCode = req.StockCode
+	return result, nil
+}
+
+// autoSize 用户开启自动计算且计划未填写数量时，按用户设置计算计划数量
+func (s *planService) autoSize(userID string, plan *Plan, lotSize int) error {
+	if plan.Quantity > 0 || plan.TargetPrice <= 0 || plan.StopLoss <= 0 || plan.TargetPrice == plan.StopLoss {
+		return nil
+	}
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return err
+	}
+	if !settings.AutoSizePlans || settings.AccountEquity <= 0 {
+		return nil
+	}
+	plan.Quantity = calculateSize(settings.AccountEquity, settings.MaxRiskPct, plan.TargetPrice, plan.StopLoss, lotSize).Quantity
+	return nil
+}
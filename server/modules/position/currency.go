@@ -0,0 +1,44 @@
+package position
+
+import (
+	"server/modules/fx"
+	"server/modules/log"
+)
+
+// convertPositions 将持仓金额换算为本位币，logs 需已由日志服务换算
+// 已实现盈亏和费用按各自成交日汇率换算，持仓成本、市值和浮动盈亏按 asOf 汇率换算，asOf 为空时取最新汇率
+func convertPositions(positions []*Position, logs []log.Log, conv *fx.Converter, asOf string) {
+	index := map[string]*Position{}
+	for _, p := range positions {
+		index[p.StockCode] = p
+	}
+	rates := map[string]float64{}
+	for _, l := range logs {
+		rates[l.ID] = l.FxRate
+		if p, ok := index[l.StockCode]; ok {
+			p.Currency = l.Currency
+			p.BaseFees += l.BaseFees
+		}
+	}
+
+	for _, p := range positions {
+		p.BaseCurrency = conv.Base()
+		p.BaseFees = round2(p.BaseFees)
+		for i := range p.Realizations {
+			r := &p.Realizations[i]
+			r.Currency = p.Currency
+			r.FxRate = rates[r.LogID]
+			r.BaseRealizedPnL = round2(r.RealizedPnL * r.FxRate)
+			p.BaseRealizedPnL += r.BaseRealizedPnL
+		}
+		p.BaseRealizedPnL = round2(p.BaseRealizedPnL)
+
+		if rate, ok := conv.Rate(p.Currency, asOf); ok {
+			p.FxRate = rate
+			p.BaseCostBasis = round2(p.CostBasis * rate)
+			p.BaseMarketValue = round2(p.MarketValue * rate)
+			p.BaseUnrealizedPnL = round2(p.UnrealizedPnL * rate)
+		}
+		p.BaseTotalPnL = round2(p.BaseRealizedPnL + p.BaseUnrealizedPnL)
+	}
+}
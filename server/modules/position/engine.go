@@ -0,0 +1,241 @@
+package position
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"server/modules/log"
+	"server/utils"
+)
+
+// ParseMethod 校验并返回成本计算方法，为空时使用默认方法
+func ParseMethod(method, defaultMethod string) (string, error) {
+	if method == "" {
+		method = defaultMethod
+	}
+	switch method {
+	case MethodFIFO, MethodLIFO, MethodAverage:
+		return method, nil
+	}
+	return "", fmt.Errorf("不支持的成本计算方法: %s", method)
+}
+
+// Replay 按时间顺序回放交易日志，返回每只股票的持仓（按首次出现顺序）
+// 调用方需保证 logs 已按交易时间升序排列
+func Replay(logs []log.Log, method string) []*Position {
+	positions := []*Position{}
+	index := map[string]*Position{}
+
+	for _, l := range logs {
+		p, ok := index[l.StockCode]
+		if !ok {
+			p = &Position{StockCode: l.StockCode, Method: method, Lots: []Lot{}, Realizations: []Realization{}}
+			index[l.StockCode] = p
+			positions = append(positions, p)
+		}
+		p.apply(l)
+	}
+
+	for _, p := range positions {
+		p.mark()
+	}
+	return positions
+}
+
+// apply 将一笔成交计入持仓
+func (p *Position) apply(l log.Log) {
+	var qty int
+	switch l.Type {
+	case log.TypeBuy:
+		qty = l.Quantity
+		p.BuyCount++
+	case log.TypeSell:
+		qty = -l.Quantity
+		p.SellCount++
+	default:
+		utils.LogWarning("忽略未知类型的交易日志，ID: %s, 类型: %s", l.ID, l.Type)
+		return
+	}
+
+	if l.StockName != "" {
+		p.StockName = l.StockName
+	}
+	if p.FirstTradeTime == "" {
+		p.FirstTradeTime = l.TradingTime
+	}
+	p.LastTradeTime = l.TradingTime
+	p.LastPrice = l.Price
+	p.Fees += l.Fees
+
+	if p.Quantity == 0 || sign(p.Quantity) == sign(qty) {
+		p.open(l, abs(qty))
+		p.Quantity += qty
+		return
+	}
+
+	// 减仓、平仓或反手：按成本计算方法匹配批次并结算已实现盈亏，本笔费用按数量分摊后从平仓金额中扣除
+	dir := sign(p.Quantity)
+	remaining := abs(qty)
+	fee := feePerShare(l)
+	exitPrice := l.Price - float64(dir)*fee
+	r := Realization{
+		LogID:       l.ID,
+		StockCode:   l.StockCode,
+		StockName:   p.StockName,
+		Type:        l.Type,
+		Side:        sideOf(dir),
+		TradingTime: l.TradingTime,
+		Price:       l.Price,
+		Matches:     []LotMatch{},
+	}
+	for remaining > 0 && len(p.Lots) > 0 {
+		idx := 0
+		if p.Method == MethodLIFO {
+			idx = len(p.Lots) - 1
+		}
+		lot := &p.Lots[idx]
+
+		q := remaining
+		if lot.Quantity < q {
+			q = lot.Quantity
+		}
+		cost := lot.Cost
+		if p.Method == MethodAverage {
+			cost = p.AvgCost
+		}
+		pnl := (exitPrice - cost) * float64(q) * float64(dir)
+
+		r.Matches = append(r.Matches, LotMatch{
+			LotID:       lot.LogID,
+			OpenTime:    lot.TradingTime,
+			OpenPrice:   cost,
+			Quantity:    q,
+			RealizedPnL: round2(pnl),
+		})
+		r.Quantity += q
+		r.CostBasis += cost * float64(q)
+		r.Proceeds += exitPrice * float64(q)
+		r.Fees += fee * float64(q)
+		r.RealizedPnL += pnl
+
+		lot.Quantity -= q
+		remaining -= q
+		p.Quantity -= dir * q
+		if lot.Quantity == 0 {
+			p.Lots = append(p.Lots[:idx], p.Lots[idx+1:]...)
+		}
+	}
+
+	p.RealizedPnL += r.RealizedPnL
+	r.CostBasis = round2(r.CostBasis)
+	r.Proceeds = round2(r.Proceeds)
+	r.Fees = round2(r.Fees)
+	r.RealizedPnL = round2(r.RealizedPnL)
+	p.Realizations = append(p.Realizations, r)
+
+	if p.Quantity == 0 {
+		p.AvgCost = 0
+	} else if p.Method != MethodAverage {
+		p.AvgCost = p.lotsAvgCost()
+	}
+
+	// 反手：超出原持仓的部分以本次成交开立反向批次
+	if remaining > 0 {
+		p.open(l, remaining)
+		p.Quantity = sign(qty) * remaining
+	}
+}
+
+// open 以本次成交开立新批次并更新持仓均价，开仓费用计入每股成本（卖空时从开仓价中扣除）
+func (p *Position) open(l log.Log, qty int) {
+	cost := l.Price + feePerShare(l)
+	if l.Type == log.TypeSell {
+		cost = l.Price - feePerShare(l)
+	}
+	held := abs(p.Quantity)
+	p.AvgCost = (p.AvgCost*float64(held) + cost*float64(qty)) / float64(held+qty)
+	p.Lots = append(p.Lots, Lot{
+		LogID:            l.ID,
+		TradingTime:      l.TradingTime,
+		Price:            l.Price,
+		Cost:             cost,
+		OriginalQuantity: qty,
+		Quantity:         qty,
+	})
+}
+
+// feePerShare 日志费用分摊到每股
+func feePerShare(l log.Log) float64 {
+	if l.Quantity <= 0 {
+		return 0
+	}
+	return l.Fees / float64(l.Quantity)
+}
+
+// lotsAvgCost 计算剩余批次的加权平均成本
+func (p *Position) lotsAvgCost() float64 {
+	var amount float64
+	var qty int
+	for _, lot := range p.Lots {
+		amount += lot.Cost * float64(lot.Quantity)
+		qty += lot.Quantity
+	}
+	if qty == 0 {
+		return 0
+	}
+	return amount / float64(qty)
+}
+
+// mark 以最新价格计算市值和浮动盈亏
+func (p *Position) mark() {
+	p.CostBasis = round2(p.AvgCost * float64(abs(p.Quantity)))
+	p.MarketValue = round2(p.LastPrice * float64(abs(p.Quantity)))
+	p.UnrealizedPnL = round2((p.LastPrice - p.AvgCost) * float64(p.Quantity))
+	p.RealizedPnL = round2(p.RealizedPnL)
+	p.TotalPnL = round2(p.RealizedPnL + p.UnrealizedPnL)
+	p.Fees = round2(p.Fees)
+	p.AvgCost = math.Round(p.AvgCost*10000) / 10000
+	for i := range p.Lots {
+		p.Lots[i].Cost = math.Round(p.Lots[i].Cost*10000) / 10000
+	}
+}
+
+// sortRealizations 按平仓时间升序排列
+func sortRealizations(items []Realization) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].TradingTime < items[j].TradingTime
+	})
+}
+
+func sideOf(dir int) string {
+	if dir < 0 {
+		return SideShort
+	}
+	return SideLong
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func round2(f float64) float64 {
+	r := math.Round(f*100) / 100
+	if r == 0 {
+		return 0 // 避免输出 -0
+	}
+	return r
+}
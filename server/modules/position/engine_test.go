@@ -0,0 +1,355 @@
+package position
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"server/modules/log"
+)
+
+// trade 构造一笔测试用的成交日志
+func trade(id, stockCode, typ string, price float64, quantity int, fees float64) log.Log {
+	return log.Log{
+		ID:          id,
+		StockCode:   stockCode,
+		Type:        typ,
+		TradingTime: "2024-01-01 10:00:" + id,
+		Price:       price,
+		Quantity:    quantity,
+		Fees:        fees,
+		Status:      log.StatusCompleted,
+	}
+}
+
+// lotState 批次的ID和剩余数量
+type lotState struct {
+	LogID    string
+	Quantity int
+}
+
+// matchState 平仓匹配的批次、数量和盈亏
+type matchState struct {
+	LotID       string
+	Quantity    int
+	RealizedPnL float64
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		logs          []log.Log
+		quantity      int
+		avgCost       float64
+		realizedPnL   float64
+		unrealizedPnL float64
+		fees          float64
+		lots          []lotState
+		matches       [][]matchState // 每笔平仓的批次匹配
+	}{
+		{
+			name:   "先进先出部分平仓",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", log.TypeBuy, 12, 100, 0),
+				trade("03", "600000", log.TypeSell, 15, 150, 0),
+			},
+			quantity:      50,
+			avgCost:       12,
+			realizedPnL:   650,
+			unrealizedPnL: 150,
+			lots:          []lotState{{"02", 50}},
+			matches:       [][]matchState{{{"01", 100, 500}, {"02", 50, 150}}},
+		},
+		{
+			name:   "后进先出部分平仓",
+			method: MethodLIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", log.TypeBuy, 12, 100, 0),
+				trade("03", "600000", log.TypeSell, 15, 150, 0),
+			},
+			quantity:      50,
+			avgCost:       10,
+			realizedPnL:   550,
+			unrealizedPnL: 250,
+			lots:          []lotState{{"01", 50}},
+			matches:       [][]matchState{{{"02", 100, 300}, {"01", 50, 250}}},
+		},
+		{
+			name:   "加权平均部分平仓",
+			method: MethodAverage,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", log.TypeBuy, 12, 100, 0),
+				trade("03", "600000", log.TypeSell, 15, 150, 0),
+			},
+			quantity:      50,
+			avgCost:       11,
+			realizedPnL:   600,
+			unrealizedPnL: 200,
+			lots:          []lotState{{"02", 50}},
+			matches:       [][]matchState{{{"01", 100, 400}, {"02", 50, 200}}},
+		},
+		{
+			name:   "加权平均多次加仓后均价不随平仓变化",
+			method: MethodAverage,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", log.TypeSell, 12, 50, 0),
+				trade("03", "600000", log.TypeBuy, 13, 50, 0),
+				trade("04", "600000", log.TypeSell, 14, 100, 0),
+			},
+			quantity:    0,
+			avgCost:     0,
+			realizedPnL: 100 + 250,
+			lots:        []lotState{},
+			matches: [][]matchState{
+				{{"01", 50, 100}},
+				{{"01", 50, 125}, {"03", 50, 125}},
+			},
+		},
+		{
+			name:   "开平仓费用按数量分摊",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 10),
+				trade("02", "600000", log.TypeSell, 12, 60, 6),
+			},
+			quantity:      40,
+			avgCost:       10.1,
+			realizedPnL:   (11.9 - 10.1) * 60,
+			unrealizedPnL: (12 - 10.1) * 40,
+			fees:          16,
+			lots:          []lotState{{"01", 40}},
+			matches:       [][]matchState{{{"01", 60, 108}}},
+		},
+		{
+			name:   "卖空后买入平仓",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeSell, 20, 100, 0),
+				trade("02", "600000", log.TypeBuy, 18, 100, 0),
+			},
+			quantity:    0,
+			realizedPnL: 200,
+			lots:        []lotState{},
+			matches:     [][]matchState{{{"01", 100, 200}}},
+		},
+		{
+			name:   "卖空开仓费用从开仓价中扣除",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeSell, 20, 100, 20),
+				trade("02", "600000", log.TypeBuy, 18, 50, 9),
+			},
+			quantity:      -50,
+			avgCost:       19.8,
+			realizedPnL:   (19.8 - 18.18) * 50,
+			unrealizedPnL: (18 - 19.8) * -50,
+			fees:          29,
+			lots:          []lotState{{"01", 50}},
+			matches:       [][]matchState{{{"01", 50, 81}}},
+		},
+		{
+			name:   "多头反手为空头",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", log.TypeSell, 12, 150, 0),
+			},
+			quantity:    -50,
+			avgCost:     12,
+			realizedPnL: 200,
+			lots:        []lotState{{"02", 50}},
+			matches:     [][]matchState{{{"01", 100, 200}}},
+		},
+		{
+			name:   "空头反手为多头",
+			method: MethodLIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeSell, 20, 100, 0),
+				trade("02", "600000", log.TypeBuy, 15, 130, 0),
+				trade("03", "600000", log.TypeSell, 16, 10, 0),
+			},
+			quantity:      20,
+			avgCost:       15,
+			realizedPnL:   500 + 10,
+			unrealizedPnL: 20,
+			lots:          []lotState{{"02", 20}},
+			matches:       [][]matchState{{{"01", 100, 500}}, {{"02", 10, 10}}},
+		},
+		{
+			name:   "忽略未知类型的日志",
+			method: MethodFIFO,
+			logs: []log.Log{
+				trade("01", "600000", log.TypeBuy, 10, 100, 0),
+				trade("02", "600000", "dividend", 1, 100, 0),
+			},
+			quantity: 100,
+			avgCost:  10,
+			lots:     []lotState{{"01", 100}},
+			matches:  [][]matchState{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions := Replay(tt.logs, tt.method)
+			if len(positions) != 1 {
+				t.Fatalf("持仓数量 = %d, 期望 1", len(positions))
+			}
+			p := positions[0]
+
+			if p.Method != tt.method {
+				t.Errorf("Method = %s, 期望 %s", p.Method, tt.method)
+			}
+			if p.Quantity != tt.quantity {
+				t.Errorf("Quantity = %d, 期望 %d", p.Quantity, tt.quantity)
+			}
+			if p.AvgCost != round4(tt.avgCost) {
+				t.Errorf("AvgCost = %v, 期望 %v", p.AvgCost, round4(tt.avgCost))
+			}
+			if p.RealizedPnL != round2(tt.realizedPnL) {
+				t.Errorf("RealizedPnL = %v, 期望 %v", p.RealizedPnL, round2(tt.realizedPnL))
+			}
+			if p.UnrealizedPnL != round2(tt.unrealizedPnL) {
+				t.Errorf("UnrealizedPnL = %v, 期望 %v", p.UnrealizedPnL, round2(tt.unrealizedPnL))
+			}
+			if p.TotalPnL != round2(p.RealizedPnL+p.UnrealizedPnL) {
+				t.Errorf("TotalPnL = %v, 期望 %v", p.TotalPnL, round2(p.RealizedPnL+p.UnrealizedPnL))
+			}
+			if p.Fees != tt.fees {
+				t.Errorf("Fees = %v, 期望 %v", p.Fees, tt.fees)
+			}
+
+			lots := []lotState{}
+			for _, lot := range p.Lots {
+				lots = append(lots, lotState{lot.LogID, lot.Quantity})
+			}
+			if !reflect.DeepEqual(lots, tt.lots) {
+				t.Errorf("Lots = %v, 期望 %v", lots, tt.lots)
+			}
+
+			matches := [][]matchState{}
+			for _, r := range p.Realizations {
+				items := []matchState{}
+				for _, m := range r.Matches {
+					items = append(items, matchState{m.LotID, m.Quantity, m.RealizedPnL})
+				}
+				matches = append(matches, items)
+			}
+			if !reflect.DeepEqual(matches, tt.matches) {
+				t.Errorf("Matches = %v, 期望 %v", matches, tt.matches)
+			}
+		})
+	}
+}
+
+func TestReplayRealization(t *testing.T) {
+	logs := []log.Log{
+		trade("01", "600000", log.TypeBuy, 10, 100, 10),
+		trade("02", "600000", log.TypeSell, 12, 100, 12),
+	}
+	r := Replay(logs, MethodFIFO)[0].Realizations[0]
+
+	want := Realization{
+		LogID:       "02",
+		StockCode:   "600000",
+		Type:        log.TypeSell,
+		Side:        SideLong,
+		TradingTime: "2024-01-01 10:00:02",
+		Price:       12,
+		Quantity:    100,
+		CostBasis:   1010,
+		Proceeds:    1188,
+		Fees:        12,
+		RealizedPnL: 178,
+		Matches: []LotMatch{{
+			LotID:       "01",
+			OpenTime:    "2024-01-01 10:00:01",
+			OpenPrice:   10.1,
+			Quantity:    100,
+			RealizedPnL: 178,
+		}},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Realization = %+v, 期望 %+v", r, want)
+	}
+}
+
+func TestReplayMultipleStocks(t *testing.T) {
+	logs := []log.Log{
+		trade("01", "600000", log.TypeBuy, 10, 100, 0),
+		trade("02", "000001", log.TypeBuy, 20, 100, 0),
+		trade("03", "600000", log.TypeSell, 11, 100, 0),
+	}
+	positions := Replay(logs, MethodFIFO)
+
+	codes := []string{}
+	for _, p := range positions {
+		codes = append(codes, p.StockCode)
+	}
+	if want := []string{"600000", "000001"}; !reflect.DeepEqual(codes, want) {
+		t.Fatalf("股票顺序 = %v, 期望 %v", codes, want)
+	}
+	if positions[0].Quantity != 0 || positions[0].RealizedPnL != 100 {
+		t.Errorf("600000 数量 = %d, 已实现盈亏 = %v", positions[0].Quantity, positions[0].RealizedPnL)
+	}
+	if positions[1].Quantity != 100 || positions[1].RealizedPnL != 0 {
+		t.Errorf("000001 数量 = %d, 已实现盈亏 = %v", positions[1].Quantity, positions[1].RealizedPnL)
+	}
+}
+
+func TestLotRemaining(t *testing.T) {
+	logs := []log.Log{
+		trade("01", "600000", log.TypeBuy, 10, 100, 0),
+		trade("02", "600000", log.TypeBuy, 12, 100, 0),
+		trade("03", "600000", log.TypeSell, 15, 150, 0),
+		trade("04", "000001", log.TypeBuy, 20, 100, 0),
+		trade("05", "000001", log.TypeSell, 21, 120, 0),
+	}
+
+	tests := []struct {
+		method string
+		want   map[string]int
+	}{
+		{MethodFIFO, map[string]int{"01": 0, "02": 50, "04": 0, "05": 20}},
+		{MethodLIFO, map[string]int{"01": 50, "02": 0, "04": 0, "05": 20}},
+		{MethodAverage, map[string]int{"01": 0, "02": 50, "04": 0, "05": 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			got := lotRemaining(Replay(logs, tt.method))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lotRemaining = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		method, defaultMethod string
+		want                  string
+		wantErr               bool
+	}{
+		{"", MethodAverage, MethodAverage, false},
+		{MethodFIFO, MethodAverage, MethodFIFO, false},
+		{MethodLIFO, MethodFIFO, MethodLIFO, false},
+		{"hifo", MethodFIFO, "", true},
+		{"", "unknown", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMethod(tt.method, tt.defaultMethod)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMethod(%q, %q) = %q, %v", tt.method, tt.defaultMethod, got, err)
+		}
+	}
+}
+
+func round4(f float64) float64 {
+	return math.Round(f*10000) / 10000
+}
@@ -0,0 +1,85 @@
+package position
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPositionRoutes 注册持仓路由
+func RegisterPositionRoutes(r *gin.RouterGroup) {
+	positionService := NewPositionService()
+
+	g := r.Group("/positions")
+	{
+		g.GET("/getList", func(c *gin.Context) {
+			req := &PositionListRequest{
+				StockCode:     c.Query("stockCode"),
+				EndDate:       c.Query("endDate"),
+				IncludeClosed: c.Query("includeClosed") == "true",
+				Method:        c.Query("method"),
+			}
+
+			response, err := positionService.ListPositions(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.GET("/getDetail/:stockCode", func(c *gin.Context) {
+			stockCode := c.Param("stockCode")
+			if stockCode == "" {
+				handler.Error(c, handler.CodeInvalid, "股票代码不能为空")
+				return
+			}
+
+			position, err := positionService.GetPosition(middleware.GetCurrentUserID(c), stockCode, c.Query("method"))
+			if err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, position)
+		})
+
+		g.GET("/getRealizations", func(c *gin.Context) {
+			req := &RealizationListRequest{
+				StockCode: c.Query("stockCode"),
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+				Method:    c.Query("method"),
+			}
+
+			response, err := positionService.ListRealizations(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.GET("/getTrades", func(c *gin.Context) {
+			req := &TradeListRequest{
+				StockCode: c.Query("stockCode"),
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+				Strategy:  c.Query("strategy"),
+				PlanID:    c.Query("planId"),
+				Method:    c.Query("method"),
+			}
+
+			response, err := positionService.ListTrades(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package position
+
+import (
+	"fmt"
+
+	"server/config"
+	"server/modules/log"
+	"server/storage"
+	"server/utils"
+)
+
+// lotTracker 按默认成本计算方法回放已成交日志，将开仓批次的剩余数量写入 logs.lot_remaining
+type lotTracker struct {
+	logService log.LogService
+}
+
+// NewLotTracker 创建批次跟踪，注入日志模块后在日志写入、修改和删除时调用
+func NewLotTracker() log.LotTracker {
+	return &lotTracker{logService: log.NewLogService()}
+}
+
+// SyncLots 重新计算指定股票的开仓批次，失败只记录日志
+func (t *lotTracker) SyncLots(userID string, stockCodes ...string) {
+	for _, code := range stockCodes {
+		if err := t.sync(userID, code); err != nil {
+			utils.LogWarning("更新开仓批次失败，股票代码: %s, 错误: %v", code, err)
+		}
+	}
+}
+
+// sync 回放一只股票的已成交日志并在事务中更新批次剩余数量
+// 已全部平仓的开仓日志记为 0，非开仓日志及未成交日志为空
+func (t *lotTracker) sync(userID, stockCode string) error {
+	method, err := ParseMethod("", config.Load().CostBasisMethod)
+	if err != nil {
+		return err
+	}
+	logs, err := t.logService.ListAllLogs(userID, &log.LogListRequest{
+		StockCode: stockCode,
+		Status:    log.StatusCompleted,
+	})
+	if err != nil {
+		return err
+	}
+
+	remaining := lotRemaining(Replay(logs, method))
+
+	tx, err := storage.GetDB().SQL.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE logs SET lot_remaining = NULL WHERE user_id = ? AND stock_code = ?", userID, stockCode); err != nil {
+		return err
+	}
+	for id, qty := range remaining {
+		if _, err := tx.Exec("UPDATE logs SET lot_remaining = ? WHERE id = ? AND user_id = ?", qty, id, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// lotRemaining 回放结果中每笔开仓日志的剩余未平仓数量，已全部平仓的批次为 0
+func lotRemaining(positions []*Position) map[string]int {
+	remaining := map[string]int{}
+	for _, p := range positions {
+		for _, r := range p.Realizations {
+			for _, m := range r.Matches {
+				remaining[m.LotID] = 0
+			}
+		}
+		for _, lot := range p.Lots {
+			remaining[lot.LogID] = lot.Quantity
+		}
+	}
+	return remaining
+}
+
+// RebuildLots 服务启动时重新计算全部用户的开仓批次，默认成本计算方法变更后也能保持一致
+func RebuildLots() error {
+	// 迁移前的旧数据没有归属用户，不属于任何用户的持仓
+	rows, err := storage.GetDB().Query("SELECT DISTINCT user_id, stock_code FROM logs WHERE user_id IS NOT NULL")
+	if err != nil {
+		return fmt.Errorf("查询日志股票失败: %w", err)
+	}
+	type key struct{ userID, stockCode string }
+	var keys []key
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.userID, &k.stockCode); err != nil {
+			rows.Close()
+			return fmt.Errorf("扫描日志股票失败: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历日志股票失败: %w", err)
+	}
+
+	t := &lotTracker{logService: log.NewLogService()}
+	for _, k := range keys {
+		if err := t.sync(k.userID, k.stockCode); err != nil {
+			return fmt.Errorf("更新开仓批次失败，股票代码: %s: %w", k.stockCode, err)
+		}
+	}
+	utils.LogInfo("开仓批次重新计算完成，共 %d 只股票", len(keys))
+	return nil
+}
@@ -0,0 +1,185 @@
+package position
+
+// 成本计算方法
+const (
+	MethodFIFO    = "fifo"    // 先进先出
+	MethodLIFO    = "lifo"    // 后进先出
+	MethodAverage = "average" // 移动加权平均
+)
+
+// 持仓方向
+const (
+	SideLong  = "long"  // 多头
+	SideShort = "short" // 空头
+)
+
+// Position 单只股票的持仓及盈亏，由交易日志按时间顺序回放得出
+type Position struct {
+	StockCode      string        `json:"stockCode"`
+	StockName      string        `json:"stockName"`
+	Method         string        `json:"method"`        // 成本计算方法
+	Quantity       int           `json:"quantity"`      // 当前持仓数量，正数为多头，负数为空头
+	AvgCost        float64       `json:"avgCost"`       // 持仓均价（含开仓费用）
+	CostBasis      float64       `json:"costBasis"`     // 持仓成本 = |数量| * 均价
+	LastPrice      float64       `json:"lastPrice"`     // 最新价格（取最后一笔成交价）
+	MarketValue    float64       `json:"marketValue"`   // 持仓市值
+	RealizedPnL    float64       `json:"realizedPnl"`   // 已实现盈亏
+	UnrealizedPnL  float64       `json:"unrealizedPnl"` // 浮动盈亏
+	TotalPnL       float64       `json:"totalPnl"`      // 总盈亏
+	Fees           float64       `json:"fees"`          // 累计交易费用
+	BuyCount       int           `json:"buyCount"`
+	SellCount      int           `json:"sellCount"`
+	FirstTradeTime string        `json:"firstTradeTime"`
+	LastTradeTime  string        `json:"lastTradeTime"`
+	Lots           []Lot         `json:"lots"`                   // 未平仓批次
+	Realizations   []Realization `json:"realizations,omitempty"` // 平仓明细，仅详情接口返回
+
+	// 本位币换算，已实现盈亏和费用按成交日汇率，其余按查询截止日汇率；缺少汇率时 fxRate 为 0
+	Currency          string  `json:"currency"`
+	BaseCurrency      string  `json:"baseCurrency"`
+	FxRate            float64 `json:"fxRate"`
+	BaseCostBasis     float64 `json:"baseCostBasis"`
+	BaseMarketValue   float64 `json:"baseMarketValue"`
+	BaseRealizedPnL   float64 `json:"baseRealizedPnl"`
+	BaseUnrealizedPnL float64 `json:"baseUnrealizedPnl"`
+	BaseTotalPnL      float64 `json:"baseTotalPnl"`
+	BaseFees          float64 `json:"baseFees"`
+}
+
+// Lot 开仓批次，每笔开仓日志对应一个批次
+type Lot struct {
+	LogID            string  `json:"logId"`
+	TradingTime      string  `json:"tradingTime"`
+	Price            float64 `json:"price"`
+	Cost             float64 `json:"cost"` // 含开仓费用的每股成本
+	OriginalQuantity int     `json:"originalQuantity"`
+	Quantity         int     `json:"quantity"` // 剩余未平仓数量
+}
+
+// Realization 一笔平仓成交的已实现盈亏
+type Realization struct {
+	LogID       string     `json:"logId"`
+	StockCode   string     `json:"stockCode"`
+	StockName   string     `json:"stockName"`
+	Type        string     `json:"type"`
+	Side        string     `json:"side"` // 被平掉的持仓方向
+	TradingTime string     `json:"tradingTime"`
+	Price       float64    `json:"price"`
+	Quantity    int        `json:"quantity"`  // 实际平仓数量（反手时不含新开仓部分）
+	CostBasis   float64    `json:"costBasis"` // 匹配批次的开仓金额
+	Proceeds    float64    `json:"proceeds"`  // 平仓金额（扣除平仓费用）
+	Fees        float64    `json:"fees"`      // 平仓部分分摊的本笔费用
+	RealizedPnL float64    `json:"realizedPnl"`
+	Matches     []LotMatch `json:"matches"`
+
+	Currency        string  `json:"currency"`
+	FxRate          float64 `json:"fxRate"` // 平仓日汇率
+	BaseRealizedPnL float64 `json:"baseRealizedPnl"`
+}
+
+// LotMatch 平仓成交与开仓批次的匹配明细
+type LotMatch struct {
+	LotID       string  `json:"lotId"` // 开仓日志ID
+	OpenTime    string  `json:"openTime"`
+	OpenPrice   float64 `json:"openPrice"` // 匹配成本价（含开仓费用），加权平均法下为当时的持仓均价
+	Quantity    int     `json:"quantity"`
+	RealizedPnL float64 `json:"realizedPnl"`
+}
+
+// PositionListRequest 持仓列表请求
+type PositionListRequest struct {
+	StockCode     string `form:"stockCode"`
+	EndDate       string `form:"endDate"`       // 只回放该时间之前的日志，用于查看历史持仓
+	IncludeClosed bool   `form:"includeClosed"` // 是否包含已清仓的股票
+	Method        string `form:"method"`        // 成本计算方法，为空时使用配置默认值
+}
+
+// PositionListResponse 持仓列表响应
+type PositionListResponse struct {
+	Items              []Position `json:"list"`
+	Total              int        `json:"total"`
+	Method             string     `json:"method"`
+	TotalCostBasis     float64    `json:"totalCostBasis"`
+	TotalMarketValue   float64    `json:"totalMarketValue"`
+	TotalRealizedPnL   float64    `json:"totalRealizedPnl"`
+	TotalUnrealizedPnL float64    `json:"totalUnrealizedPnl"`
+	TotalPnL           float64    `json:"totalPnl"`
+	TotalFees          float64    `json:"totalFees"`
+
+	// 原币合计在多币种持仓时没有意义，汇总应使用本位币字段
+	BaseCurrency           string   `json:"baseCurrency"`
+	BaseTotalCostBasis     float64  `json:"baseTotalCostBasis"`
+	BaseTotalMarketValue   float64  `json:"baseTotalMarketValue"`
+	BaseTotalRealizedPnL   float64  `json:"baseTotalRealizedPnl"`
+	BaseTotalUnrealizedPnL float64  `json:"baseTotalUnrealizedPnl"`
+	BaseTotalPnL           float64  `json:"baseTotalPnl"`
+	BaseTotalFees          float64  `json:"baseTotalFees"`
+	MissingRates           []string `json:"missingRates"` // 缺少汇率的币种，对应金额未计入本位币合计
+}
+
+// RealizationListRequest 平仓明细列表请求
+type RealizationListRequest struct {
+	StockCode string `form:"stockCode"`
+	StartDate string `form:"startDate"` // 按平仓时间过滤
+	EndDate   string `form:"endDate"`
+	Method    string `form:"method"`
+}
+
+// RealizationListResponse 平仓明细列表响应
+type RealizationListResponse struct {
+	Items            []Realization `json:"list"`
+	Total            int           `json:"total"`
+	Method           string        `json:"method"`
+	TotalRealizedPnL float64       `json:"totalRealizedPnl"`
+
+	BaseCurrency         string   `json:"baseCurrency"`
+	BaseTotalRealizedPnL float64  `json:"baseTotalRealizedPnl"`
+	MissingRates         []string `json:"missingRates"`
+}
+
+// Trade 一笔已平仓交易，由一个开仓批次与一笔平仓成交匹配得出
+// 策略和计划取开仓日志的设置，开仓日志未设置时取平仓日志的设置
+type Trade struct {
+	OpenLogID   string  `json:"openLogId"`
+	CloseLogID  string  `json:"closeLogId"`
+	StockCode   string  `json:"stockCode"`
+	StockName   string  `json:"stockName"`
+	Side        string  `json:"side"`
+	OpenTime    string  `json:"openTime"`
+	CloseTime   string  `json:"closeTime"`
+	Quantity    int     `json:"quantity"`
+	OpenPrice   float64 `json:"openPrice"`   // 开仓成本价（含开仓费用）
+	ClosePrice  float64 `json:"closePrice"`  // 平仓成交价
+	PnL         float64 `json:"pnl"`         // 已实现盈亏，已扣除开平仓费用
+	PnLPct      float64 `json:"pnlPct"`      // 盈亏占开仓金额的比例（%）
+	HoldingDays float64 `json:"holdingDays"` // 持仓天数，按开平仓时间差计算
+	Strategy    string  `json:"strategy"`    // 交易策略
+	PlanID      string  `json:"planId"`
+	PlanName    string  `json:"planName"`
+
+	Currency string  `json:"currency"`
+	FxRate   float64 `json:"fxRate"` // 平仓日汇率，缺少汇率时为 0
+	BasePnL  float64 `json:"basePnl"`
+}
+
+// TradeListRequest 已平仓交易列表请求
+type TradeListRequest struct {
+	StockCode string `form:"stockCode"`
+	StartDate string `form:"startDate"` // 按平仓时间过滤
+	EndDate   string `form:"endDate"`
+	Strategy  string `form:"strategy"`
+	PlanID    string `form:"planId"`
+	Method    string `form:"method"`
+}
+
+// TradeListResponse 已平仓交易列表响应
+type TradeListResponse struct {
+	Items    []Trade `json:"list"`
+	Total    int     `json:"total"`
+	Method   string  `json:"method"`
+	TotalPnL float64 `json:"totalPnl"`
+
+	BaseCurrency string   `json:"baseCurrency"`
+	BaseTotalPnL float64  `json:"baseTotalPnl"`
+	MissingRates []string `json:"missingRates"`
+}
@@ -0,0 +1,185 @@
+package position
+
+import (
+	"fmt"
+	"sort"
+
+	"server/config"
+	"server/modules/fx"
+	"server/modules/log"
+	"server/utils"
+)
+
+// PositionService 持仓服务接口
+type PositionService interface {
+	ListPositions(userID string, req *PositionListRequest) (*PositionListResponse, error)
+	GetPosition(userID, stockCode string, method string) (*Position, error)
+	ListRealizations(userID string, req *RealizationListRequest) (*RealizationListResponse, error)
+	ListTrades(userID string, req *TradeListRequest) (*TradeListResponse, error)
+}
+
+// positionService 持仓服务实现
+type positionService struct {
+	logService log.LogService
+}
+
+// NewPositionService 创建持仓服务
+func NewPositionService() PositionService {
+	return &positionService{
+		logService: log.NewLogService(),
+	}
+}
+
+// replay 读取日志并按指定成本计算方法回放，同时换算为本位币，返回持仓和回放使用的日志
+func (s *positionService) replay(userID, stockCode, endDate, method string) ([]*Position, string, *fx.Converter, []log.Log, error) {
+	method, err := ParseMethod(method, config.Load().CostBasisMethod)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	logs, err := s.logService.ListAllLogs(userID, &log.LogListRequest{
+		StockCode: stockCode,
+		Status:    log.StatusCompleted,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	positions := Replay(logs, method)
+	conv := s.logService.ConvertLogs(userID, logs)
+	convertPositions(positions, logs, conv, endDate)
+	utils.LogDebug("持仓计算完成，成本方法: %s，共回放 %d 条日志，%d 只股票", method, len(logs), len(positions))
+	return positions, method, conv, logs, nil
+}
+
+// ListPositions 获取持仓列表
+func (s *positionService) ListPositions(userID string, req *PositionListRequest) (*PositionListResponse, error) {
+	positions, method, conv, _, err := s.replay(userID, req.StockCode, req.EndDate, req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合计包含已平仓股票的已实现盈亏和费用，是否返回已平仓股票只影响列表
+	resp := &PositionListResponse{Items: []Position{}, Method: method, BaseCurrency: conv.Base()}
+	for _, p := range positions {
+		resp.TotalCostBasis += p.CostBasis
+		resp.TotalMarketValue += p.MarketValue
+		resp.TotalRealizedPnL += p.RealizedPnL
+		resp.TotalUnrealizedPnL += p.UnrealizedPnL
+		resp.TotalFees += p.Fees
+		resp.BaseTotalCostBasis += p.BaseCostBasis
+		resp.BaseTotalMarketValue += p.BaseMarketValue
+		resp.BaseTotalRealizedPnL += p.BaseRealizedPnL
+		resp.BaseTotalUnrealizedPnL += p.BaseUnrealizedPnL
+		resp.BaseTotalFees += p.BaseFees
+
+		if p.Quantity == 0 && !req.IncludeClosed {
+			continue
+		}
+		p.Realizations = nil
+		resp.Items = append(resp.Items, *p)
+	}
+	resp.Total = len(resp.Items)
+	resp.TotalCostBasis = round2(resp.TotalCostBasis)
+	resp.TotalMarketValue = round2(resp.TotalMarketValue)
+	resp.TotalRealizedPnL = round2(resp.TotalRealizedPnL)
+	resp.TotalUnrealizedPnL = round2(resp.TotalUnrealizedPnL)
+	resp.TotalPnL = round2(resp.TotalRealizedPnL + resp.TotalUnrealizedPnL)
+	resp.TotalFees = round2(resp.TotalFees)
+	resp.BaseTotalCostBasis = round2(resp.BaseTotalCostBasis)
+	resp.BaseTotalMarketValue = round2(resp.BaseTotalMarketValue)
+	resp.BaseTotalRealizedPnL = round2(resp.BaseTotalRealizedPnL)
+	resp.BaseTotalUnrealizedPnL = round2(resp.BaseTotalUnrealizedPnL)
+	resp.BaseTotalPnL = round2(resp.BaseTotalRealizedPnL + resp.BaseTotalUnrealizedPnL)
+	resp.BaseTotalFees = round2(resp.BaseTotalFees)
+	resp.MissingRates = conv.Missing()
+
+	return resp, nil
+}
+
+// GetPosition 获取单只股票的持仓详情，包含每笔平仓的批次匹配明细
+func (s *positionService) GetPosition(userID, stockCode string, method string) (*Position, error) {
+	positions, _, _, _, err := s.replay(userID, stockCode, "", method)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("该股票没有交易记录")
+	}
+	return positions[0], nil
+}
+
+// ListRealizations 获取平仓明细列表，按平仓时间升序排列
+func (s *positionService) ListRealizations(userID string, req *RealizationListRequest) (*RealizationListResponse, error) {
+	// 开始时间之前的日志也需要回放，才能得到正确的开仓批次
+	positions, method, conv, _, err := s.replay(userID, req.StockCode, req.EndDate, req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &RealizationListResponse{Items: []Realization{}, Method: method, BaseCurrency: conv.Base(), MissingRates: []string{}}
+	missing := map[string]bool{}
+	for _, p := range positions {
+		for _, r := range p.Realizations {
+			if req.StartDate != "" && r.TradingTime < req.StartDate {
+				continue
+			}
+			resp.Items = append(resp.Items, r)
+			resp.TotalRealizedPnL += r.RealizedPnL
+			resp.BaseTotalRealizedPnL += r.BaseRealizedPnL
+			if r.FxRate == 0 {
+				missing[r.Currency] = true
+			}
+		}
+	}
+	sortRealizations(resp.Items)
+	resp.Total = len(resp.Items)
+	resp.TotalRealizedPnL = round2(resp.TotalRealizedPnL)
+	resp.BaseTotalRealizedPnL = round2(resp.BaseTotalRealizedPnL)
+	for currency := range missing {
+		resp.MissingRates = append(resp.MissingRates, currency)
+	}
+	sort.Strings(resp.MissingRates)
+
+	return resp, nil
+}
+
+// ListTrades 获取已平仓交易列表，按平仓时间升序排列
+func (s *positionService) ListTrades(userID string, req *TradeListRequest) (*TradeListResponse, error) {
+	// 开始时间之前的日志也需要回放，才能得到正确的开仓批次
+	positions, method, conv, logs, err := s.replay(userID, req.StockCode, req.EndDate, req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TradeListResponse{Items: []Trade{}, Method: method, BaseCurrency: conv.Base(), MissingRates: []string{}}
+	missing := map[string]bool{}
+	for _, t := range buildTrades(positions, logs) {
+		if req.StartDate != "" && t.CloseTime < req.StartDate {
+			continue
+		}
+		if req.Strategy != "" && t.Strategy != req.Strategy {
+			continue
+		}
+		if req.PlanID != "" && t.PlanID != req.PlanID {
+			continue
+		}
+		resp.Items = append(resp.Items, t)
+		resp.TotalPnL += t.PnL
+		resp.BaseTotalPnL += t.BasePnL
+		if t.FxRate == 0 {
+			missing[t.Currency] = true
+		}
+	}
+	resp.Total = len(resp.Items)
+	resp.TotalPnL = round2(resp.TotalPnL)
+	resp.BaseTotalPnL = round2(resp.BaseTotalPnL)
+	for currency := range missing {
+		resp.MissingRates = append(resp.MissingRates, currency)
+	}
+	sort.Strings(resp.MissingRates)
+
+	return resp, nil
+}
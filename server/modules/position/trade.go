@@ -0,0 +1,83 @@
+package position
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"server/modules/log"
+)
+
+// buildTrades 将持仓的平仓明细按匹配批次拆分为已平仓交易，按平仓时间升序排列
+// positions 需已由 convertPositions 换算本位币，logs 为回放使用的日志
+func buildTrades(positions []*Position, logs []log.Log) []Trade {
+	index := make(map[string]*log.Log, len(logs))
+	for i := range logs {
+		index[logs[i].ID] = &logs[i]
+	}
+
+	trades := []Trade{}
+	for _, p := range positions {
+		for _, r := range p.Realizations {
+			closeLog := index[r.LogID]
+			for _, m := range r.Matches {
+				t := Trade{
+					OpenLogID:  m.LotID,
+					CloseLogID: r.LogID,
+					StockCode:  r.StockCode,
+					StockName:  r.StockName,
+					Side:       r.Side,
+					OpenTime:   m.OpenTime,
+					CloseTime:  r.TradingTime,
+					Quantity:   m.Quantity,
+					OpenPrice:  math.Round(m.OpenPrice*10000) / 10000,
+					ClosePrice: r.Price,
+					PnL:        m.RealizedPnL,
+					Currency:   r.Currency,
+					FxRate:     r.FxRate,
+					BasePnL:    round2(m.RealizedPnL * r.FxRate),
+				}
+				if amount := m.OpenPrice * float64(m.Quantity); amount > 0 {
+					t.PnLPct = round2(m.RealizedPnL / amount * 100)
+				}
+				t.HoldingDays = holdingDays(t.OpenTime, t.CloseTime)
+
+				for _, l := range []*log.Log{index[m.LotID], closeLog} {
+					if l == nil {
+						continue
+					}
+					if t.Strategy == "" {
+						t.Strategy = l.Strategy
+					}
+					if t.PlanID == "" {
+						t.PlanID, t.PlanName = l.PlanID, l.PlanName
+					}
+				}
+				trades = append(trades, t)
+			}
+		}
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].CloseTime < trades[j].CloseTime
+	})
+	return trades
+}
+
+// holdingDays 计算开平仓时间相差的天数，保留两位小数
+func holdingDays(openTime, closeTime string) float64 {
+	open, err1 := parseTradingTime(openTime)
+	close, err2 := parseTradingTime(closeTime)
+	if err1 != nil || err2 != nil || close.Before(open) {
+		return 0
+	}
+	return round2(close.Sub(open).Hours() / 24)
+}
+
+// parseTradingTime 解析日志的交易时间，兼容只有日期的情况
+func parseTradingTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02 15:04:05", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,193 @@
+package price
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportErrors 导入结果中最多返回的错误行数
+const maxImportErrors = 50
+
+// columnAliases CSV 表头别名，表头不区分大小写
+var columnAliases = map[string][]string{
+	"code":   {"code", "stock_code", "stockcode", "symbol", "ts_code", "代码", "股票代码"},
+	"time":   {"date", "time", "datetime", "trade_date", "trade_time", "tradetime", "日期", "时间", "交易日期"},
+	"open":   {"open", "开盘", "开盘价"},
+	"high":   {"high", "最高", "最高价"},
+	"low":    {"low", "最低", "最低价"},
+	"close":  {"close", "收盘", "收盘价"},
+	"volume": {"volume", "vol", "成交量"},
+	"amount": {"amount", "turnover", "成交额"},
+}
+
+// timeLayouts 支持的日期时间格式
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"20060102",
+}
+
+// parseTradeTime 解析交易时间并统一格式，日线只保留日期
+func parseTradeTime(value, interval string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if interval == IntervalDaily {
+			return t.Format("2006-01-02"), nil
+		}
+		return t.Format("2006-01-02 15:04"), nil
+	}
+	return "", fmt.Errorf("无法识别的时间格式: %s", value)
+}
+
+// mapColumns 根据表头确定各字段所在列
+func mapColumns(header []string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for field, aliases := range columnAliases {
+			if _, ok := index[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					index[field] = i
+					break
+				}
+			}
+		}
+	}
+	for _, field := range []string{"time", "open", "high", "low", "close"} {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("CSV 缺少 %s 列", field)
+		}
+	}
+	return index, nil
+}
+
+// parseCSV 解析行情 CSV，返回有效的K线、总行数和出错的行
+func parseCSV(r io.Reader, defaultCode, interval, source string) ([]Price, int, []ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+	index, err := mapColumns(header)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if _, ok := index["code"]; !ok && defaultCode == "" {
+		return nil, 0, nil, fmt.Errorf("CSV 没有代码列时需要指定 stockCode")
+	}
+
+	var prices []Price
+	var errs []ImportError
+	total := 0
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			errs = append(errs, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		total++
+
+		p, err := parseRecord(record, index, defaultCode, interval, source)
+		if err != nil {
+			errs = append(errs, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		prices = append(prices, *p)
+	}
+
+	return prices, total, errs, nil
+}
+
+// parseRecord 解析一行K线数据并校验价格关系
+func parseRecord(record []string, index map[string]int, defaultCode, interval, source string) (*Price, error) {
+	field := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	number := func(name string, required bool) (float64, error) {
+		v := strings.ReplaceAll(field(name), ",", "")
+		if v == "" {
+			if required {
+				return 0, fmt.Errorf("%s 不能为空", name)
+			}
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, fmt.Errorf("%s 不是有效数字: %s", name, v)
+		}
+		return f, nil
+	}
+
+	p := &Price{StockCode: field("code"), Interval: interval, Source: source}
+	if p.StockCode == "" {
+		p.StockCode = defaultCode
+	}
+	if p.StockCode == "" {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+
+	var err error
+	if p.TradeTime, err = parseTradeTime(field("time"), interval); err != nil {
+		return nil, err
+	}
+	if p.Open, err = number("open", true); err != nil {
+		return nil, err
+	}
+	if p.High, err = number("high", true); err != nil {
+		return nil, err
+	}
+	if p.Low, err = number("low", true); err != nil {
+		return nil, err
+	}
+	if p.Close, err = number("close", true); err != nil {
+		return nil, err
+	}
+	if p.Volume, err = number("volume", false); err != nil {
+		return nil, err
+	}
+	if p.Amount, err = number("amount", false); err != nil {
+		return nil, err
+	}
+
+	if p.Low <= 0 || p.Open <= 0 || p.Close <= 0 {
+		return nil, fmt.Errorf("价格必须大于0")
+	}
+	if p.High < math.Max(p.Open, p.Close) || p.Low > math.Min(p.Open, p.Close) {
+		return nil, fmt.Errorf("最高价/最低价与开盘价、收盘价不匹配")
+	}
+	if p.Volume < 0 || p.Amount < 0 {
+		return nil, fmt.Errorf("成交量和成交额不能为负数")
+	}
+	return p, nil
+}
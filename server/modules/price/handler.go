@@ -0,0 +1,58 @@
+package price
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPriceRoutes 注册行情路由
+func RegisterPriceRoutes(r *gin.RouterGroup) {
+	priceService := NewPriceService()
+
+	g := r.Group("/prices")
+	{
+		g.POST("/import", func(c *gin.Context) {
+			var req PriceImportRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			result, err := priceService.ImportCSV(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, result)
+		})
+
+		g.GET("/getList", func(c *gin.Context) {
+			var req PriceListRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			response, err := priceService.ListPrices(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+
+		g.GET("/latest/:stockCode", func(c *gin.Context) {
+			p, err := priceService.GetLatest(middleware.GetCurrentUserID(c), c.Param("stockCode"), c.Query("interval"))
+			if err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, p)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package price
+
+// K线周期
+const (
+	IntervalDaily = "1d"
+	Interval1Min  = "1m"
+	Interval5Min  = "5m"
+	Interval15Min = "15m"
+	Interval30Min = "30m"
+	Interval60Min = "60m"
+)
+
+// validIntervals 支持的K线周期
+var validIntervals = map[string]bool{
+	IntervalDaily: true,
+	Interval1Min:  true,
+	Interval5Min:  true,
+	Interval15Min: true,
+	Interval30Min: true,
+	Interval60Min: true,
+}
+
+// Price K线行情（OHLCV），日线的交易时间为 YYYY-MM-DD，分钟线为 YYYY-MM-DD HH:MM
+type Price struct {
+	StockCode string  `json:"stockCode" db:"stock_code"`
+	Interval  string  `json:"interval" db:"interval"`
+	TradeTime string  `json:"tradeTime" db:"trade_time"`
+	Open      float64 `json:"open" db:"open"`
+	High      float64 `json:"high" db:"high"`
+	Low       float64 `json:"low" db:"low"`
+	Close     float64 `json:"close" db:"close"`
+	Volume    float64 `json:"volume" db:"volume"`
+	Amount    float64 `json:"amount" db:"amount"`
+	Source    string  `json:"source" db:"source"`
+}
+
+// PriceListRequest 行情查询请求
+type PriceListRequest struct {
+	StockCode string `form:"stockCode" binding:"required"`
+	Interval  string `form:"interval"`
+	StartDate string `form:"startDate"`
+	EndDate   string `form:"endDate"`
+}
+
+// PriceListResponse 行情查询响应，按交易时间升序
+type PriceListResponse struct {
+	Items     []Price `json:"list"`
+	Total     int     `json:"total"`
+	StockCode string  `json:"stockCode"`
+	Interval  string  `json:"interval"`
+}
+
+// PriceImportRequest 行情导入请求，文件需先通过分片上传接口上传
+type PriceImportRequest struct {
+	FileName string `json:"fileName" binding:"required"`
+	// StockCode CSV 中没有代码列时使用
+	StockCode string `json:"stockCode"`
+	Interval  string `json:"interval"`
+	Source    string `json:"source"`
+}
+
+// ImportError 导入失败的行
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// PriceImportResult 行情导入结果
+type PriceImportResult struct {
+	Total    int           `json:"total"`
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Codes    []string      `json:"codes"`
+	Errors   []ImportError `json:"errors"`
+}
@@ -0,0 +1,118 @@
+package price
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+	"time"
+)
+
+// PriceRepository 行情数据访问层
+type PriceRepository struct{}
+
+// NewPriceRepository 创建行情仓库
+func NewPriceRepository() *PriceRepository {
+	return &PriceRepository{}
+}
+
+// visibleCondition 用户可见的K线：自己导入的K线，以及自己没有导入同一时间K线时的公共K线
+// userID 为空时只有公共K线
+const visibleCondition = `(p.user_id = ? OR (p.user_id IS NULL AND NOT EXISTS (
+	SELECT 1 FROM prices o WHERE o.user_id = ? AND o.stock_code = p.stock_code
+	AND o.interval = p.interval AND o.trade_time = p.trade_time)))`
+
+// Upsert 在事务中批量写入用户的K线，该用户已导入的同一时间K线会被覆盖，公共K线和其他用户的K线不受影响
+func (r *PriceRepository) Upsert(userID string, prices []Price) error {
+	tx, err := storage.GetDB().SQL.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO prices (
+		user_id, stock_code, interval, trade_time, open, high, low, close, volume, amount, source, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(user_id, stock_code, interval, trade_time) DO UPDATE SET
+		open = excluded.open, high = excluded.high, low = excluded.low, close = excluded.close,
+		volume = excluded.volume, amount = excluded.amount, source = excluded.source,
+		updated_at = excluded.updated_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, p := range prices {
+		if _, err := stmt.Exec(userID, p.StockCode, p.Interval, p.TradeTime, p.Open, p.High, p.Low, p.Close,
+			p.Volume, p.Amount, p.Source, now, now); err != nil {
+			return fmt.Errorf("%s %s: %w", p.StockCode, p.TradeTime, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// List 查询用户可见的K线，按交易时间升序
+func (r *PriceRepository) List(userID, stockCode, interval, startTime, endTime string) ([]Price, error) {
+	where := []string{"p.stock_code = ?", "p.interval = ?", visibleCondition}
+	args := []interface{}{stockCode, interval, userID, userID}
+
+	if startTime != "" {
+		where = append(where, "p.trade_time >= ?")
+		args = append(args, startTime)
+	}
+	if endTime != "" {
+		where = append(where, "p.trade_time <= ?")
+		args = append(args, endTime)
+	}
+
+	query := fmt.Sprintf(`SELECT p.stock_code, p.interval, p.trade_time, p.open, p.high, p.low, p.close, p.volume, p.amount, p.source
+		FROM prices p WHERE %s ORDER BY p.trade_time ASC`, strings.Join(where, " AND "))
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []Price{}
+	for rows.Next() {
+		var p Price
+		var source sql.NullString
+		if err := rows.Scan(&p.StockCode, &p.Interval, &p.TradeTime, &p.Open, &p.High, &p.Low, &p.Close,
+			&p.Volume, &p.Amount, &source); err != nil {
+			return nil, err
+		}
+		p.Source = source.String
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// GetLatest 获取用户可见的最新一根K线
+func (r *PriceRepository) GetLatest(userID, stockCode, interval string) (*Price, error) {
+	query := `SELECT p.stock_code, p.interval, p.trade_time, p.open, p.high, p.low, p.close, p.volume, p.amount, p.source
+		FROM prices p WHERE p.stock_code = ? AND p.interval = ? AND ` + visibleCondition + `
+		ORDER BY p.trade_time DESC LIMIT 1`
+
+	var p Price
+	var source sql.NullString
+	err := storage.GetDB().QueryRow(query, stockCode, interval, userID, userID).Scan(
+		&p.StockCode, &p.Interval, &p.TradeTime, &p.Open, &p.High, &p.Low, &p.Close,
+		&p.Volume, &p.Amount, &source,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("没有行情数据")
+		}
+		return nil, err
+	}
+	p.Source = source.String
+
+	return &p, nil
+}
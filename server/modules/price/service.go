@@ -0,0 +1,133 @@
+package price
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"server/handler"
+	"server/utils"
+)
+
+// PriceService 行情服务接口
+type PriceService interface {
+	ImportCSV(userID string, req *PriceImportRequest) (*PriceImportResult, error)
+	ListPrices(userID string, req *PriceListRequest) (*PriceListResponse, error)
+	GetLatest(userID, stockCode, interval string) (*Price, error)
+}
+
+// priceService 行情服务实现
+type priceService struct {
+	priceRepo *PriceRepository
+}
+
+// NewPriceService 创建行情服务
+func NewPriceService() PriceService {
+	return &priceService{
+		priceRepo: NewPriceRepository(),
+	}
+}
+
+// parseInterval 校验K线周期，为空时为日线
+func parseInterval(interval string) (string, error) {
+	if interval == "" {
+		return IntervalDaily, nil
+	}
+	if !validIntervals[interval] {
+		return "", fmt.Errorf("不支持的K线周期: %s", interval)
+	}
+	return interval, nil
+}
+
+// ImportCSV 从用户上传目录中的 CSV 文件导入行情，格式错误的行跳过并在结果中返回
+func (s *priceService) ImportCSV(userID string, req *PriceImportRequest) (*PriceImportResult, error) {
+	interval, err := parseInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := handler.ResolveUpload(userID, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开上传文件失败: %w", err)
+	}
+	defer file.Close()
+
+	source := req.Source
+	if source == "" {
+		source = "csv"
+	}
+	prices, total, errs, err := parseCSV(file, req.StockCode, interval, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(prices) > 0 {
+		if err := s.priceRepo.Upsert(userID, prices); err != nil {
+			utils.LogError("导入行情失败，文件: %s, 错误: %v", req.FileName, err)
+			return nil, fmt.Errorf("导入行情失败: %w", err)
+		}
+	}
+
+	result := &PriceImportResult{
+		Total:    total,
+		Imported: len(prices),
+		Skipped:  total - len(prices),
+		Codes:    []string{},
+		Errors:   errs,
+	}
+	if len(result.Errors) > maxImportErrors {
+		result.Errors = result.Errors[:maxImportErrors]
+	}
+	if result.Errors == nil {
+		result.Errors = []ImportError{}
+	}
+	seen := map[string]bool{}
+	for _, p := range prices {
+		if !seen[p.StockCode] {
+			seen[p.StockCode] = true
+			result.Codes = append(result.Codes, p.StockCode)
+		}
+	}
+	sort.Strings(result.Codes)
+
+	utils.LogInfo("行情导入完成，文件: %s, 周期: %s, 导入 %d 行, 跳过 %d 行", req.FileName, interval, result.Imported, result.Skipped)
+	return result, nil
+}
+
+// ListPrices 按股票代码和日期范围查询用户可见的K线
+func (s *priceService) ListPrices(userID string, req *PriceListRequest) (*PriceListResponse, error) {
+	interval, err := parseInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分钟线的交易时间带时分，结束日期取当天最后一刻
+	endTime := req.EndDate
+	if len(endTime) == len("2006-01-02") {
+		endTime += " 23:59:59"
+	}
+	prices, err := s.priceRepo.List(userID, req.StockCode, interval, req.StartDate, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("查询行情失败: %w", err)
+	}
+
+	return &PriceListResponse{
+		Items:     prices,
+		Total:     len(prices),
+		StockCode: req.StockCode,
+		Interval:  interval,
+	}, nil
+}
+
+// GetLatest 获取用户可见的最新一根K线，userID 为空时只查公共K线
+func (s *priceService) GetLatest(userID, stockCode, interval string) (*Price, error) {
+	interval, err := parseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	return s.priceRepo.GetLatest(userID, stockCode, interval)
+}
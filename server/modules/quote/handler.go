@@ -0,0 +1,35 @@
+package quote
+
+import (
+	"context"
+	"time"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterQuoteRoutes 注册行情报价路由
+func RegisterQuoteRoutes(r *gin.RouterGroup) {
+	quoteService := NewQuoteService()
+
+	r.GET("/quotes", func(c *gin.Context) {
+		codes := ParseCodes(c.Query("codes"))
+		if len(codes) == 0 {
+			handler.Error(c, handler.CodeInvalid, "股票代码不能为空")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		response, err := quoteService.GetQuotes(ctx, middleware.GetCurrentUserID(c), codes)
+		if err != nil {
+			handler.Error(c, handler.CodeError, err.Error())
+			return
+		}
+
+		handler.Success(c, response)
+	})
+}
@@ -0,0 +1,18 @@
+package quote
+
+import "server/market"
+
+// maxQuoteCodes 单次最多查询的代码数量
+const maxQuoteCodes = 50
+
+// QuoteError 获取失败的代码
+type QuoteError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// QuoteListResponse 行情查询响应，按请求中的代码顺序返回
+type QuoteListResponse struct {
+	Items  []market.Quote `json:"list"`
+	Errors []QuoteError   `json:"errors"`
+}
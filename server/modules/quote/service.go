@@ -0,0 +1,150 @@
+package quote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"server/config"
+	"server/market"
+	"server/modules/price"
+	"server/modules/stock"
+	"server/utils"
+)
+
+// QuoteService 行情报价服务接口
+type QuoteService interface {
+	GetQuotes(ctx context.Context, userID string, codes []string) (*QuoteListResponse, error)
+}
+
+// quoteService 行情报价服务实现
+type quoteService struct {
+	stockRepo *stock.StockRepository
+	cache     *market.QuoteCache
+}
+
+var (
+	setupOnce sync.Once
+	cache     *market.QuoteCache
+)
+
+// setupProviders 按配置为各地区注册行情数据源
+func setupProviders() {
+	cfg := config.Load()
+	if cfg.QuoteProvider != "mock" {
+		utils.LogWarning("不支持的行情数据源 %s，使用模拟数据源", cfg.QuoteProvider)
+	}
+
+	// 模拟行情以最近一根公共日K线的收盘价为昨收，行情缓存由所有用户共用，不使用用户自己导入的K线
+	priceService := price.NewPriceService()
+	latestClose := func(code string) (float64, bool) {
+		p, err := priceService.GetLatest("", code, price.IntervalDaily)
+		if err != nil {
+			return 0, false
+		}
+		return p.Close, true
+	}
+	for _, region := range market.Regions {
+		market.Register(region, market.NewMockProvider(region, latestClose))
+	}
+	cache = market.NewQuoteCache(cfg.QuoteCacheTTL)
+}
+
+// NewQuoteService 创建行情报价服务
+func NewQuoteService() QuoteService {
+	setupOnce.Do(setupProviders)
+	return &quoteService{
+		stockRepo: stock.NewStockRepository(),
+		cache:     cache,
+	}
+}
+
+// ParseCodes 解析逗号分隔的代码列表并去重
+func ParseCodes(raw string) []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, code := range strings.Split(raw, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// GetQuotes 获取行情，地区取自用户股票列表，列表中没有的代码按代码格式推断
+func (s *quoteService) GetQuotes(ctx context.Context, userID string, codes []string) (*QuoteListResponse, error) {
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+	if len(codes) > maxQuoteCodes {
+		return nil, fmt.Errorf("单次最多查询 %d 个代码", maxQuoteCodes)
+	}
+
+	quotes := map[string]market.Quote{}
+	names := map[string]string{}
+	regionOf := map[string]string{}
+	pending := map[string][]string{}
+	for _, code := range codes {
+		region := market.InferRegion(code)
+		if st, err := s.stockRepo.GetByCode(userID, code); err == nil {
+			names[code] = st.Name
+			if st.Region != "" {
+				region = st.Region
+			}
+		}
+		regionOf[code] = region
+
+		if q, ok := s.cache.Get(region, code); ok {
+			quotes[code] = q
+			continue
+		}
+		pending[region] = append(pending[region], code)
+	}
+
+	failed := map[string]string{}
+	for region, regionCodes := range pending {
+		provider, err := market.ProviderFor(region)
+		if err != nil {
+			for _, code := range regionCodes {
+				failed[code] = err.Error()
+			}
+			continue
+		}
+
+		result, err := provider.GetQuotes(ctx, regionCodes)
+		if err != nil {
+			utils.LogWarning("获取行情失败，数据源: %s, 地区: %s, 错误: %v", provider.Name(), region, err)
+			for _, code := range regionCodes {
+				failed[code] = "获取行情失败: " + err.Error()
+			}
+			continue
+		}
+		for _, code := range regionCodes {
+			q, ok := result[code]
+			if !ok {
+				failed[code] = "没有该代码的行情"
+				continue
+			}
+			s.cache.Set(region, q)
+			quotes[code] = q
+		}
+	}
+
+	resp := &QuoteListResponse{Items: []market.Quote{}, Errors: []QuoteError{}}
+	for _, code := range codes {
+		if msg, ok := failed[code]; ok {
+			resp.Errors = append(resp.Errors, QuoteError{Code: code, Message: msg})
+			continue
+		}
+		q := quotes[code]
+		if q.Name == "" {
+			q.Name = names[code]
+		}
+		resp.Items = append(resp.Items, q)
+	}
+	return resp, nil
+}
@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"server/handler"
+	"server/middleware"
+	"server/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,7 +23,7 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			review, err := reviewService.CreateReview(&req)
+			review, err := reviewService.CreateReview(middleware.GetCurrentUserID(c), &req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -50,7 +52,7 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 				}
 			}
 
-			response, err := reviewService.ListReviews(req)
+			response, err := reviewService.ListReviews(middleware.GetCurrentUserID(c), req)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -59,6 +61,20 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 			handler.Success(c, response)
 		})
 
+		g.GET("/export", func(c *gin.Context) {
+			req := &ReviewListRequest{
+				Keyword:   c.Query("keyword"),
+				Period:    c.Query("period"),
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+			}
+
+			userID := middleware.GetCurrentUserID(c)
+			handler.Export(c, "reviews", "交易复盘", func(w utils.RowWriter) error {
+				return reviewService.ExportReviews(userID, req, w)
+			})
+		})
+
 		g.GET("/getDetail/:id", func(c *gin.Context) {
 			id := c.Param("id")
 			if id == "" {
@@ -66,7 +82,7 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			review, err := reviewService.GetReview(id)
+			review, err := reviewService.GetReview(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeNotFound, err.Error())
 				return
@@ -88,7 +104,39 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			review, err := reviewService.UpdateReview(id, &req)
+			review, err := reviewService.UpdateReview(middleware.GetCurrentUserID(c), id, &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, review)
+		})
+
+		g.GET("/stats", func(c *gin.Context) {
+			var req ReviewStatsRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			stats, err := reviewService.CalculateStats(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, stats)
+		})
+
+		g.POST("/recalculate/:id", func(c *gin.Context) {
+			id := c.Param("id")
+			if id == "" {
+				handler.Error(c, handler.CodeInvalid, "复盘ID不能为空")
+				return
+			}
+
+			review, err := reviewService.RecalculateReview(middleware.GetCurrentUserID(c), id, c.Query("method"))
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -104,7 +152,7 @@ func RegisterReviewRoutes(r *gin.RouterGroup) {
 				return
 			}
 
-			err := reviewService.DeleteReview(id)
+			err := reviewService.DeleteReview(middleware.GetCurrentUserID(c), id)
 			if err != nil {
 				handler.Error(c, handler.CodeError, err.Error())
 				return
@@ -5,6 +5,7 @@ import "time"
 // Review 交易复盘模型
 type Review struct {
 	ID           string    `json:"id" db:"id"`
+	UserID       string    `json:"userId" db:"user_id"`
 	Period       string    `json:"period" db:"period"`
 	ReviewDate   string    `json:"reviewDate" db:"review_date"`
 	Title        string    `json:"title" db:"title"`
@@ -19,7 +20,7 @@ type Review struct {
 
 // ReviewCreateRequest 创建复盘请求
 type ReviewCreateRequest struct {
-	Period       string  `json:"period" binding:"required"`
+	Period       string  `json:"period" binding:"required,oneof=daily weekly monthly"`
 	ReviewDate   string  `json:"reviewDate" binding:"required"`
 	Title        string  `json:"title" binding:"required"`
 	BuyCount     int     `json:"buyCount"`
@@ -27,11 +28,12 @@ type ReviewCreateRequest struct {
 	TotalProfit  float64 `json:"totalProfit"`
 	Summary      string  `json:"summary" binding:"required"`
 	Improvements string  `json:"improvements"`
+	AutoStats    bool    `json:"autoStats"` // 为 true 时根据交易日志计算买卖次数和盈亏，忽略手工填写的值
 }
 
 // ReviewUpdateRequest 更新复盘请求
 type ReviewUpdateRequest struct {
-	Period       *string  `json:"period,omitempty"`
+	Period       *string  `json:"period,omitempty" binding:"omitempty,oneof=daily weekly monthly"`
 	ReviewDate   *string  `json:"reviewDate,omitempty"`
 	Title        *string  `json:"title,omitempty"`
 	BuyCount     *int     `json:"buyCount,omitempty"`
@@ -41,6 +43,32 @@ type ReviewUpdateRequest struct {
 	Improvements *string  `json:"improvements,omitempty"`
 }
 
+// ReviewStatsRequest 复盘统计请求
+type ReviewStatsRequest struct {
+	Period     string `form:"period" binding:"required,oneof=daily weekly monthly"`
+	ReviewDate string `form:"reviewDate" binding:"required"`
+	Method     string `form:"method"` // 成本计算方法，为空时使用配置默认值
+}
+
+// ReviewStats 根据交易日志计算的复盘统计
+type ReviewStats struct {
+	Period      string  `json:"period"`
+	ReviewDate  string  `json:"reviewDate"`
+	StartDate   string  `json:"startDate"`
+	EndDate     string  `json:"endDate"`
+	Method      string  `json:"method"`
+	BuyCount    int     `json:"buyCount"`
+	SellCount   int     `json:"sellCount"`
+	TotalProfit float64 `json:"totalProfit"` // 已实现盈亏，已扣除交易费用
+	TotalFees   float64 `json:"totalFees"`   // 期间内成交的交易费用
+
+	// 本位币换算，盈亏按平仓日汇率、费用按成交日汇率，复盘记录的总盈亏取本位币金额
+	BaseCurrency    string   `json:"baseCurrency"`
+	BaseTotalProfit float64  `json:"baseTotalProfit"`
+	BaseTotalFees   float64  `json:"baseTotalFees"`
+	MissingRates    []string `json:"missingRates"`
+}
+
 // ReviewListRequest 复盘列表请求
 type ReviewListRequest struct {
 	Keyword   string `form:"keyword"`
@@ -0,0 +1,168 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"server/modules/log"
+	"server/modules/notification"
+	"server/storage"
+	"server/utils"
+)
+
+// reminderCheckInterval 复盘提醒的检查间隔
+const reminderCheckInterval = time.Minute
+
+// Reminder 每天在设定时间后提醒当天有交易但未写日复盘的用户
+type Reminder struct {
+	at                  time.Duration // 当天的提醒时间，距零点的时长
+	enabled             bool
+	notificationService notification.NotificationService
+	logService          log.LogService
+	date                string          // reminded 对应的日期
+	reminded            map[string]bool // 当天已提醒的用户
+	cancel              context.CancelFunc
+	wg                  sync.WaitGroup
+}
+
+// NewReminder 创建复盘提醒，at 为 HH:MM 格式的提醒时间，为空或格式错误时不启动
+func NewReminder(at string) *Reminder {
+	r := &Reminder{
+		notificationService: notification.NewNotificationService(),
+		logService:          log.NewLogService(),
+	}
+	if at == "" {
+		return r
+	}
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		utils.LogWarning("复盘提醒时间格式错误，应为 HH:MM: %s", at)
+		return r
+	}
+	r.at = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	r.enabled = true
+	return r
+}
+
+// Start 在后台每分钟检查一次是否到达提醒时间
+func (r *Reminder) Start() {
+	if !r.enabled {
+		utils.LogInfo("复盘提醒未启用")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(reminderCheckInterval)
+		defer ticker.Stop()
+		for {
+			r.runOnce(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	utils.LogInfo("复盘提醒已启动，提醒时间: %02d:%02d", int(r.at.Hours()), int(r.at.Minutes())%60)
+}
+
+// Stop 停止复盘提醒
+func (r *Reminder) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+	utils.LogInfo("复盘提醒已停止")
+}
+
+// runOnce 到达提醒时间后为当天未提醒过的用户写入通知，之后订阅或交易的用户在下次检查时提醒
+func (r *Reminder) runOnce(ctx context.Context, now time.Time) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			utils.LogError("复盘提醒异常: %v", rec)
+		}
+	}()
+
+	today := now.Format("2006-01-02")
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if now.Before(midnight.Add(r.at)) {
+		return
+	}
+	if r.date != today {
+		r.date = today
+		r.reminded = map[string]bool{}
+	}
+
+	count, err := r.remind(ctx, today)
+	if err != nil {
+		utils.LogError("复盘提醒失败: %v", err)
+		return
+	}
+	if count > 0 {
+		utils.LogInfo("复盘提醒完成，提醒用户 %d 个", count)
+	}
+}
+
+// remind 提醒订阅了复盘提醒、当天有交易且没有当天日复盘的用户，返回提醒的用户数
+// 通知以日期去重，服务重启后重复执行不会重复提醒
+func (r *Reminder) remind(ctx context.Context, today string) (int, error) {
+	userIDs, err := r.notificationService.Subscribers(notification.EventReviewReminder)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		if r.reminded[userID] {
+			continue
+		}
+		logs, err := r.logService.ListAllLogs(userID, &log.LogListRequest{
+			Status:    log.StatusCompleted,
+			StartDate: today,
+			EndDate:   today + " 23:59:59",
+		})
+		if err != nil {
+			return count, fmt.Errorf("获取交易日志失败: %w", err)
+		}
+		if len(logs) == 0 {
+			continue
+		}
+		reviewed, err := hasDailyReview(userID, today)
+		if err != nil {
+			return count, fmt.Errorf("获取复盘失败: %w", err)
+		}
+		if reviewed {
+			continue
+		}
+
+		msg := &notification.Message{
+			Event:     notification.EventReviewReminder,
+			Title:     "今日复盘提醒",
+			Content:   fmt.Sprintf("今天（%s）有 %d 笔交易，还没有写日复盘", today, len(logs)),
+			CreatedAt: time.Now(),
+		}
+		if err := r.notificationService.Notify(userID, notification.EventReviewReminder, "review_reminder:"+today, msg); err != nil {
+			return count, err
+		}
+		r.reminded[userID] = true
+		count++
+	}
+	return count, nil
+}
+
+// hasDailyReview 判断用户是否已有指定日期的日复盘
+func hasDailyReview(userID, date string) (bool, error) {
+	var count int
+	err := storage.GetDB().QueryRow(`SELECT COUNT(*) FROM reviews
+		WHERE user_id = ? AND period = ? AND review_date = ?`, userID, PeriodDaily, date).Scan(&count)
+	return count > 0, err
+}
@@ -4,17 +4,21 @@ import (
 	"database/sql"
 	"fmt"
 	"server/storage"
+	"server/utils"
 	"strings"
 	"time"
 )
 
 // ReviewService 复盘服务接口
 type ReviewService interface {
-	CreateReview(req *ReviewCreateRequest) (*Review, error)
-	GetReview(id string) (*Review, error)
-	ListReviews(req *ReviewListRequest) (*ReviewListResponse, error)
-	UpdateReview(id string, req *ReviewUpdateRequest) (*Review, error)
-	DeleteReview(id string) error
+	CreateReview(userID string, req *ReviewCreateRequest) (*Review, error)
+	GetReview(userID, id string) (*Review, error)
+	ListReviews(userID string, req *ReviewListRequest) (*ReviewListResponse, error)
+	ExportReviews(userID string, req *ReviewListRequest, w utils.RowWriter) error
+	UpdateReview(userID, id string, req *ReviewUpdateRequest) (*Review, error)
+	DeleteReview(userID, id string) error
+	CalculateStats(userID string, req *ReviewStatsRequest) (*ReviewStats, error)
+	RecalculateReview(userID, id string, method string) (*Review, error)
 }
 
 // reviewService 复盘服务实现
@@ -26,11 +30,23 @@ func NewReviewService() ReviewService {
 }
 
 // CreateReview 创建复盘
-func (s *reviewService) CreateReview(req *ReviewCreateRequest) (*Review, error) {
+func (s *reviewService) CreateReview(userID string, req *ReviewCreateRequest) (*Review, error) {
 	id := fmt.Sprintf("%d", time.Now().UnixNano())
 
+	// 根据交易日志自动填充统计字段
+	if req.AutoStats {
+		stats, err := calculateStats(userID, &ReviewStatsRequest{Period: req.Period, ReviewDate: req.ReviewDate})
+		if err != nil {
+			return nil, err
+		}
+		req.BuyCount = stats.BuyCount
+		req.SellCount = stats.SellCount
+		req.TotalProfit = stats.BaseTotalProfit
+	}
+
 	review := &Review{
 		ID:           id,
+		UserID:       userID,
 		Period:       req.Period,
 		ReviewDate:   req.ReviewDate,
 		Title:        req.Title,
@@ -44,12 +60,12 @@ func (s *reviewService) CreateReview(req *ReviewCreateRequest) (*Review, error)
 	}
 
 	query := `INSERT INTO reviews (
-		id, period, review_date, title, buy_count, sell_count,
+		id, user_id, period, review_date, title, buy_count, sell_count,
 		total_profit, summary, improvements, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := storage.GetDB().Exec(query,
-		review.ID, review.Period, review.ReviewDate, review.Title,
+		review.ID, review.UserID, review.Period, review.ReviewDate, review.Title,
 		review.BuyCount, review.SellCount, review.TotalProfit, review.Summary,
 		review.Improvements, review.CreatedAt, review.UpdatedAt,
 	)
@@ -62,14 +78,14 @@ func (s *reviewService) CreateReview(req *ReviewCreateRequest) (*Review, error)
 }
 
 // GetReview 获取复盘详情
-func (s *reviewService) GetReview(id string) (*Review, error) {
-	query := `SELECT id, period, review_date, title, buy_count, sell_count,
+func (s *reviewService) GetReview(userID, id string) (*Review, error) {
+	query := `SELECT id, user_id, period, review_date, title, buy_count, sell_count,
 		total_profit, summary, improvements, created_at, updated_at
-		FROM reviews WHERE id = ?`
+		FROM reviews WHERE id = ? AND user_id = ?`
 
 	review := &Review{}
-	err := storage.GetDB().QueryRow(query, id).Scan(
-		&review.ID, &review.Period, &review.ReviewDate, &review.Title,
+	err := storage.GetDB().QueryRow(query, id, userID).Scan(
+		&review.ID, &review.UserID, &review.Period, &review.ReviewDate, &review.Title,
 		&review.BuyCount, &review.SellCount, &review.TotalProfit, &review.Summary,
 		&review.Improvements, &review.CreatedAt, &review.UpdatedAt,
 	)
@@ -84,11 +100,10 @@ func (s *reviewService) GetReview(id string) (*Review, error) {
 	return review, nil
 }
 
-// ListReviews 获取复盘列表
-func (s *reviewService) ListReviews(req *ReviewListRequest) (*ReviewListResponse, error) {
-	// 构建查询条件
-	where := []string{"1=1"}
-	args := []interface{}{}
+// buildReviewFilter 根据列表请求构建查询条件
+func buildReviewFilter(userID string, req *ReviewListRequest) (string, []interface{}) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
 
 	if req.Keyword != "" {
 		where = append(where, "(title LIKE ? OR summary LIKE ?)")
@@ -111,7 +126,27 @@ func (s *reviewService) ListReviews(req *ReviewListRequest) (*ReviewListResponse
 		args = append(args, req.EndDate)
 	}
 
-	whereClause := strings.Join(where, " AND ")
+	return strings.Join(where, " AND "), args
+}
+
+// scanReview 扫描一行复盘数据
+func scanReview(rows *sql.Rows) (*Review, error) {
+	review := &Review{}
+	err := rows.Scan(
+		&review.ID, &review.UserID, &review.Period, &review.ReviewDate, &review.Title,
+		&review.BuyCount, &review.SellCount, &review.TotalProfit, &review.Summary,
+		&review.Improvements, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("扫描复盘数据失败: %w", err)
+	}
+	return review, nil
+}
+
+// ListReviews 获取复盘列表
+func (s *reviewService) ListReviews(userID string, req *ReviewListRequest) (*ReviewListResponse, error) {
+	// 构建查询条件
+	whereClause, args := buildReviewFilter(userID, req)
 
 	// 获取总数
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM reviews WHERE %s", whereClause)
@@ -133,7 +168,7 @@ func (s *reviewService) ListReviews(req *ReviewListRequest) (*ReviewListResponse
 	offset := (page - 1) * pageSize
 
 	// 获取数据
-	query := fmt.Sprintf(`SELECT id, period, review_date, title, buy_count, sell_count,
+	query := fmt.Sprintf(`SELECT id, user_id, period, review_date, title, buy_count, sell_count,
 		total_profit, summary, improvements, created_at, updated_at
 		FROM reviews WHERE %s ORDER BY review_date DESC LIMIT ? OFFSET ?`, whereClause)
 
@@ -147,14 +182,9 @@ func (s *reviewService) ListReviews(req *ReviewListRequest) (*ReviewListResponse
 
 	var reviews []Review
 	for rows.Next() {
-		review := &Review{}
-		err = rows.Scan(
-			&review.ID, &review.Period, &review.ReviewDate, &review.Title,
-			&review.BuyCount, &review.SellCount, &review.TotalProfit, &review.Summary,
-			&review.Improvements, &review.CreatedAt, &review.UpdatedAt,
-		)
+		review, err := scanReview(rows)
 		if err != nil {
-			return nil, fmt.Errorf("扫描复盘数据失败: %w", err)
+			return nil, err
 		}
 		reviews = append(reviews, *review)
 	}
@@ -172,10 +202,49 @@ func (s *reviewService) ListReviews(req *ReviewListRequest) (*ReviewListResponse
 	}, nil
 }
 
+// ExportReviews 按列表筛选条件导出全部复盘，逐行写出
+func (s *reviewService) ExportReviews(userID string, req *ReviewListRequest, w utils.RowWriter) error {
+	whereClause, args := buildReviewFilter(userID, req)
+	query := fmt.Sprintf(`SELECT id, user_id, period, review_date, title, buy_count, sell_count,
+		total_profit, summary, improvements, created_at, updated_at
+		FROM reviews WHERE %s ORDER BY review_date DESC`, whereClause)
+
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("查询复盘列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	err = w.WriteRow([]interface{}{"复盘周期", "复盘日期", "标题", "买入次数", "卖出次数", "总盈亏", "总结", "改进", "创建时间"})
+	if err != nil {
+		return err
+	}
+	count := 0
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			return err
+		}
+		err = w.WriteRow([]interface{}{
+			r.Period, r.ReviewDate, r.Title, r.BuyCount, r.SellCount, r.TotalProfit, r.Summary, r.Improvements, r.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历复盘数据失败: %w", err)
+	}
+
+	utils.LogInfo("导出复盘完成，共 %d 条", count)
+	return nil
+}
+
 // UpdateReview 更新复盘
-func (s *reviewService) UpdateReview(id string, req *ReviewUpdateRequest) (*Review, error) {
+func (s *reviewService) UpdateReview(userID, id string, req *ReviewUpdateRequest) (*Review, error) {
 	// 检查复盘是否存在
-	_, err := s.GetReview(id)
+	_, err := s.GetReview(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("复盘不存在: %w", err)
 	}
@@ -226,28 +295,28 @@ func (s *reviewService) UpdateReview(id string, req *ReviewUpdateRequest) (*Revi
 	args = append(args, time.Now())
 
 	// 添加WHERE条件
-	args = append(args, id)
+	args = append(args, id, userID)
 
-	query := fmt.Sprintf("UPDATE reviews SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	query := fmt.Sprintf("UPDATE reviews SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
 	_, err = storage.GetDB().Exec(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("更新复盘失败: %w", err)
 	}
 
 	// 返回更新后的复盘
-	return s.GetReview(id)
+	return s.GetReview(userID, id)
 }
 
 // DeleteReview 删除复盘
-func (s *reviewService) DeleteReview(id string) error {
+func (s *reviewService) DeleteReview(userID, id string) error {
 	// 检查复盘是否存在
-	_, err := s.GetReview(id)
+	_, err := s.GetReview(userID, id)
 	if err != nil {
 		return fmt.Errorf("复盘不存在: %w", err)
 	}
 
-	query := "DELETE FROM reviews WHERE id = ?"
-	result, err := storage.GetDB().Exec(query, id)
+	query := "DELETE FROM reviews WHERE id = ? AND user_id = ?"
+	result, err := storage.GetDB().Exec(query, id, userID)
 	if err != nil {
 		return fmt.Errorf("删除复盘失败: %w", err)
 	}
@@ -263,3 +332,31 @@ func (s *reviewService) DeleteReview(id string) error {
 
 	return nil
 }
+
+// CalculateStats 根据交易日志计算复盘周期内的统计数据，用于创建复盘时预填
+func (s *reviewService) CalculateStats(userID string, req *ReviewStatsRequest) (*ReviewStats, error) {
+	return calculateStats(userID, req)
+}
+
+// RecalculateReview 交易日志修改后，按复盘的周期和日期重新计算统计字段
+func (s *reviewService) RecalculateReview(userID, id string, method string) (*Review, error) {
+	review, err := s.GetReview(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := calculateStats(userID, &ReviewStatsRequest{
+		Period:     review.Period,
+		ReviewDate: review.ReviewDate,
+		Method:     method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UpdateReview(userID, id, &ReviewUpdateRequest{
+		BuyCount:    &stats.BuyCount,
+		SellCount:   &stats.SellCount,
+		TotalProfit: &stats.BaseTotalProfit,
+	})
+}
@@ -0,0 +1,124 @@
+package review
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"server/modules/log"
+	"server/modules/position"
+)
+
+// 复盘周期
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// periodRange 根据复盘周期和复盘日期计算统计区间（闭区间，格式 YYYY-MM-DD）
+// 日复盘为当天，周复盘为所在周的周一至周日，月复盘为整月
+func periodRange(period, reviewDate string) (string, string, error) {
+	switch period {
+	case PeriodDaily:
+		d, err := time.Parse("2006-01-02", reviewDate)
+		if err != nil {
+			return "", "", fmt.Errorf("复盘日期格式错误，应为 YYYY-MM-DD: %s", reviewDate)
+		}
+		return d.Format("2006-01-02"), d.Format("2006-01-02"), nil
+	case PeriodWeekly:
+		d, err := time.Parse("2006-01-02", reviewDate)
+		if err != nil {
+			return "", "", fmt.Errorf("复盘日期格式错误，应为 YYYY-MM-DD: %s", reviewDate)
+		}
+		offset := (int(d.Weekday()) + 6) % 7 // 周一为一周的第一天
+		start := d.AddDate(0, 0, -offset)
+		return start.Format("2006-01-02"), start.AddDate(0, 0, 6).Format("2006-01-02"), nil
+	case PeriodMonthly:
+		d, err := time.Parse("2006-01", reviewDate)
+		if err != nil {
+			// 兼容传入具体日期
+			d, err = time.Parse("2006-01-02", reviewDate)
+			if err != nil {
+				return "", "", fmt.Errorf("复盘日期格式错误，应为 YYYY-MM: %s", reviewDate)
+			}
+		}
+		start := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01-02"), start.AddDate(0, 1, -1).Format("2006-01-02"), nil
+	}
+	return "", "", fmt.Errorf("不支持的复盘周期: %s", period)
+}
+
+// calculateStats 汇总区间内的交易日志，已实现盈亏按平仓时间归属到区间
+func calculateStats(userID string, req *ReviewStatsRequest) (*ReviewStats, error) {
+	startDate, endDate, err := periodRange(req.Period, req.ReviewDate)
+	if err != nil {
+		return nil, err
+	}
+	// 交易时间可能带时分秒，结束时间取当天最后一刻
+	start := startDate
+	end := endDate + " 23:59:59"
+
+	logService := log.NewLogService()
+	logs, err := logService.ListAllLogs(userID, &log.LogListRequest{
+		Status:    log.StatusCompleted,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("统计交易日志失败: %w", err)
+	}
+
+	stats := &ReviewStats{
+		Period:     req.Period,
+		ReviewDate: req.ReviewDate,
+		StartDate:  startDate,
+		EndDate:    endDate,
+	}
+	logService.ConvertLogs(userID, logs)
+	for _, l := range logs {
+		switch l.Type {
+		case log.TypeBuy:
+			stats.BuyCount++
+		case log.TypeSell:
+			stats.SellCount++
+		}
+		stats.TotalFees += l.Fees
+		stats.BaseTotalFees += l.BaseFees
+	}
+	stats.TotalFees = math.Round(stats.TotalFees*100) / 100
+	stats.BaseTotalFees = math.Round(stats.BaseTotalFees*100) / 100
+
+	realized, err := position.NewPositionService().ListRealizations(userID, &position.RealizationListRequest{
+		StartDate: start,
+		EndDate:   end,
+		Method:    req.Method,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("计算已实现盈亏失败: %w", err)
+	}
+	stats.TotalProfit = realized.TotalRealizedPnL
+	stats.Method = realized.Method
+	stats.BaseCurrency = realized.BaseCurrency
+	stats.BaseTotalProfit = realized.BaseTotalRealizedPnL
+	stats.MissingRates = realized.MissingRates
+	for _, l := range logs {
+		if l.FxRate == 0 && !containsString(stats.MissingRates, l.Currency) {
+			stats.MissingRates = append(stats.MissingRates, l.Currency)
+		}
+	}
+	sort.Strings(stats.MissingRates)
+
+	return stats, nil
+}
+
+// containsString 判断切片是否包含指定字符串
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package risk
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+	"strings"
+)
+
+// BreachRepository 违规记录数据访问层
+type BreachRepository struct{}
+
+// NewBreachRepository 创建违规记录仓库
+func NewBreachRepository() *BreachRepository {
+	return &BreachRepository{}
+}
+
+// Create 写入违规记录
+func (r *BreachRepository) Create(b *Breach) error {
+	query := `INSERT INTO risk_breaches (
+		id, user_id, log_id, stock_code, type, trading_time, price, quantity,
+		rule, limit_value, actual_value, action, message, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := storage.GetDB().Exec(query,
+		b.ID, b.UserID, sql.NullString{String: b.LogID, Valid: b.LogID != ""}, b.StockCode, b.Type, b.TradingTime,
+		b.Price, b.Quantity, b.Rule, b.LimitValue, b.ActualValue, b.Action, b.Message, b.CreatedAt,
+	)
+	return err
+}
+
+// List 分页查询违规记录，按记录时间倒序
+func (r *BreachRepository) List(userID string, req *BreachListRequest, page, pageSize int) ([]Breach, int, error) {
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
+
+	if req.StartDate != "" {
+		where = append(where, "trading_time >= ?")
+		args = append(args, req.StartDate)
+	}
+	if req.EndDate != "" {
+		where = append(where, "trading_time <= ?")
+		args = append(args, req.EndDate)
+	}
+	if req.Rule != "" {
+		where = append(where, "rule = ?")
+		args = append(args, req.Rule)
+	}
+	if req.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, req.Action)
+	}
+	if req.StockCode != "" {
+		where = append(where, "stock_code = ?")
+		args = append(args, req.StockCode)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := storage.GetDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM risk_breaches WHERE %s", whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, user_id, log_id, stock_code, type, trading_time, price, quantity,
+		rule, limit_value, actual_value, action, message, created_at
+		FROM risk_breaches WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	breaches := []Breach{}
+	for rows.Next() {
+		var b Breach
+		var logID, stockCode, tradeType, tradingTime, message sql.NullString
+		if err := rows.Scan(
+			&b.ID, &b.UserID, &logID, &stockCode, &tradeType, &tradingTime, &b.Price, &b.Quantity,
+			&b.Rule, &b.LimitValue, &b.ActualValue, &b.Action, &message, &b.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		b.LogID = logID.String
+		b.StockCode = stockCode.String
+		b.Type = tradeType.String
+		b.TradingTime = tradingTime.String
+		b.Message = message.String
+		breaches = append(breaches, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return breaches, total, nil
+}
@@ -0,0 +1,46 @@
+package risk
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRiskRoutes 注册风险限制路由
+func RegisterRiskRoutes(r *gin.RouterGroup) {
+	riskService := NewRiskService()
+
+	g := r.Group("/risk")
+	{
+		g.GET("/breaches", func(c *gin.Context) {
+			req := &BreachListRequest{
+				StartDate: c.Query("startDate"),
+				EndDate:   c.Query("endDate"),
+				Rule:      c.Query("rule"),
+				Action:    c.Query("action"),
+				StockCode: c.Query("stockCode"),
+			}
+			if pageStr := c.Query("page"); pageStr != "" {
+				if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+					req.Page = page
+				}
+			}
+			if pageSizeStr := c.Query("pageSize"); pageSizeStr != "" {
+				if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
+					req.PageSize = pageSize
+				}
+			}
+
+			response, err := riskService.ListBreaches(middleware.GetCurrentUserID(c), req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, response)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"server/modules/log"
+	"server/modules/position"
+	"server/modules/user"
+)
+
+// violation 一条被违反的限制
+type violation struct {
+	Rule     string
+	Limit    float64
+	Actual   float64
+	Blocking bool // 增加风险敞口的交易才能被拒绝，减仓始终只警告
+	Message  string
+}
+
+// findPosition 按股票代码查找持仓
+func findPosition(positions []*position.Position, stockCode string) *position.Position {
+	for _, p := range positions {
+		if p.StockCode == stockCode {
+			return p
+		}
+	}
+	return nil
+}
+
+// evaluateLimits 比较新日志加入前后的持仓，返回被违反的限制
+// history 为用户已有日志，需按交易时间升序排列；rates 为各股票按新日志交易日换算到本位币的汇率，缺失时按 1 计
+func evaluateLimits(settings *user.UserSettings, history []log.Log, l *log.Log, method string, rates map[string]float64) []violation {
+	toBase := func(stockCode string, amount float64) float64 {
+		if rate, ok := rates[stockCode]; ok && rate > 0 {
+			return amount * rate
+		}
+		return amount
+	}
+
+	after := append(append([]log.Log{}, history...), *l)
+	sort.SliceStable(after, func(i, j int) bool {
+		return after[i].TradingTime < after[j].TradingTime
+	})
+
+	beforePositions := position.Replay(history, method)
+	afterPositions := position.Replay(after, method)
+
+	var beforeQty int
+	if p := findPosition(beforePositions, l.StockCode); p != nil {
+		beforeQty = p.Quantity
+	}
+	current := findPosition(afterPositions, l.StockCode)
+	increasing := abs(current.Quantity) > abs(beforeQty)
+
+	violations := []violation{}
+	add := func(rule string, limit, actual float64, blocking bool, format string) {
+		violations = append(violations, violation{
+			Rule:     rule,
+			Limit:    limit,
+			Actual:   round2(actual),
+			Blocking: blocking,
+			Message:  fmt.Sprintf(format, actual, limit),
+		})
+	}
+
+	if value := toBase(current.StockCode, current.MarketValue); settings.MaxPositionValue > 0 && increasing && value > settings.MaxPositionValue {
+		add(RuleMaxPositionValue, settings.MaxPositionValue, value, true,
+			l.StockCode+" 持仓金额 %.2f 超过单只股票上限 %.2f")
+	}
+
+	if settings.MaxTotalExposure > 0 && increasing {
+		var exposure float64
+		for _, p := range afterPositions {
+			exposure += toBase(p.StockCode, p.MarketValue)
+		}
+		if exposure > settings.MaxTotalExposure {
+			add(RuleMaxTotalExposure, settings.MaxTotalExposure, exposure, true, "总持仓金额 %.2f 超过上限 %.2f")
+		}
+	}
+
+	if settings.MaxOpenPositions > 0 && beforeQty == 0 && current.Quantity != 0 {
+		var open int
+		for _, p := range afterPositions {
+			if p.Quantity != 0 {
+				open++
+			}
+		}
+		if open > settings.MaxOpenPositions {
+			add(RuleMaxOpenPositions, float64(settings.MaxOpenPositions), float64(open), true, "持仓股票数 %.0f 超过上限 %.0f")
+		}
+	}
+
+	if settings.MaxDailyLoss > 0 && len(l.TradingTime) >= 10 {
+		day := l.TradingTime[:10]
+		var realized float64
+		for _, p := range afterPositions {
+			for _, r := range p.Realizations {
+				if len(r.TradingTime) >= 10 && r.TradingTime[:10] == day {
+					realized += toBase(p.StockCode, r.RealizedPnL)
+				}
+			}
+		}
+		if -realized > settings.MaxDailyLoss {
+			add(RuleMaxDailyLoss, settings.MaxDailyLoss, -realized, increasing, day+" 已实现亏损 %.2f 超过单日上限 %.2f")
+		}
+	}
+
+	return violations
+}
+
+// abs 取整数绝对值
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// round2 保留两位小数
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,54 @@
+package risk
+
+import "time"
+
+// 风险限制规则
+const (
+	RuleMaxPositionValue = "max_position_value" // 单只股票最大持仓金额
+	RuleMaxTotalExposure = "max_total_exposure" // 最大总持仓金额
+	RuleMaxOpenPositions = "max_open_positions" // 最多同时持有的股票数
+	RuleMaxDailyLoss     = "max_daily_loss"     // 单日最大已实现亏损
+)
+
+// 违规处理结果
+const (
+	ActionRejected = "rejected" // 日志被拒绝创建
+	ActionWarned   = "warned"   // 日志已保存并标记警告
+)
+
+// Breach 风险限制违规记录
+type Breach struct {
+	ID          string    `json:"id" db:"id"`
+	UserID      string    `json:"userId" db:"user_id"`
+	LogID       string    `json:"logId" db:"log_id"` // 被拒绝的日志未保存，为空
+	StockCode   string    `json:"stockCode" db:"stock_code"`
+	Type        string    `json:"type" db:"type"`
+	TradingTime string    `json:"tradingTime" db:"trading_time"`
+	Price       float64   `json:"price" db:"price"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	Rule        string    `json:"rule" db:"rule"`
+	LimitValue  float64   `json:"limitValue" db:"limit_value"`
+	ActualValue float64   `json:"actualValue" db:"actual_value"`
+	Action      string    `json:"action" db:"action"`
+	Message     string    `json:"message" db:"message"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// BreachListRequest 违规记录列表请求
+type BreachListRequest struct {
+	StartDate string `form:"startDate"` // 按交易时间过滤
+	EndDate   string `form:"endDate"`
+	Rule      string `form:"rule"`
+	Action    string `form:"action"`
+	StockCode string `form:"stockCode"`
+	Page      int    `form:"page"`
+	PageSize  int    `form:"pageSize"`
+}
+
+// BreachListResponse 违规记录列表响应
+type BreachListResponse struct {
+	Items    []Breach `json:"list"`
+	Total    int      `json:"total"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"pageSize"`
+}
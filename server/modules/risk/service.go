@@ -0,0 +1,179 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"server/config"
+	"server/modules/log"
+	"server/modules/notification"
+	"server/modules/position"
+	"server/modules/user"
+	"server/utils"
+)
+
+// RiskService 风险限制服务接口
+type RiskService interface {
+	Check(userID string, l *log.Log) ([]string, error)
+	ListBreaches(userID string, req *BreachListRequest) (*BreachListResponse, error)
+}
+
+// riskService 风险限制服务实现
+type riskService struct {
+	breachRepo          *BreachRepository
+	logService          log.LogService
+	userService         user.UserService
+	notificationService notification.NotificationService
+}
+
+// NewRiskService 创建风险限制服务
+func NewRiskService() RiskService {
+	return &riskService{
+		breachRepo:          NewBreachRepository(),
+		logService:          log.NewLogService(),
+		userService:         user.NewUserService(),
+		notificationService: notification.NewNotificationService(),
+	}
+}
+
+// Check 在日志创建或修改前按用户设置的风险限制检查当前持仓
+// 策略为 reject 且交易增加风险敞口时返回错误，否则返回警告信息；所有违规都会记录
+func (s *riskService) Check(userID string, l *log.Log) ([]string, error) {
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.MaxPositionValue <= 0 && settings.MaxTotalExposure <= 0 &&
+		settings.MaxOpenPositions <= 0 && settings.MaxDailyLoss <= 0 {
+		return nil, nil
+	}
+
+	method, err := position.ParseMethod("", config.Load().CostBasisMethod)
+	if err != nil {
+		return nil, err
+	}
+	// 按已成交的日志计算当前持仓，新日志无论状态都按成交检查
+	all, err := s.logService.ListAllLogs(userID, &log.LogListRequest{Status: log.StatusCompleted})
+	if err != nil {
+		return nil, fmt.Errorf("检查风险限制失败: %w", err)
+	}
+	// 修改日志时排除修改前的记录，按修改后的日志检查
+	history := make([]log.Log, 0, len(all))
+	for _, item := range all {
+		if item.ID != l.ID {
+			history = append(history, item)
+		}
+	}
+
+	violations := evaluateLimits(settings, history, l, method, s.baseRates(userID, history, l))
+	if len(violations) == 0 {
+		return nil, nil
+	}
+
+	rejected := false
+	if settings.RiskLimitPolicy == user.RiskPolicyReject {
+		for _, v := range violations {
+			if v.Blocking {
+				rejected = true
+				break
+			}
+		}
+	}
+
+	action, logID := ActionWarned, l.ID
+	if rejected {
+		action, logID = ActionRejected, ""
+	}
+	messages := make([]string, 0, len(violations))
+	firstBreachID := ""
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+		breach := &Breach{
+			ID:          utils.GenerateID(),
+			UserID:      userID,
+			LogID:       logID,
+			StockCode:   l.StockCode,
+			Type:        l.Type,
+			TradingTime: l.TradingTime,
+			Price:       l.Price,
+			Quantity:    l.Quantity,
+			Rule:        v.Rule,
+			LimitValue:  v.Limit,
+			ActualValue: v.Actual,
+			Action:      action,
+			Message:     v.Message,
+			CreatedAt:   time.Now(),
+		}
+		if err := s.breachRepo.Create(breach); err != nil {
+			utils.LogError("记录风险限制违规失败，规则: %s, 错误: %v", v.Rule, err)
+		} else if firstBreachID == "" {
+			firstBreachID = breach.ID
+		}
+	}
+	utils.LogWarning("交易日志违反风险限制，股票代码: %s, 处理: %s, 详情: %s", l.StockCode, action, strings.Join(messages, "；"))
+
+	// 同一笔交易的多条违规合并为一条通知
+	if firstBreachID != "" {
+		title := "交易违反风险限制"
+		if rejected {
+			title = "交易因违反风险限制被拒绝"
+		}
+		msg := &notification.Message{
+			Event:     notification.EventRiskBreach,
+			Title:     fmt.Sprintf("%s：%s", title, l.StockCode),
+			Content:   strings.Join(messages, "；"),
+			CreatedAt: time.Now(),
+		}
+		if err := s.notificationService.Notify(userID, notification.EventRiskBreach, "risk:"+firstBreachID, msg); err != nil {
+			utils.LogWarning("发送风险限制通知失败，错误: %v", err)
+		}
+	}
+
+	if rejected {
+		return nil, fmt.Errorf("违反风险限制: %s", strings.Join(messages, "；"))
+	}
+	return messages, nil
+}
+
+// baseRates 按新日志的交易日汇率计算各股票换算到本位币的汇率，限制金额均以本位币计
+func (s *riskService) baseRates(userID string, history []log.Log, l *log.Log) map[string]float64 {
+	logs := append(append([]log.Log{}, history...), *l)
+	conv := s.logService.ConvertLogs(userID, logs)
+	rates := map[string]float64{}
+	for _, item := range logs {
+		if _, ok := rates[item.StockCode]; ok {
+			continue
+		}
+		rate, ok := conv.Rate(item.Currency, l.TradingTime)
+		if !ok {
+			utils.LogWarning("缺少 %s/%s 汇率，风险限制按原币金额计算，股票代码: %s", item.Currency, conv.Base(), item.StockCode)
+		}
+		rates[item.StockCode] = rate
+	}
+	return rates
+}
+
+// ListBreaches 获取风险限制违规记录
+func (s *riskService) ListBreaches(userID string, req *BreachListRequest) (*BreachListResponse, error) {
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	breaches, total, err := s.breachRepo.List(userID, req, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取违规记录失败: %w", err)
+	}
+
+	return &BreachListResponse{
+		Items:    breaches,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"server/handler"
+	"server/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -42,7 +43,7 @@ func (h *StockHandler) createStock(c *gin.Context) {
 		return
 	}
 
-	stock, err := h.stockService.CreateStock(&req)
+	stock, err := h.stockService.CreateStock(middleware.GetCurrentUserID(c), &req)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -59,7 +60,7 @@ func (h *StockHandler) getStock(c *gin.Context) {
 		return
 	}
 
-	stock, err := h.stockService.GetStock(id)
+	stock, err := h.stockService.GetStock(middleware.GetCurrentUserID(c), id)
 	if err != nil {
 		handler.Error(c, handler.CodeNotFound, err.Error())
 		return
@@ -75,6 +76,7 @@ func (h *StockHandler) listStocks(c *gin.Context) {
 		Region:   c.Query("region"),
 		Category: c.Query("category"),
 	}
+	req.IncludeArchived, _ = strconv.ParseBool(c.Query("includeArchived"))
 
 	// 解析分页参数
 	if pageStr := c.Query("page"); pageStr != "" {
@@ -88,7 +90,7 @@ func (h *StockHandler) listStocks(c *gin.Context) {
 		}
 	}
 
-	response, err := h.stockService.ListStocks(req)
+	response, err := h.stockService.ListStocks(middleware.GetCurrentUserID(c), req)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -111,7 +113,7 @@ func (h *StockHandler) updateStock(c *gin.Context) {
 		return
 	}
 
-	stock, err := h.stockService.UpdateStock(id, &req)
+	stock, err := h.stockService.UpdateStock(middleware.GetCurrentUserID(c), id, &req)
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
@@ -128,7 +130,7 @@ func (h *StockHandler) deleteStock(c *gin.Context) {
 		return
 	}
 
-	err := h.stockService.DeleteStock(id)
+	err := h.stockService.DeleteStock(middleware.GetCurrentUserID(c), id, c.Query("policy"))
 	if err != nil {
 		handler.Error(c, handler.CodeError, err.Error())
 		return
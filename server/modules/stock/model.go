@@ -1,19 +1,35 @@
 package stock
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"server/market"
+)
+
+// 删除仍被计划或日志引用的股票时的处理方式
+const (
+	DeletePolicyBlock   = "block"   // 拒绝删除
+	DeletePolicyCascade = "cascade" // 同时删除引用该股票的计划和日志
+	DeletePolicyArchive = "archive" // 归档并停用，保留计划和日志
+)
 
 // Stock 股票模型
 type Stock struct {
 	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"userId" db:"user_id"`
 	Code      string    `json:"code" db:"code"`
 	Name      string    `json:"name" db:"name"`
 	Region    string    `json:"region" db:"region"`
 	Currency  string    `json:"currency" db:"currency"`
 	Category  string    `json:"category" db:"category"`
+	LotSize   int       `json:"lotSize" db:"lot_size"` // 每手股数，0 表示按地区默认
 	Enabled   bool      `json:"enabled" db:"enabled"`
 	Remark    string    `json:"remark" db:"remark"`
 	CreatedAt time.Time `json:"createdAt" db:"created_at"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+	// ArchivedAt 归档时间，归档的股票默认不出现在列表中
+	ArchivedAt *time.Time `json:"archivedAt,omitempty" db:"archived_at"`
 }
 
 // StockCreateRequest 创建股票请求
@@ -23,7 +39,8 @@ type StockCreateRequest struct {
 	Region   string `json:"region"`
 	Currency string `json:"currency"`
 	Category string `json:"category"`
-	Enabled  bool   `json:"enabled"`
+	LotSize  int    `json:"lotSize" binding:"min=0"` // 每手股数，港股按个股设置
+	Enabled  *bool  `json:"enabled"`                 // 默认启用
 	Remark   string `json:"remark"`
 }
 
@@ -34,6 +51,7 @@ type StockUpdateRequest struct {
 	Region   *string `json:"region,omitempty"`
 	Currency *string `json:"currency,omitempty"`
 	Category *string `json:"category,omitempty"`
+	LotSize  *int    `json:"lotSize,omitempty" binding:"omitempty,min=0"`
 	Enabled  *bool   `json:"enabled,omitempty"`
 	Remark   *string `json:"remark,omitempty"`
 }
@@ -43,8 +61,10 @@ type StockListRequest struct {
 	Keyword  string `form:"keyword"`
 	Region   string `form:"region"`
 	Category string `form:"category"`
-	Page     int    `form:"page"`
-	PageSize int    `form:"pageSize"`
+	// IncludeArchived 为 true 时包含已归档的股票
+	IncludeArchived bool `form:"includeArchived"`
+	Page            int  `form:"page"`
+	PageSize        int  `form:"pageSize"`
 }
 
 // StockListResponse 股票列表响应
@@ -54,3 +74,33 @@ type StockListResponse struct {
 	Page     int     `json:"page"`
 	PageSize int     `json:"pageSize"`
 }
+
+// StockReferences 股票被计划和日志引用的数量
+type StockReferences struct {
+	PlanCount int `json:"planCount"`
+	LogCount  int `json:"logCount"`
+}
+
+// EffectiveLotSize 每手股数，未设置时按地区默认
+func (s *Stock) EffectiveLotSize() int {
+	if s.LotSize > 0 {
+		return s.LotSize
+	}
+	return market.DefaultLotSize(s.EffectiveRegion())
+}
+
+// EffectiveCurrency 股票计价币种（大写），未设置时按地区默认
+func (s *Stock) EffectiveCurrency() string {
+	if s.Currency != "" {
+		return strings.ToUpper(strings.TrimSpace(s.Currency))
+	}
+	return market.DefaultCurrency(s.EffectiveRegion())
+}
+
+// EffectiveRegion 股票所属地区，未设置时按代码格式推断
+func (s *Stock) EffectiveRegion() string {
+	if s.Region != "" {
+		return s.Region
+	}
+	return market.InferRegion(s.Code)
+}
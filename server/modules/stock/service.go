@@ -2,17 +2,19 @@ package stock
 
 import (
 	"fmt"
+	"server/config"
 	"server/utils"
 	"time"
 )
 
 // StockService 股票服务接口
 type StockService interface {
-	CreateStock(req *StockCreateRequest) (*Stock, error)
-	GetStock(id string) (*Stock, error)
-	ListStocks(req *StockListRequest) (*StockListResponse, error)
-	UpdateStock(id string, req *StockUpdateRequest) (*Stock, error)
-	DeleteStock(id string) error
+	CreateStock(userID string, req *StockCreateRequest) (*Stock, error)
+	GetStock(userID, id string) (*Stock, error)
+	ListStocks(userID string, req *StockListRequest) (*StockListResponse, error)
+	UpdateStock(userID, id string, req *StockUpdateRequest) (*Stock, error)
+	DeleteStock(userID, id, policy string) error
+	ResolveStock(userID, code string) (*Stock, error)
 }
 
 // stockService 股票服务实现
@@ -24,17 +26,24 @@ func NewStockService() StockService {
 }
 
 // CreateStock 创建股票
-func (s *stockService) CreateStock(req *StockCreateRequest) (*Stock, error) {
+func (s *stockService) CreateStock(userID string, req *StockCreateRequest) (*Stock, error) {
 	id := utils.GenerateID()
 
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
 	stock := &Stock{
 		ID:        id,
+		UserID:    userID,
 		Code:      req.Code,
 		Name:      req.Name,
 		Region:    req.Region,
 		Currency:  req.Currency,
 		Category:  req.Category,
-		Enabled:   req.Enabled,
+		LotSize:   req.LotSize,
+		Enabled:   enabled,
 		Remark:    req.Remark,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -50,9 +59,9 @@ func (s *stockService) CreateStock(req *StockCreateRequest) (*Stock, error) {
 }
 
 // GetStock 获取股票详情
-func (s *stockService) GetStock(id string) (*Stock, error) {
+func (s *stockService) GetStock(userID, id string) (*Stock, error) {
 	repo := NewStockRepository()
-	stock, err := repo.GetByID(id)
+	stock, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("获取股票失败: %w", err)
 	}
@@ -61,9 +70,9 @@ func (s *stockService) GetStock(id string) (*Stock, error) {
 }
 
 // ListStocks 获取股票列表
-func (s *stockService) ListStocks(req *StockListRequest) (*StockListResponse, error) {
+func (s *stockService) ListStocks(userID string, req *StockListRequest) (*StockListResponse, error) {
 	repo := NewStockRepository()
-	stocks, total, err := repo.List(req)
+	stocks, total, err := repo.List(userID, req)
 	if err != nil {
 		return nil, fmt.Errorf("获取股票列表失败: %w", err)
 	}
@@ -87,23 +96,30 @@ func (s *stockService) ListStocks(req *StockListRequest) (*StockListResponse, er
 }
 
 // UpdateStock 更新股票
-func (s *stockService) UpdateStock(id string, req *StockUpdateRequest) (*Stock, error) {
+func (s *stockService) UpdateStock(userID, id string, req *StockUpdateRequest) (*Stock, error) {
 	repo := NewStockRepository()
 
 	// 检查股票是否存在
-	_, err := repo.GetByID(id)
+	existing, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("股票不存在: %w", err)
 	}
 
+	// 被引用的股票不能修改代码，否则计划和日志会失去对应的股票
+	if req.Code != nil && *req.Code != existing.Code {
+		if err := s.checkUnreferenced(repo, existing); err != nil {
+			return nil, fmt.Errorf("不能修改股票代码: %w", err)
+		}
+	}
+
 	// 更新股票
-	err = repo.Update(id, req)
+	err = repo.Update(userID, id, req)
 	if err != nil {
 		return nil, fmt.Errorf("更新股票失败: %w", err)
 	}
 
 	// 返回更新后的股票
-	updatedStock, err := repo.GetByID(id)
+	updatedStock, err := repo.GetByID(userID, id)
 	if err != nil {
 		return nil, fmt.Errorf("获取更新后的股票失败: %w", err)
 	}
@@ -111,21 +127,80 @@ func (s *stockService) UpdateStock(id string, req *StockUpdateRequest) (*Stock,
 	return updatedStock, nil
 }
 
-// DeleteStock 删除股票
-func (s *stockService) DeleteStock(id string) error {
+// DeleteStock 删除股票，股票仍被计划或日志引用时按 policy 处理，policy 为空时使用配置的默认策略
+func (s *stockService) DeleteStock(userID, id, policy string) error {
+	if policy == "" {
+		policy = config.Load().StockDeletePolicy
+	}
+	switch policy {
+	case DeletePolicyBlock, DeletePolicyCascade, DeletePolicyArchive:
+	default:
+		return fmt.Errorf("不支持的删除策略: %s", policy)
+	}
+
 	repo := NewStockRepository()
 
 	// 检查股票是否存在
-	_, err := repo.GetByID(id)
+	stock, err := repo.GetByID(userID, id)
 	if err != nil {
 		return fmt.Errorf("股票不存在: %w", err)
 	}
 
+	if err := s.checkUnreferenced(repo, stock); err != nil {
+		switch policy {
+		case DeletePolicyCascade:
+			if err := repo.DeleteCascade(userID, id, stock.Code); err != nil {
+				return fmt.Errorf("删除股票失败: %w", err)
+			}
+			utils.LogInfo("股票已删除，同时删除了引用的计划和日志，代码: %s", stock.Code)
+			return nil
+		case DeletePolicyArchive:
+			if err := repo.Archive(userID, id); err != nil {
+				return fmt.Errorf("归档股票失败: %w", err)
+			}
+			utils.LogInfo("股票仍被引用，已归档，代码: %s", stock.Code)
+			return nil
+		}
+		return fmt.Errorf("删除股票失败: %w", err)
+	}
+
 	// 删除股票
-	err = repo.Delete(id)
+	err = repo.Delete(userID, id)
 	if err != nil {
 		return fmt.Errorf("删除股票失败: %w", err)
 	}
 
 	return nil
 }
+
+// checkUnreferenced 检查股票代码是否仍被计划或日志引用，同代码的其他股票仍在时不算悬空引用
+func (s *stockService) checkUnreferenced(repo *StockRepository, stock *Stock) error {
+	others, err := repo.CountOthersByCode(stock.UserID, stock.ID, stock.Code)
+	if err != nil {
+		return fmt.Errorf("检查股票引用失败: %w", err)
+	}
+	if others > 0 {
+		return nil
+	}
+
+	refs, err := repo.CountReferences(stock.UserID, stock.Code)
+	if err != nil {
+		return fmt.Errorf("检查股票引用失败: %w", err)
+	}
+	if refs.PlanCount > 0 || refs.LogCount > 0 {
+		return fmt.Errorf("股票 %s 仍被 %d 个计划和 %d 条日志引用", stock.Code, refs.PlanCount, refs.LogCount)
+	}
+	return nil
+}
+
+// ResolveStock 根据代码查找当前用户已启用的股票，供计划和日志校验股票代码
+func (s *stockService) ResolveStock(userID, code string) (*Stock, error) {
+	stock, err := NewStockRepository().GetByCode(userID, code)
+	if err != nil {
+		return nil, fmt.Errorf("股票 %s 不在股票列表中，请先添加", code)
+	}
+	if !stock.Enabled {
+		return nil, fmt.Errorf("股票 %s 已停用", code)
+	}
+	return stock, nil
+}
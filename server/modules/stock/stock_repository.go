@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"server/storage"
 	"strings"
+	"time"
 )
 
 // StockRepository 股票数据访问层
@@ -17,26 +18,36 @@ func NewStockRepository() *StockRepository {
 
 // Create 创建股票
 func (r *StockRepository) Create(stock *Stock) error {
-	query := `INSERT INTO stocks (id, code, name, region, currency, category, enabled, remark, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO stocks (id, user_id, code, name, region, currency, category, lot_size, enabled, remark, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := storage.GetDB().Exec(query,
-		stock.ID, stock.Code, stock.Name, stock.Region, stock.Currency,
-		stock.Category, stock.Enabled, stock.Remark, stock.CreatedAt, stock.UpdatedAt,
+		stock.ID, stock.UserID, stock.Code, stock.Name, stock.Region, stock.Currency,
+		stock.Category, stock.LotSize, stock.Enabled, stock.Remark, stock.CreatedAt, stock.UpdatedAt,
 	)
 
 	return err
 }
 
 // GetByID 根据ID获取股票
-func (r *StockRepository) GetByID(id string) (*Stock, error) {
-	query := `SELECT id, code, name, region, currency, category, enabled, remark, created_at, updated_at
-		FROM stocks WHERE id = ?`
+func (r *StockRepository) GetByID(userID, id string) (*Stock, error) {
+	return r.getOne("id = ? AND user_id = ?", id, userID)
+}
+
+// GetByCode 根据代码获取未归档的股票，存在多条时优先返回启用的
+func (r *StockRepository) GetByCode(userID, code string) (*Stock, error) {
+	return r.getOne("user_id = ? AND code = ? AND archived_at IS NULL ORDER BY enabled DESC, created_at DESC LIMIT 1", userID, code)
+}
+
+func (r *StockRepository) getOne(where string, args ...interface{}) (*Stock, error) {
+	query := fmt.Sprintf(`SELECT id, user_id, code, name, region, currency, category, lot_size, enabled, remark, created_at, updated_at, archived_at
+		FROM stocks WHERE %s`, where)
 
 	stock := &Stock{}
-	err := storage.GetDB().QueryRow(query, id).Scan(
-		&stock.ID, &stock.Code, &stock.Name, &stock.Region, &stock.Currency,
-		&stock.Category, &stock.Enabled, &stock.Remark, &stock.CreatedAt, &stock.UpdatedAt,
+	var archivedAt sql.NullTime
+	err := storage.GetDB().QueryRow(query, args...).Scan(
+		&stock.ID, &stock.UserID, &stock.Code, &stock.Name, &stock.Region, &stock.Currency,
+		&stock.Category, &stock.LotSize, &stock.Enabled, &stock.Remark, &stock.CreatedAt, &stock.UpdatedAt, &archivedAt,
 	)
 
 	if err != nil {
@@ -45,15 +56,18 @@ func (r *StockRepository) GetByID(id string) (*Stock, error) {
 		}
 		return nil, err
 	}
+	if archivedAt.Valid {
+		stock.ArchivedAt = &archivedAt.Time
+	}
 
 	return stock, nil
 }
 
 // List 获取股票列表
-func (r *StockRepository) List(req *StockListRequest) ([]Stock, int, error) {
+func (r *StockRepository) List(userID string, req *StockListRequest) ([]Stock, int, error) {
 	// 构建查询条件
-	where := []string{"1=1"}
-	args := []interface{}{}
+	where := []string{"user_id = ?"}
+	args := []interface{}{userID}
 
 	if req.Keyword != "" {
 		where = append(where, "(code LIKE ? OR name LIKE ?)")
@@ -71,6 +85,10 @@ func (r *StockRepository) List(req *StockListRequest) ([]Stock, int, error) {
 		args = append(args, req.Category)
 	}
 
+	if !req.IncludeArchived {
+		where = append(where, "archived_at IS NULL")
+	}
+
 	whereClause := strings.Join(where, " AND ")
 
 	// 获取总数
@@ -93,7 +111,7 @@ func (r *StockRepository) List(req *StockListRequest) ([]Stock, int, error) {
 	offset := (page - 1) * pageSize
 
 	// 获取数据
-	query := fmt.Sprintf(`SELECT id, code, name, region, currency, category, enabled, remark, created_at, updated_at
+	query := fmt.Sprintf(`SELECT id, user_id, code, name, region, currency, category, lot_size, enabled, remark, created_at, updated_at, archived_at
 		FROM stocks WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
 
 	args = append(args, pageSize, offset)
@@ -107,13 +125,17 @@ func (r *StockRepository) List(req *StockListRequest) ([]Stock, int, error) {
 	var stocks []Stock
 	for rows.Next() {
 		stock := Stock{}
+		var archivedAt sql.NullTime
 		err := rows.Scan(
-			&stock.ID, &stock.Code, &stock.Name, &stock.Region, &stock.Currency,
-			&stock.Category, &stock.Enabled, &stock.Remark, &stock.CreatedAt, &stock.UpdatedAt,
+			&stock.ID, &stock.UserID, &stock.Code, &stock.Name, &stock.Region, &stock.Currency,
+			&stock.Category, &stock.LotSize, &stock.Enabled, &stock.Remark, &stock.CreatedAt, &stock.UpdatedAt, &archivedAt,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
+		if archivedAt.Valid {
+			stock.ArchivedAt = &archivedAt.Time
+		}
 		stocks = append(stocks, stock)
 	}
 
@@ -126,7 +148,7 @@ func (r *StockRepository) List(req *StockListRequest) ([]Stock, int, error) {
 }
 
 // Update 更新股票
-func (r *StockRepository) Update(id string, req *StockUpdateRequest) error {
+func (r *StockRepository) Update(userID, id string, req *StockUpdateRequest) error {
 	// 构建更新字段
 	setParts := []string{}
 	args := []interface{}{}
@@ -151,6 +173,10 @@ func (r *StockRepository) Update(id string, req *StockUpdateRequest) error {
 		setParts = append(setParts, "category = ?")
 		args = append(args, *req.Category)
 	}
+	if req.LotSize != nil {
+		setParts = append(setParts, "lot_size = ?")
+		args = append(args, *req.LotSize)
+	}
 	if req.Enabled != nil {
 		setParts = append(setParts, "enabled = ?")
 		args = append(args, *req.Enabled)
@@ -166,12 +192,12 @@ func (r *StockRepository) Update(id string, req *StockUpdateRequest) error {
 
 	// 添加更新时间
 	setParts = append(setParts, "updated_at = ?")
-	args = append(args, "NOW()")
+	args = append(args, time.Now())
 
 	// 添加WHERE条件
-	args = append(args, id)
+	args = append(args, id, userID)
 
-	query := fmt.Sprintf("UPDATE stocks SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	query := fmt.Sprintf("UPDATE stocks SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
 	result, err := storage.GetDB().Exec(query, args...)
 	if err != nil {
 		return err
@@ -190,9 +216,9 @@ func (r *StockRepository) Update(id string, req *StockUpdateRequest) error {
 }
 
 // Delete 删除股票
-func (r *StockRepository) Delete(id string) error {
-	query := "DELETE FROM stocks WHERE id = ?"
-	result, err := storage.GetDB().Exec(query, id)
+func (r *StockRepository) Delete(userID, id string) error {
+	query := "DELETE FROM stocks WHERE id = ? AND user_id = ?"
+	result, err := storage.GetDB().Exec(query, id, userID)
 	if err != nil {
 		return err
 	}
@@ -208,3 +234,77 @@ func (r *StockRepository) Delete(id string) error {
 
 	return nil
 }
+
+// CountReferences 统计引用该股票代码的计划和日志数量
+func (r *StockRepository) CountReferences(userID, code string) (*StockReferences, error) {
+	refs := &StockReferences{}
+	err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM plans WHERE user_id = ? AND stock_code = ?", userID, code).Scan(&refs.PlanCount)
+	if err != nil {
+		return nil, err
+	}
+	err = storage.GetDB().QueryRow("SELECT COUNT(*) FROM logs WHERE user_id = ? AND stock_code = ?", userID, code).Scan(&refs.LogCount)
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// Archive 归档并停用股票
+func (r *StockRepository) Archive(userID, id string) error {
+	now := time.Now()
+	result, err := storage.GetDB().Exec("UPDATE stocks SET enabled = 0, archived_at = ?, updated_at = ? WHERE id = ? AND user_id = ?",
+		now, now, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("股票不存在")
+	}
+
+	return nil
+}
+
+// DeleteCascade 在事务中删除股票及引用该股票代码的计划和日志
+func (r *StockRepository) DeleteCascade(userID, id, code string) error {
+	tx, err := storage.GetDB().SQL.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM logs WHERE user_id = ? AND stock_code = ?", userID, code); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM plans WHERE user_id = ? AND stock_code = ?", userID, code); err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM stocks WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("股票不存在")
+	}
+
+	return tx.Commit()
+}
+
+// CountOthersByCode 统计同一代码的其他未归档股票数量
+func (r *StockRepository) CountOthersByCode(userID, id, code string) (int, error) {
+	var count int
+	err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM stocks WHERE user_id = ? AND code = ? AND id != ? AND archived_at IS NULL",
+		userID, code, id).Scan(&count)
+	return count, err
+}
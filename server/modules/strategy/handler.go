@@ -0,0 +1,131 @@
+package strategy
+
+import (
+	"strconv"
+
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StrategyHandler 策略处理器
+type StrategyHandler struct {
+	strategyService StrategyService
+}
+
+// NewStrategyHandler 创建策略处理器
+func NewStrategyHandler() *StrategyHandler {
+	return &StrategyHandler{
+		strategyService: NewStrategyService(),
+	}
+}
+
+// RegisterStrategyRoutes 注册策略路由
+func RegisterStrategyRoutes(r *gin.RouterGroup) {
+	handler := NewStrategyHandler()
+
+	g := r.Group("/strategies")
+	{
+		g.POST("/create", handler.createStrategy)
+		g.GET("/getList", handler.listStrategies)
+		g.GET("/getDetail/:id", handler.getStrategy)
+		g.PUT("/update/:id", handler.updateStrategy)
+		g.DELETE("/delete/:id", handler.deleteStrategy)
+	}
+}
+
+// CreateStrategy 创建策略
+func (h *StrategyHandler) createStrategy(c *gin.Context) {
+	var req StrategyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	strategy, err := h.strategyService.CreateStrategy(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, strategy)
+}
+
+// GetStrategy 获取策略详情
+func (h *StrategyHandler) getStrategy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "策略ID不能为空")
+		return
+	}
+
+	strategy, err := h.strategyService.GetStrategy(middleware.GetCurrentUserID(c), id)
+	if err != nil {
+		handler.Error(c, handler.CodeNotFound, err.Error())
+		return
+	}
+
+	handler.Success(c, strategy)
+}
+
+// ListStrategies 获取策略列表
+func (h *StrategyHandler) listStrategies(c *gin.Context) {
+	req := &StrategyListRequest{
+		Kind:     c.Query("kind"),
+		Category: c.Query("category"),
+		Keyword:  c.Query("keyword"),
+	}
+	if enabledStr := c.Query("enabled"); enabledStr != "" {
+		if enabled, err := strconv.ParseBool(enabledStr); err == nil {
+			req.Enabled = &enabled
+		}
+	}
+
+	response, err := h.strategyService.ListStrategies(middleware.GetCurrentUserID(c), req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, response)
+}
+
+// UpdateStrategy 更新策略
+func (h *StrategyHandler) updateStrategy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "策略ID不能为空")
+		return
+	}
+
+	var req StrategyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+		return
+	}
+
+	strategy, err := h.strategyService.UpdateStrategy(middleware.GetCurrentUserID(c), id, &req)
+	if err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, strategy)
+}
+
+// DeleteStrategy 删除策略
+func (h *StrategyHandler) deleteStrategy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		handler.Error(c, handler.CodeInvalid, "策略ID不能为空")
+		return
+	}
+
+	if err := h.strategyService.DeleteStrategy(middleware.GetCurrentUserID(c), id); err != nil {
+		handler.Error(c, handler.CodeError, err.Error())
+		return
+	}
+
+	handler.Success(c, gin.H{"id": id})
+}
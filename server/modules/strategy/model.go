@@ -0,0 +1,92 @@
+package strategy
+
+import "time"
+
+// 策略类型
+const (
+	KindSelection = "selection" // 选股策略，对应计划的 strategy
+	KindTrading   = "trading"   // 交易策略，对应计划的 tradingStrategy 和日志的 strategy
+)
+
+// 风险等级
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// Strategy 策略模型，内置策略所有用户共用，用户创建的策略只对自己可见
+type Strategy struct {
+	ID          string                 `json:"id" db:"id"`
+	UserID      string                 `json:"userId" db:"user_id"` // 为空表示内置策略
+	Kind        string                 `json:"kind" db:"kind"`
+	Name        string                 `json:"name" db:"name"`
+	Category    string                 `json:"category" db:"category"`
+	Description string                 `json:"description" db:"description"`
+	Detail      string                 `json:"detail" db:"detail"`
+	RiskLevel   string                 `json:"riskLevel" db:"risk_level"`
+	Parameters  map[string]interface{} `json:"parameters" db:"parameters"`
+	WinRate     string                 `json:"winRate" db:"win_rate"`
+	SuitableFor string                 `json:"suitableFor" db:"suitable_for"`
+	Pros        []string               `json:"pros" db:"pros"`
+	Cons        []string               `json:"cons" db:"cons"`
+	Enabled     bool                   `json:"enabled" db:"enabled"`
+	SortOrder   int                    `json:"sortOrder" db:"sort_order"`
+	CreatedAt   time.Time              `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time              `json:"updatedAt" db:"updated_at"`
+}
+
+// StrategyCreateRequest 创建策略请求，ID 为空时自动生成
+type StrategyCreateRequest struct {
+	ID          string                 `json:"id"`
+	Kind        string                 `json:"kind" binding:"required"`
+	Name        string                 `json:"name" binding:"required"`
+	Category    string                 `json:"category"`
+	Description string                 `json:"description"`
+	Detail      string                 `json:"detail"`
+	RiskLevel   string                 `json:"riskLevel"` // 默认中风险
+	Parameters  map[string]interface{} `json:"parameters"`
+	WinRate     string                 `json:"winRate"`
+	SuitableFor string                 `json:"suitableFor"`
+	Pros        []string               `json:"pros"`
+	Cons        []string               `json:"cons"`
+	Enabled     *bool                  `json:"enabled"` // 默认启用
+	SortOrder   int                    `json:"sortOrder"`
+}
+
+// StrategyUpdateRequest 更新策略请求，ID 和类型不可修改
+type StrategyUpdateRequest struct {
+	Name        *string                 `json:"name,omitempty"`
+	Category    *string                 `json:"category,omitempty"`
+	Description *string                 `json:"description,omitempty"`
+	Detail      *string                 `json:"detail,omitempty"`
+	RiskLevel   *string                 `json:"riskLevel,omitempty"`
+	Parameters  *map[string]interface{} `json:"parameters,omitempty"`
+	WinRate     *string                 `json:"winRate,omitempty"`
+	SuitableFor *string                 `json:"suitableFor,omitempty"`
+	Pros        *[]string               `json:"pros,omitempty"`
+	Cons        *[]string               `json:"cons,omitempty"`
+	Enabled     *bool                   `json:"enabled,omitempty"`
+	SortOrder   *int                    `json:"sortOrder,omitempty"`
+}
+
+// StrategyListRequest 策略列表请求
+type StrategyListRequest struct {
+	Kind     string `form:"kind"`
+	Category string `form:"category"`
+	Keyword  string `form:"keyword"`
+	// Enabled 为空时返回全部，true/false 按启用状态过滤
+	Enabled *bool `form:"enabled"`
+}
+
+// StrategyListResponse 策略列表响应
+type StrategyListResponse struct {
+	Items []Strategy `json:"list"`
+	Total int        `json:"total"`
+}
+
+// StrategyReferences 策略被计划和日志引用的数量
+type StrategyReferences struct {
+	PlanCount int `json:"planCount"`
+	LogCount  int `json:"logCount"`
+}
@@ -0,0 +1,210 @@
+package strategy
+
+import (
+	"fmt"
+	"regexp"
+	"server/utils"
+	"time"
+)
+
+// idPattern 策略ID只允许小写字母、数字和下划线，以字母开头
+var idPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// kindNames 策略类型的中文名称
+var kindNames = map[string]string{
+	KindSelection: "选股策略",
+	KindTrading:   "交易策略",
+}
+
+// StrategyService 策略服务接口
+type StrategyService interface {
+	CreateStrategy(userID string, req *StrategyCreateRequest) (*Strategy, error)
+	GetStrategy(userID, id string) (*Strategy, error)
+	ListStrategies(userID string, req *StrategyListRequest) (*StrategyListResponse, error)
+	UpdateStrategy(userID, id string, req *StrategyUpdateRequest) (*Strategy, error)
+	DeleteStrategy(userID, id string) error
+	ValidateStrategy(userID, kind, id string) error
+}
+
+// strategyService 策略服务实现
+type strategyService struct {
+	strategyRepo *StrategyRepository
+}
+
+// NewStrategyService 创建策略服务
+func NewStrategyService() StrategyService {
+	return &strategyService{
+		strategyRepo: NewStrategyRepository(),
+	}
+}
+
+// validateRiskLevel 校验风险等级
+func validateRiskLevel(level string) error {
+	switch level {
+	case RiskLow, RiskMedium, RiskHigh:
+		return nil
+	}
+	return fmt.Errorf("无效的风险等级: %s", level)
+}
+
+// CreateStrategy 创建用户自己的策略
+func (s *strategyService) CreateStrategy(userID string, req *StrategyCreateRequest) (*Strategy, error) {
+	if _, ok := kindNames[req.Kind]; !ok {
+		return nil, fmt.Errorf("无效的策略类型: %s", req.Kind)
+	}
+
+	id := req.ID
+	if id == "" {
+		id = "strategy_" + utils.GenerateID()
+	} else if !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("策略ID只能包含小写字母、数字和下划线，且以字母开头")
+	}
+	exists, err := s.strategyRepo.Exists(id)
+	if err != nil {
+		return nil, fmt.Errorf("检查策略ID失败: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("策略ID %s 已存在", id)
+	}
+
+	riskLevel := req.RiskLevel
+	if riskLevel == "" {
+		riskLevel = RiskMedium
+	}
+	if err := validateRiskLevel(riskLevel); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	strategy := &Strategy{
+		ID:          id,
+		UserID:      userID,
+		Kind:        req.Kind,
+		Name:        req.Name,
+		Category:    req.Category,
+		Description: req.Description,
+		Detail:      req.Detail,
+		RiskLevel:   riskLevel,
+		Parameters:  req.Parameters,
+		WinRate:     req.WinRate,
+		SuitableFor: req.SuitableFor,
+		Pros:        req.Pros,
+		Cons:        req.Cons,
+		Enabled:     enabled,
+		SortOrder:   req.SortOrder,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.strategyRepo.Create(strategy); err != nil {
+		return nil, fmt.Errorf("创建策略失败: %w", err)
+	}
+
+	return s.strategyRepo.GetByID(userID, id)
+}
+
+// GetStrategy 获取策略详情
+func (s *strategyService) GetStrategy(userID, id string) (*Strategy, error) {
+	strategy, err := s.strategyRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取策略失败: %w", err)
+	}
+
+	return strategy, nil
+}
+
+// ListStrategies 获取策略列表
+func (s *strategyService) ListStrategies(userID string, req *StrategyListRequest) (*StrategyListResponse, error) {
+	if req.Kind != "" {
+		if _, ok := kindNames[req.Kind]; !ok {
+			return nil, fmt.Errorf("无效的策略类型: %s", req.Kind)
+		}
+	}
+
+	strategies, err := s.strategyRepo.List(userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("获取策略列表失败: %w", err)
+	}
+
+	return &StrategyListResponse{
+		Items: strategies,
+		Total: len(strategies),
+	}, nil
+}
+
+// ownStrategy 获取用户自己创建的策略，内置策略不能修改或删除
+func (s *strategyService) ownStrategy(userID, id string) (*Strategy, error) {
+	strategy, err := s.strategyRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("策略不存在: %w", err)
+	}
+	if strategy.UserID == "" {
+		return nil, fmt.Errorf("内置策略 %s 不能修改或删除", strategy.Name)
+	}
+	return strategy, nil
+}
+
+// UpdateStrategy 更新策略
+func (s *strategyService) UpdateStrategy(userID, id string, req *StrategyUpdateRequest) (*Strategy, error) {
+	if _, err := s.ownStrategy(userID, id); err != nil {
+		return nil, err
+	}
+	if req.RiskLevel != nil {
+		if err := validateRiskLevel(*req.RiskLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.strategyRepo.Update(userID, id, req); err != nil {
+		return nil, fmt.Errorf("更新策略失败: %w", err)
+	}
+
+	updated, err := s.strategyRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("获取更新后的策略失败: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteStrategy 删除策略，仍被计划或日志引用的策略只能停用
+func (s *strategyService) DeleteStrategy(userID, id string) error {
+	strategy, err := s.ownStrategy(userID, id)
+	if err != nil {
+		return err
+	}
+
+	refs, err := s.strategyRepo.CountReferences(userID, strategy)
+	if err != nil {
+		return fmt.Errorf("检查策略引用失败: %w", err)
+	}
+	if refs.PlanCount > 0 || refs.LogCount > 0 {
+		return fmt.Errorf("策略 %s 仍被 %d 个计划和 %d 条日志引用，请改为停用", id, refs.PlanCount, refs.LogCount)
+	}
+
+	if err := s.strategyRepo.Delete(userID, id); err != nil {
+		return fmt.Errorf("删除策略失败: %w", err)
+	}
+	utils.LogInfo("策略已删除，用户: %s, ID: %s", userID, id)
+
+	return nil
+}
+
+// ValidateStrategy 校验计划和日志引用的策略对用户可见、类型正确且已启用，id 为空时不校验
+func (s *strategyService) ValidateStrategy(userID, kind, id string) error {
+	if id == "" {
+		return nil
+	}
+	strategy, err := s.strategyRepo.GetByID(userID, id)
+	if err != nil || strategy.Kind != kind {
+		return fmt.Errorf("%s %s 不存在", kindNames[kind], id)
+	}
+	if !strategy.Enabled {
+		return fmt.Errorf("%s %s 已停用", kindNames[kind], strategy.Name)
+	}
+	return nil
+}
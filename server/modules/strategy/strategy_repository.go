@@ -0,0 +1,292 @@
+package strategy
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"server/storage"
+	"strings"
+	"time"
+)
+
+// StrategyRepository 策略数据访问层
+type StrategyRepository struct{}
+
+// NewStrategyRepository 创建策略仓库
+func NewStrategyRepository() *StrategyRepository {
+	return &StrategyRepository{}
+}
+
+const strategyColumns = `id, user_id, kind, name, category, description, detail, risk_level, parameters,
+	win_rate, suitable_for, pros, cons, enabled, sort_order, created_at, updated_at`
+
+// visibleCondition 用户可见的策略：内置策略和自己创建的策略
+const visibleCondition = "(user_id IS NULL OR user_id = ?)"
+
+// Create 创建策略
+func (r *StrategyRepository) Create(s *Strategy) error {
+	parameters, pros, cons, err := encodeJSONFields(s.Parameters, s.Pros, s.Cons)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO strategies (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, strategyColumns)
+	_, err = storage.GetDB().Exec(query,
+		s.ID, s.UserID, s.Kind, s.Name, s.Category, s.Description, s.Detail, s.RiskLevel, parameters,
+		s.WinRate, s.SuitableFor, pros, cons, s.Enabled, s.SortOrder, s.CreatedAt, s.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID 根据ID获取用户可见的策略
+func (r *StrategyRepository) GetByID(userID, id string) (*Strategy, error) {
+	query := fmt.Sprintf("SELECT %s FROM strategies WHERE id = ? AND %s", strategyColumns, visibleCondition)
+	s, err := scanStrategy(storage.GetDB().QueryRow(query, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("策略不存在")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Exists 判断策略ID是否已被占用，策略ID在所有用户间唯一
+func (r *StrategyRepository) Exists(id string) (bool, error) {
+	var count int
+	err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM strategies WHERE id = ?", id).Scan(&count)
+	return count > 0, err
+}
+
+// List 获取用户可见的策略列表，按类型和排序号升序
+func (r *StrategyRepository) List(userID string, req *StrategyListRequest) ([]Strategy, error) {
+	where := []string{visibleCondition}
+	args := []interface{}{userID}
+
+	if req.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, req.Kind)
+	}
+	if req.Category != "" {
+		where = append(where, "category = ?")
+		args = append(args, req.Category)
+	}
+	if req.Keyword != "" {
+		where = append(where, "(id LIKE ? OR name LIKE ? OR description LIKE ?)")
+		keyword := "%" + req.Keyword + "%"
+		args = append(args, keyword, keyword, keyword)
+	}
+	if req.Enabled != nil {
+		where = append(where, "enabled = ?")
+		args = append(args, *req.Enabled)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM strategies WHERE %s ORDER BY kind ASC, sort_order ASC, created_at ASC",
+		strategyColumns, strings.Join(where, " AND "))
+	rows, err := storage.GetDB().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	strategies := []Strategy{}
+	for rows.Next() {
+		s, err := scanStrategy(rows)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return strategies, nil
+}
+
+// Update 更新用户自己创建的策略
+func (r *StrategyRepository) Update(userID, id string, req *StrategyUpdateRequest) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	set := func(column string, value interface{}) {
+		setParts = append(setParts, column+" = ?")
+		args = append(args, value)
+	}
+	if req.Name != nil {
+		set("name", *req.Name)
+	}
+	if req.Category != nil {
+		set("category", *req.Category)
+	}
+	if req.Description != nil {
+		set("description", *req.Description)
+	}
+	if req.Detail != nil {
+		set("detail", *req.Detail)
+	}
+	if req.RiskLevel != nil {
+		set("risk_level", *req.RiskLevel)
+	}
+	if req.WinRate != nil {
+		set("win_rate", *req.WinRate)
+	}
+	if req.SuitableFor != nil {
+		set("suitable_for", *req.SuitableFor)
+	}
+	if req.Enabled != nil {
+		set("enabled", *req.Enabled)
+	}
+	if req.SortOrder != nil {
+		set("sort_order", *req.SortOrder)
+	}
+	if req.Parameters != nil {
+		data, err := json.Marshal(*req.Parameters)
+		if err != nil {
+			return fmt.Errorf("编码策略参数失败: %w", err)
+		}
+		set("parameters", string(data))
+	}
+	if req.Pros != nil {
+		data, err := json.Marshal(*req.Pros)
+		if err != nil {
+			return fmt.Errorf("编码策略优点失败: %w", err)
+		}
+		set("pros", string(data))
+	}
+	if req.Cons != nil {
+		data, err := json.Marshal(*req.Cons)
+		if err != nil {
+			return fmt.Errorf("编码策略缺点失败: %w", err)
+		}
+		set("cons", string(data))
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("没有要更新的字段")
+	}
+
+	set("updated_at", time.Now())
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf("UPDATE strategies SET %s WHERE id = ? AND user_id = ?", strings.Join(setParts, ", "))
+	result, err := storage.GetDB().Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("策略不存在")
+	}
+
+	return nil
+}
+
+// Delete 删除用户自己创建的策略
+func (r *StrategyRepository) Delete(userID, id string) error {
+	result, err := storage.GetDB().Exec("DELETE FROM strategies WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("策略不存在")
+	}
+
+	return nil
+}
+
+// CountReferences 统计用户引用该策略的计划和日志数量
+func (r *StrategyRepository) CountReferences(userID string, s *Strategy) (*StrategyReferences, error) {
+	refs := &StrategyReferences{}
+	planColumn := "strategy"
+	if s.Kind == KindTrading {
+		planColumn = "trading_strategy"
+	}
+	err := storage.GetDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM plans WHERE user_id = ? AND %s = ?", planColumn), userID, s.ID).Scan(&refs.PlanCount)
+	if err != nil {
+		return nil, err
+	}
+	if s.Kind == KindTrading {
+		err = storage.GetDB().QueryRow("SELECT COUNT(*) FROM logs WHERE user_id = ? AND strategy = ?", userID, s.ID).Scan(&refs.LogCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// rowScanner sql.Row 和 sql.Rows 的公共扫描接口
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanStrategy 扫描一行策略数据并解码 JSON 字段
+func scanStrategy(row rowScanner) (*Strategy, error) {
+	s := &Strategy{}
+	var userID, category, description, detail, parameters, winRate, suitableFor, pros, cons sql.NullString
+	err := row.Scan(
+		&s.ID, &userID, &s.Kind, &s.Name, &category, &description, &detail, &s.RiskLevel, &parameters,
+		&winRate, &suitableFor, &pros, &cons, &s.Enabled, &s.SortOrder, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.UserID = userID.String
+	s.Category = category.String
+	s.Description = description.String
+	s.Detail = detail.String
+	s.WinRate = winRate.String
+	s.SuitableFor = suitableFor.String
+
+	s.Parameters = map[string]interface{}{}
+	s.Pros = []string{}
+	s.Cons = []string{}
+	for _, field := range []struct {
+		raw  sql.NullString
+		dest interface{}
+	}{{parameters, &s.Parameters}, {pros, &s.Pros}, {cons, &s.Cons}} {
+		if field.raw.String == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(field.raw.String), field.dest); err != nil {
+			return nil, fmt.Errorf("解析策略 %s 数据失败: %w", s.ID, err)
+		}
+	}
+
+	return s, nil
+}
+
+// encodeJSONFields 将参数、优点、缺点编码为 JSON 文本
+func encodeJSONFields(parameters map[string]interface{}, pros, cons []string) (string, string, string, error) {
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+	if pros == nil {
+		pros = []string{}
+	}
+	if cons == nil {
+		cons = []string{}
+	}
+	p, err := json.Marshal(parameters)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码策略参数失败: %w", err)
+	}
+	pr, err := json.Marshal(pros)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码策略优点失败: %w", err)
+	}
+	c, err := json.Marshal(cons)
+	if err != nil {
+		return "", "", "", fmt.Errorf("编码策略缺点失败: %w", err)
+	}
+	return string(p), string(pr), string(c), nil
+}
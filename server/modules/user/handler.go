@@ -0,0 +1,137 @@
+package user
+
+import (
+	"server/handler"
+	"server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterUserRoutes 注册用户路由，注册和登录无需认证
+func RegisterUserRoutes(r *gin.RouterGroup) {
+	userService := NewUserService()
+
+	// 小程序登录，code 由 wx.login 获取
+	r.POST("/auth/wechat/login", func(c *gin.Context) {
+		var req WechatLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+			return
+		}
+
+		resp, err := userService.WechatLogin(c.Request.Context(), &req)
+		if err != nil {
+			handler.Error(c, handler.CodeError, err.Error())
+			return
+		}
+
+		handler.Success(c, resp)
+	})
+
+	g := r.Group("/user")
+	{
+		g.POST("/register", func(c *gin.Context) {
+			var req RegisterRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			user, err := userService.Register(&req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, user)
+		})
+
+		g.POST("/login", func(c *gin.Context) {
+			var req LoginRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			resp, err := userService.Login(&req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, resp)
+		})
+
+		g.GET("/profile", middleware.RequireAuth(), func(c *gin.Context) {
+			user, err := userService.GetUser(middleware.GetCurrentUser(c).ID)
+			if err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, user)
+		})
+
+		g.GET("/settings", middleware.RequireAuth(), func(c *gin.Context) {
+			settings, err := userService.GetSettings(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, settings)
+		})
+
+		g.PUT("/settings", middleware.RequireAuth(), func(c *gin.Context) {
+			var req SettingsUpdateRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			settings, err := userService.UpdateSettings(middleware.GetCurrentUserID(c), &req)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, settings)
+		})
+
+		// 微信绑定状态，未绑定时 data 为 null
+		g.GET("/wechat", middleware.RequireAuth(), func(c *gin.Context) {
+			binding, err := userService.GetWechatBinding(middleware.GetCurrentUserID(c))
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, binding)
+		})
+
+		g.POST("/wechat/bind", middleware.RequireAuth(), func(c *gin.Context) {
+			var req WechatBindRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				handler.Error(c, handler.CodeInvalid, "参数错误: "+err.Error())
+				return
+			}
+
+			binding, err := userService.BindWechat(c.Request.Context(), middleware.GetCurrentUserID(c), req.Code)
+			if err != nil {
+				handler.Error(c, handler.CodeError, err.Error())
+				return
+			}
+
+			handler.Success(c, binding)
+		})
+
+		g.DELETE("/wechat/unbind", middleware.RequireAuth(), func(c *gin.Context) {
+			if err := userService.UnbindWechat(middleware.GetCurrentUserID(c)); err != nil {
+				handler.Error(c, handler.CodeNotFound, err.Error())
+				return
+			}
+
+			handler.Success(c, nil)
+		})
+	}
+}
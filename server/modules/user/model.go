@@ -0,0 +1,116 @@
+package user
+
+import "time"
+
+// 用户状态
+const (
+	StatusDisabled = 0 // 禁用
+	StatusEnabled  = 1 // 正常
+)
+
+// User 用户模型
+type User struct {
+	ID           string    `json:"id" db:"id"`
+	Username     string    `json:"username" db:"username"`
+	Email        string    `json:"email" db:"email"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Nickname     string    `json:"nickname" db:"nickname"`
+	Status       int       `json:"status" db:"status"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// RegisterRequest 注册请求
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=32"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Password string `json:"password" binding:"required,min=6,max=64"`
+	Nickname string `json:"nickname"`
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse 登录响应
+type LoginResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"` // token 过期时间（Unix 秒）
+	User      *User  `json:"user"`
+}
+
+// WechatLoginRequest 小程序登录请求
+// 微信未绑定账号时，传入用户名和密码则绑定到该账号，否则自动创建新账号
+type WechatLoginRequest struct {
+	Code     string `json:"code" binding:"required"` // wx.login 返回的 code
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Nickname string `json:"nickname"` // 新建账号时使用的昵称
+}
+
+// WechatLoginResponse 小程序登录响应
+type WechatLoginResponse struct {
+	*LoginResponse
+	Created bool `json:"created"` // 是否本次新建的账号
+}
+
+// WechatBindRequest 已登录用户绑定微信请求
+type WechatBindRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// WechatBinding 用户绑定的小程序微信账号，每个用户只能绑定一个
+type WechatBinding struct {
+	UserID     string    `json:"-" db:"user_id"`
+	OpenID     string    `json:"openid" db:"openid"`
+	UnionID    string    `json:"unionid" db:"unionid"`
+	SessionKey string    `json:"-" db:"session_key"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// DefaultMaxRiskPct 单笔交易默认最大风险比例（%）
+const DefaultMaxRiskPct = 1.0
+
+// DefaultBaseCurrency 默认本位币，持仓和复盘统计换算到本位币汇总
+const DefaultBaseCurrency = "CNY"
+
+// 违反风险限制时的处理方式
+const (
+	RiskPolicyReject = "reject" // 拒绝创建日志
+	RiskPolicyWarn   = "warn"   // 保存日志并标记警告
+)
+
+// UserSettings 用户账户设置
+type UserSettings struct {
+	UserID        string  `json:"-" db:"user_id"`
+	AccountEquity float64 `json:"accountEquity" db:"account_equity"`  // 账户权益
+	MaxRiskPct    float64 `json:"maxRiskPct" db:"max_risk_pct"`       // 单笔交易最大风险占权益的比例（%）
+	AutoSizePlans bool    `json:"autoSizePlans" db:"auto_size_plans"` // 创建计划未填写数量时按风险自动计算
+	BaseCurrency  string  `json:"baseCurrency" db:"base_currency"`    // 本位币
+
+	// 风险限制，金额按本位币计算，0 表示不限制
+	MaxPositionValue float64 `json:"maxPositionValue" db:"max_position_value"` // 单只股票最大持仓金额
+	MaxTotalExposure float64 `json:"maxTotalExposure" db:"max_total_exposure"` // 最大总持仓金额
+	MaxOpenPositions int     `json:"maxOpenPositions" db:"max_open_positions"` // 最多同时持有的股票数
+	MaxDailyLoss     float64 `json:"maxDailyLoss" db:"max_daily_loss"`         // 单日最大已实现亏损
+	RiskLimitPolicy  string  `json:"riskLimitPolicy" db:"risk_limit_policy"`   // 违反限制时的处理方式：reject / warn
+
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SettingsUpdateRequest 更新用户设置请求
+type SettingsUpdateRequest struct {
+	AccountEquity *float64 `json:"accountEquity,omitempty" binding:"omitempty,min=0"`
+	MaxRiskPct    *float64 `json:"maxRiskPct,omitempty" binding:"omitempty,gt=0,max=100"`
+	AutoSizePlans *bool    `json:"autoSizePlans,omitempty"`
+	BaseCurrency  *string  `json:"baseCurrency,omitempty" binding:"omitempty,len=3,alpha"`
+
+	MaxPositionValue *float64 `json:"maxPositionValue,omitempty" binding:"omitempty,min=0"`
+	MaxTotalExposure *float64 `json:"maxTotalExposure,omitempty" binding:"omitempty,min=0"`
+	MaxOpenPositions *int     `json:"maxOpenPositions,omitempty" binding:"omitempty,min=0"`
+	MaxDailyLoss     *float64 `json:"maxDailyLoss,omitempty" binding:"omitempty,min=0"`
+	RiskLimitPolicy  *string  `json:"riskLimitPolicy,omitempty" binding:"omitempty,oneof=reject warn"`
+}
@@ -0,0 +1,367 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/config"
+	"server/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserService 用户服务接口
+type UserService interface {
+	Register(req *RegisterRequest) (*User, error)
+	Login(req *LoginRequest) (*LoginResponse, error)
+	// WechatLogin 小程序登录，签发与网页登录相同的 token
+	WechatLogin(ctx context.Context, req *WechatLoginRequest) (*WechatLoginResponse, error)
+	BindWechat(ctx context.Context, userID, code string) (*WechatBinding, error)
+	UnbindWechat(userID string) error
+	// GetWechatBinding 获取用户的微信绑定，未绑定时返回 nil
+	GetWechatBinding(userID string) (*WechatBinding, error)
+	GetUser(id string) (*User, error)
+	GetSettings(userID string) (*UserSettings, error)
+	UpdateSettings(userID string, req *SettingsUpdateRequest) (*UserSettings, error)
+}
+
+// userService 用户服务实现
+type userService struct {
+	userRepo *UserRepository
+}
+
+// NewUserService 创建用户服务
+func NewUserService() UserService {
+	return &userService{
+		userRepo: NewUserRepository(),
+	}
+}
+
+// Register 注册用户
+func (s *userService) Register(req *RegisterRequest) (*User, error) {
+	exists, err := s.userRepo.ExistsByUsername(req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("检查用户名失败: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("用户名已存在")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	nickname := req.Nickname
+	if nickname == "" {
+		nickname = req.Username
+	}
+
+	user := &User{
+		ID:           utils.GenerateID(),
+		Username:     req.Username,
+		Email:        req.Email,
+		PasswordHash: string(hash),
+		Nickname:     nickname,
+		Status:       StatusEnabled,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.createUser(user); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("用户注册成功，ID: %s, 用户名: %s", user.ID, user.Username)
+	return user, nil
+}
+
+// createUser 写入新用户，第一个用户接管启用多用户之前的历史数据
+func (s *userService) createUser(user *User) error {
+	count, err := s.userRepo.Count()
+	if err != nil {
+		return fmt.Errorf("统计用户失败: %w", err)
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return fmt.Errorf("创建用户失败: %w", err)
+	}
+
+	if count == 0 {
+		if err := s.userRepo.ClaimOrphanData(user.ID); err != nil {
+			return fmt.Errorf("迁移历史数据失败: %w", err)
+		}
+		utils.LogInfo("历史数据已归属到首个用户: %s", user.Username)
+	}
+	return nil
+}
+
+// Login 校验用户名密码并签发 token
+func (s *userService) Login(req *LoginRequest) (*LoginResponse, error) {
+	user, err := s.authenticate(req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueToken(user)
+}
+
+// authenticate 校验用户名密码
+func (s *userService) authenticate(username, password string) (*User, error) {
+	user, err := s.userRepo.GetByUsername(username)
+	if err != nil {
+		utils.LogWarning("登录失败，用户不存在: %s", username)
+		return nil, fmt.Errorf("用户名或密码错误")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		utils.LogWarning("登录失败，密码错误: %s", username)
+		return nil, fmt.Errorf("用户名或密码错误")
+	}
+	return user, nil
+}
+
+// WechatLogin 用 code 换取 openid：已绑定时登录绑定的账号；未绑定时传入用户名密码则绑定该账号，否则新建账号并绑定
+func (s *userService) WechatLogin(ctx context.Context, req *WechatLoginRequest) (*WechatLoginResponse, error) {
+	session, err := code2Session(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	binding, err := s.userRepo.GetWechatByOpenID(session.OpenID)
+	if err != nil {
+		return nil, fmt.Errorf("获取微信绑定失败: %w", err)
+	}
+
+	var user *User
+	created := false
+	switch {
+	case binding != nil:
+		user, err = s.userRepo.GetByID(binding.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("获取用户失败: %w", err)
+		}
+		if req.Username != "" && req.Username != user.Username {
+			return nil, fmt.Errorf("该微信已绑定其他账号")
+		}
+	case req.Username != "":
+		user, err = s.authenticate(req.Username, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := s.userRepo.GetWechatByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("获取微信绑定失败: %w", err)
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("该账号已绑定其他微信")
+		}
+	default:
+		user, err = s.createWechatUser(session.OpenID, req.Nickname)
+		if err != nil {
+			return nil, err
+		}
+		created = true
+	}
+
+	if err := s.saveWechat(user.ID, session, binding); err != nil {
+		return nil, err
+	}
+	resp, err := s.issueToken(user)
+	if err != nil {
+		return nil, err
+	}
+	return &WechatLoginResponse{LoginResponse: resp, Created: created}, nil
+}
+
+// BindWechat 已登录用户绑定微信，重复绑定时更换为新的微信
+func (s *userService) BindWechat(ctx context.Context, userID, code string) (*WechatBinding, error) {
+	session, err := code2Session(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	binding, err := s.userRepo.GetWechatByOpenID(session.OpenID)
+	if err != nil {
+		return nil, fmt.Errorf("获取微信绑定失败: %w", err)
+	}
+	if binding != nil && binding.UserID != userID {
+		return nil, fmt.Errorf("该微信已绑定其他账号")
+	}
+	if binding == nil {
+		// 用户已绑定其他微信时更换为当前微信，保留原绑定时间
+		binding, err = s.userRepo.GetWechatByUserID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("获取微信绑定失败: %w", err)
+		}
+	}
+
+	if err := s.saveWechat(userID, session, binding); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetWechatByUserID(userID)
+}
+
+// UnbindWechat 解除微信绑定
+func (s *userService) UnbindWechat(userID string) error {
+	deleted, err := s.userRepo.DeleteWechat(userID)
+	if err != nil {
+		return fmt.Errorf("解除微信绑定失败: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("未绑定微信")
+	}
+	return nil
+}
+
+// GetWechatBinding 获取微信绑定
+func (s *userService) GetWechatBinding(userID string) (*WechatBinding, error) {
+	binding, err := s.userRepo.GetWechatByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取微信绑定失败: %w", err)
+	}
+	return binding, nil
+}
+
+// saveWechat 保存绑定并更新 session_key，previous 为已有的绑定
+func (s *userService) saveWechat(userID string, session *wechatSession, previous *WechatBinding) error {
+	now := time.Now()
+	binding := &WechatBinding{
+		UserID:     userID,
+		OpenID:     session.OpenID,
+		UnionID:    session.UnionID,
+		SessionKey: session.SessionKey,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if previous != nil {
+		binding.CreatedAt = previous.CreatedAt
+	}
+	if err := s.userRepo.SaveWechat(binding); err != nil {
+		return fmt.Errorf("保存微信绑定失败: %w", err)
+	}
+	return nil
+}
+
+// createWechatUser 为首次登录的微信创建账号，用户名由 openid 生成，密码随机（只能通过微信登录）
+func (s *userService) createWechatUser(openID, nickname string) (*User, error) {
+	sum := sha256.Sum256([]byte(openID))
+	digest := hex.EncodeToString(sum[:])
+	username := "wx_" + digest[:12]
+	exists, err := s.userRepo.ExistsByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("检查用户名失败: %w", err)
+	}
+	if exists {
+		username = "wx_" + digest[:28]
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("生成密码失败: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	if nickname == "" {
+		nickname = "微信用户"
+	}
+	user := &User{
+		ID:           utils.GenerateID(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Nickname:     nickname,
+		Status:       StatusEnabled,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.createUser(user); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("微信用户注册成功，ID: %s, 用户名: %s", user.ID, user.Username)
+	return user, nil
+}
+
+// issueToken 为用户签发 JWT
+func (s *userService) issueToken(user *User) (*LoginResponse, error) {
+	if user.Status != StatusEnabled {
+		return nil, fmt.Errorf("账号已被禁用")
+	}
+
+	cfg := config.Load()
+	ttl := time.Duration(cfg.JWTExpireMinutes) * time.Minute
+	token, err := utils.GenerateJWT(user.ID, user.Username, cfg.JWTSecret, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("生成token失败: %w", err)
+	}
+
+	utils.LogInfo("用户登录成功，ID: %s, 用户名: %s", user.ID, user.Username)
+	return &LoginResponse{
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+		User:      user,
+	}, nil
+}
+
+// GetUser 获取用户信息
+func (s *userService) GetUser(id string) (*User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户失败: %w", err)
+	}
+	return user, nil
+}
+
+// GetSettings 获取用户设置
+func (s *userService) GetSettings(userID string) (*UserSettings, error) {
+	settings, err := s.userRepo.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户设置失败: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings 更新用户设置
+func (s *userService) UpdateSettings(userID string, req *SettingsUpdateRequest) (*UserSettings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AccountEquity != nil {
+		settings.AccountEquity = *req.AccountEquity
+	}
+	if req.MaxRiskPct != nil {
+		settings.MaxRiskPct = *req.MaxRiskPct
+	}
+	if req.AutoSizePlans != nil {
+		settings.AutoSizePlans = *req.AutoSizePlans
+	}
+	if req.BaseCurrency != nil {
+		settings.BaseCurrency = strings.ToUpper(*req.BaseCurrency)
+	}
+	if req.MaxPositionValue != nil {
+		settings.MaxPositionValue = *req.MaxPositionValue
+	}
+	if req.MaxTotalExposure != nil {
+		settings.MaxTotalExposure = *req.MaxTotalExposure
+	}
+	if req.MaxOpenPositions != nil {
+		settings.MaxOpenPositions = *req.MaxOpenPositions
+	}
+	if req.MaxDailyLoss != nil {
+		settings.MaxDailyLoss = *req.MaxDailyLoss
+	}
+	if req.RiskLimitPolicy != nil {
+		settings.RiskLimitPolicy = *req.RiskLimitPolicy
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := s.userRepo.SaveSettings(settings); err != nil {
+		return nil, fmt.Errorf("保存用户设置失败: %w", err)
+	}
+	return settings, nil
+}
@@ -0,0 +1,197 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"server/storage"
+)
+
+// UserRepository 用户数据访问层
+type UserRepository struct{}
+
+// NewUserRepository 创建用户仓库
+func NewUserRepository() *UserRepository {
+	return &UserRepository{}
+}
+
+// Create 创建用户
+func (r *UserRepository) Create(user *User) error {
+	query := `INSERT INTO users (id, username, email, password_hash, nickname, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := storage.GetDB().Exec(query,
+		user.ID, user.Username, user.Email, user.PasswordHash, user.Nickname,
+		user.Status, user.CreatedAt, user.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByID 根据ID获取用户
+func (r *UserRepository) GetByID(id string) (*User, error) {
+	return r.getOne("id = ?", id)
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *UserRepository) GetByUsername(username string) (*User, error) {
+	return r.getOne("username = ?", username)
+}
+
+// ExistsByUsername 检查用户名是否已存在
+func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
+	var count int
+	err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", username).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Count 统计用户数量
+func (r *UserRepository) Count() (int, error) {
+	var count int
+	if err := storage.GetDB().QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ClaimOrphanData 将没有归属用户的历史数据划归给指定用户
+func (r *UserRepository) ClaimOrphanData(userID string) error {
+	for _, table := range []string{"stocks", "plans", "logs", "reviews"} {
+		query := fmt.Sprintf("UPDATE %s SET user_id = ? WHERE user_id IS NULL OR user_id = ''", table)
+		if _, err := storage.GetDB().Exec(query, userID); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// GetSettings 获取用户设置，没有保存过时返回默认值
+func (r *UserRepository) GetSettings(userID string) (*UserSettings, error) {
+	settings := &UserSettings{
+		UserID:          userID,
+		MaxRiskPct:      DefaultMaxRiskPct,
+		BaseCurrency:    DefaultBaseCurrency,
+		RiskLimitPolicy: RiskPolicyWarn,
+	}
+	var baseCurrency, policy sql.NullString
+	err := storage.GetDB().QueryRow(`SELECT account_equity, max_risk_pct, auto_size_plans, base_currency,
+		max_position_value, max_total_exposure, max_open_positions, max_daily_loss, risk_limit_policy, updated_at
+		FROM user_settings WHERE user_id = ?`, userID).Scan(
+		&settings.AccountEquity, &settings.MaxRiskPct, &settings.AutoSizePlans, &baseCurrency,
+		&settings.MaxPositionValue, &settings.MaxTotalExposure, &settings.MaxOpenPositions, &settings.MaxDailyLoss,
+		&policy, &settings.UpdatedAt,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if baseCurrency.String != "" {
+		settings.BaseCurrency = baseCurrency.String
+	}
+	if policy.String != "" {
+		settings.RiskLimitPolicy = policy.String
+	}
+	return settings, nil
+}
+
+// SaveSettings 保存用户设置
+func (r *UserRepository) SaveSettings(settings *UserSettings) error {
+	query := `INSERT INTO user_settings (user_id, account_equity, max_risk_pct, auto_size_plans, base_currency,
+			max_position_value, max_total_exposure, max_open_positions, max_daily_loss, risk_limit_policy, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			account_equity = excluded.account_equity,
+			max_risk_pct = excluded.max_risk_pct,
+			auto_size_plans = excluded.auto_size_plans,
+			base_currency = excluded.base_currency,
+			max_position_value = excluded.max_position_value,
+			max_total_exposure = excluded.max_total_exposure,
+			max_open_positions = excluded.max_open_positions,
+			max_daily_loss = excluded.max_daily_loss,
+			risk_limit_policy = excluded.risk_limit_policy,
+			updated_at = excluded.updated_at`
+	_, err := storage.GetDB().Exec(query,
+		settings.UserID, settings.AccountEquity, settings.MaxRiskPct, settings.AutoSizePlans, settings.BaseCurrency,
+		settings.MaxPositionValue, settings.MaxTotalExposure, settings.MaxOpenPositions, settings.MaxDailyLoss,
+		settings.RiskLimitPolicy, settings.UpdatedAt,
+	)
+	return err
+}
+
+// GetWechatByOpenID 根据 openid 获取微信绑定，未绑定时返回 nil
+func (r *UserRepository) GetWechatByOpenID(openID string) (*WechatBinding, error) {
+	return r.getWechat("openid = ?", openID)
+}
+
+// GetWechatByUserID 获取用户的微信绑定，未绑定时返回 nil
+func (r *UserRepository) GetWechatByUserID(userID string) (*WechatBinding, error) {
+	return r.getWechat("user_id = ?", userID)
+}
+
+// SaveWechat 保存微信绑定，用户已绑定时更新
+func (r *UserRepository) SaveWechat(binding *WechatBinding) error {
+	_, err := storage.GetDB().Exec(`INSERT INTO user_wechat (user_id, openid, unionid, session_key, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			openid = excluded.openid,
+			unionid = excluded.unionid,
+			session_key = excluded.session_key,
+			updated_at = excluded.updated_at`,
+		binding.UserID, binding.OpenID, binding.UnionID, binding.SessionKey, binding.CreatedAt, binding.UpdatedAt,
+	)
+	return err
+}
+
+// DeleteWechat 解除微信绑定，返回是否存在
+func (r *UserRepository) DeleteWechat(userID string) (bool, error) {
+	result, err := storage.GetDB().Exec("DELETE FROM user_wechat WHERE user_id = ?", userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *UserRepository) getWechat(where string, args ...interface{}) (*WechatBinding, error) {
+	query := fmt.Sprintf(`SELECT user_id, openid, unionid, session_key, created_at, updated_at
+		FROM user_wechat WHERE %s`, where)
+
+	binding := &WechatBinding{}
+	var unionID, sessionKey sql.NullString
+	err := storage.GetDB().QueryRow(query, args...).Scan(
+		&binding.UserID, &binding.OpenID, &unionID, &sessionKey, &binding.CreatedAt, &binding.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	binding.UnionID = unionID.String
+	binding.SessionKey = sessionKey.String
+	return binding, nil
+}
+
+func (r *UserRepository) getOne(where string, args ...interface{}) (*User, error) {
+	query := fmt.Sprintf(`SELECT id, username, email, password_hash, nickname, status, created_at, updated_at
+		FROM users WHERE %s`, where)
+
+	user := &User{}
+	var email, nickname sql.NullString
+	err := storage.GetDB().QueryRow(query, args...).Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash, &nickname,
+		&user.Status, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("用户不存在")
+		}
+		return nil, err
+	}
+
+	user.Email = email.String
+	user.Nickname = nickname.String
+	return user, nil
+}
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"server/config"
+)
+
+// wechatSession code2session 接口返回
+type wechatSession struct {
+	OpenID     string `json:"openid"`
+	SessionKey string `json:"session_key"`
+	UnionID    string `json:"unionid"`
+	ErrCode    int    `json:"errcode"`
+	ErrMsg     string `json:"errmsg"`
+}
+
+// wechatClient 请求微信接口的 HTTP 客户端
+var wechatClient = &http.Client{Timeout: 10 * time.Second}
+
+// code2Session 用小程序 wx.login 得到的 code 换取 openid 和 session_key
+func code2Session(ctx context.Context, code string) (*wechatSession, error) {
+	cfg := config.GetWechatConfig()
+	if cfg.AppID == "" || cfg.AppSecret == "" {
+		return nil, fmt.Errorf("未配置微信小程序")
+	}
+
+	query := url.Values{}
+	query.Set("appid", cfg.AppID)
+	query.Set("secret", cfg.AppSecret)
+	query.Set("js_code", code)
+	query.Set("grant_type", "authorization_code")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.APIBaseURL+"/sns/jscode2session?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := wechatClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求微信登录接口失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("微信登录接口返回 %d", resp.StatusCode)
+	}
+
+	var session wechatSession
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return nil, fmt.Errorf("解析微信登录返回失败: %w", err)
+	}
+	switch {
+	case session.ErrCode == 40029 || session.ErrCode == 40163:
+		return nil, fmt.Errorf("登录凭证无效或已使用，请重新登录")
+	case session.ErrCode != 0:
+		return nil, fmt.Errorf("微信登录失败: %d %s", session.ErrCode, session.ErrMsg)
+	case session.OpenID == "":
+		return nil, fmt.Errorf("微信登录失败: 未返回 openid")
+	}
+	return &session, nil
+}
@@ -32,12 +32,18 @@ func SetupRouter() *gin.Engine {
 
 	// API路由组 - 应用认证中间件
 	api := r.Group("/api")
+	api.Use(middleware.AuthMiddleware())
 
 	{
+		// 注册公开路由（登录、注册）
+		modules.RegisterPublicRoutes(api)
+
+		// 以下路由均需登录
+		authed := api.Group("", middleware.RequireAuth())
 		// 注册所有模块路由
-		modules.RegisterAllRoutes(api)
+		modules.RegisterAllRoutes(authed)
 		// 注册其他路由（如上传等）
-		handler.RegisterUploadRoutes(api)
+		handler.RegisterUploadRoutes(authed)
 	}
 
 	return r
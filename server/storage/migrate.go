@@ -0,0 +1,336 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/utils"
+)
+
+// migrationFS 内嵌的 SQL 迁移文件，命名格式为 <版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Migration 一个数据库迁移版本
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// MigrationStatus 迁移版本的执行状态
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// goMigrations 无法用纯 SQL 表达的迁移，与 SQL 文件按版本号统一排序
+var goMigrations = []Migration{
+	{
+		// 迁移系统之前创建的数据库缺少后来增加的列，新库在 0001 中已包含这些列
+		Version: 2,
+		Name:    "add_legacy_columns",
+		Up:      addLegacyColumns,
+		Down:    func(tx *sql.Tx) error { return nil },
+	},
+}
+
+// addLegacyColumns 为旧数据库补齐缺失的列
+func addLegacyColumns(tx *sql.Tx) error {
+	columns := []struct {
+		table, column, definition string
+	}{
+		{"logs", "title", "TEXT"},
+		{"logs", "status", "TEXT DEFAULT 'pending'"},
+		{"logs", "lot_remaining", "INTEGER"},
+		{"stocks", "user_id", "TEXT"},
+		{"plans", "user_id", "TEXT"},
+		{"logs", "user_id", "TEXT"},
+		{"reviews", "user_id", "TEXT"},
+	}
+	for _, col := range columns {
+		if err := addColumnIfNotExists(tx, col.table, col.column, col.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfNotExists 检查列是否存在，如果不存在则添加
+func addColumnIfNotExists(tx *sql.Tx, tableName, columnName, definition string) error {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, tableName, columnName).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check column exists: %w", err)
+	}
+	if count > 0 {
+		utils.LogDebug("列 %s.%s 已存在，跳过", tableName, columnName)
+		return nil
+	}
+
+	utils.LogInfo("表 %s 中缺少列 %s，正在添加...", tableName, columnName)
+	alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", tableName, columnName, definition)
+	if _, err := tx.Exec(alterStmt); err != nil {
+		return fmt.Errorf("add column %s.%s: %w", tableName, columnName, err)
+	}
+	return nil
+}
+
+// execSQL 将 SQL 文本包装为迁移函数
+func execSQL(stmt string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if strings.TrimSpace(stmt) == "" {
+			return nil
+		}
+		_, err := tx.Exec(stmt)
+		return err
+	}
+}
+
+// LoadMigrations 加载内嵌 SQL 迁移和 Go 迁移，按版本号升序返回
+func LoadMigrations() ([]Migration, error) {
+	byVersion := map[int]*Migration{}
+
+	files, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	for _, file := range files {
+		base := path.Base(file)
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name: %s", base)
+		}
+		stem := strings.TrimSuffix(base, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(stem, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", base)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", base)
+		}
+
+		content, err := migrationFS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", base, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("duplicate migration version %d: %s / %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = execSQL(string(content))
+		} else {
+			m.Down = execSQL(string(content))
+		}
+	}
+
+	for i := range goMigrations {
+		gm := goMigrations[i]
+		if _, ok := byVersion[gm.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s", gm.Version, gm.Name)
+		}
+		byVersion[gm.Version] = &gm
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up step", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// ensureMigrationTable 创建迁移记录表
+func (d *DB) ensureMigrationTable() error {
+	_, err := d.SQL.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INTEGER PRIMARY KEY,
+            name TEXT NOT NULL,
+            applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        );`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions 查询已执行的迁移版本
+func (d *DB) appliedVersions() (map[int]time.Time, error) {
+	rows, err := d.SQL.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// runInTx 在事务中执行一次迁移并更新迁移记录
+func (d *DB) runInTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.SQL.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Migrate 执行所有未执行的迁移
+func (d *DB) Migrate() error {
+	return d.MigrateUp(0)
+}
+
+// MigrateUp 按版本顺序执行未执行的迁移，steps 为 0 时执行全部
+func (d *DB) MigrateUp(steps int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if err := d.ensureMigrationTable(); err != nil {
+		return err
+	}
+	applied, err := d.appliedVersions()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if steps > 0 && count >= steps {
+			break
+		}
+
+		utils.LogInfo("正在执行迁移: %04d_%s", m.Version, m.Name)
+		err := d.runInTx(func(tx *sql.Tx) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.Version, m.Name, time.Now())
+			return err
+		})
+		if err != nil {
+			utils.LogError("迁移 %04d_%s 失败: %v", m.Version, m.Name, err)
+			return fmt.Errorf("migrate up %04d_%s: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		utils.LogInfo("数据库已是最新版本")
+	} else {
+		utils.LogInfo("共执行 %d 个迁移", count)
+	}
+	return nil
+}
+
+// MigrateDown 按版本倒序回滚已执行的迁移，steps 为 0 时回滚全部
+func (d *DB) MigrateDown(steps int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if err := d.ensureMigrationTable(); err != nil {
+		return err
+	}
+	applied, err := d.appliedVersions()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if steps > 0 && count >= steps {
+			break
+		}
+		if m.Down == nil {
+			return fmt.Errorf("migration %04d_%s has no down step", m.Version, m.Name)
+		}
+
+		utils.LogInfo("正在回滚迁移: %04d_%s", m.Version, m.Name)
+		err := d.runInTx(func(tx *sql.Tx) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+			return err
+		})
+		if err != nil {
+			utils.LogError("回滚迁移 %04d_%s 失败: %v", m.Version, m.Name, err)
+			return fmt.Errorf("migrate down %04d_%s: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+
+	utils.LogInfo("共回滚 %d 个迁移", count)
+	return nil
+}
+
+// MigrationStatus 获取所有迁移的执行状态
+func (d *DB) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.ensureMigrationTable(); err != nil {
+		return nil, err
+	}
+	applied, err := d.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if t, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &t
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
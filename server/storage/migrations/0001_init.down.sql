@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS logs;
+DROP TABLE IF EXISTS plans;
+DROP TABLE IF EXISTS stocks;
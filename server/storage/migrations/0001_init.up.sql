@@ -0,0 +1,81 @@
+CREATE TABLE IF NOT EXISTS stocks (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    region TEXT,
+    currency TEXT,
+    category TEXT,
+    enabled INTEGER DEFAULT 1,
+    remark TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS plans (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    name TEXT NOT NULL,
+    type TEXT,
+    stock_code TEXT,
+    stock_name TEXT,
+    strategy TEXT,
+    trading_strategy TEXT,
+    target_price REAL,
+    quantity INTEGER,
+    stop_loss REAL,
+    take_profit REAL,
+    start_time TEXT,
+    end_time TEXT,
+    risk_level TEXT,
+    description TEXT,
+    remark TEXT,
+    status TEXT DEFAULT 'active',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS logs (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    title TEXT,
+    plan_name TEXT,
+    stock_code TEXT NOT NULL,
+    stock_name TEXT,
+    type TEXT NOT NULL,
+    trading_time TEXT NOT NULL,
+    price REAL NOT NULL,
+    quantity INTEGER NOT NULL,
+    strategy TEXT,
+    remark TEXT,
+    status TEXT DEFAULT 'pending',
+    lot_remaining INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    period TEXT NOT NULL,
+    review_date TEXT NOT NULL,
+    title TEXT NOT NULL,
+    buy_count INTEGER DEFAULT 0,
+    sell_count INTEGER DEFAULT 0,
+    total_profit REAL DEFAULT 0,
+    summary TEXT NOT NULL,
+    improvements TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    email TEXT,
+    password_hash TEXT NOT NULL,
+    nickname TEXT,
+    status INTEGER DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
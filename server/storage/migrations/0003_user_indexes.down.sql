@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reviews_user_id;
+DROP INDEX IF EXISTS idx_logs_user_id;
+DROP INDEX IF EXISTS idx_plans_user_id;
+DROP INDEX IF EXISTS idx_stocks_user_id;
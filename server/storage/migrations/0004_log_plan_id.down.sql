@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_logs_plan_id;
+
+ALTER TABLE logs DROP COLUMN plan_id;
@@ -0,0 +1,11 @@
+ALTER TABLE logs ADD COLUMN plan_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_logs_plan_id ON logs(plan_id);
+
+-- 按计划名称回填已有日志的计划ID
+UPDATE logs SET plan_id = (
+    SELECT p.id FROM plans p
+    WHERE p.name = logs.plan_name AND p.user_id IS logs.user_id
+    ORDER BY p.created_at LIMIT 1
+)
+WHERE plan_id IS NULL AND plan_name IS NOT NULL AND plan_name != '';
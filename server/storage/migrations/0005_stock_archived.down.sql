@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_stocks_user_code;
+
+ALTER TABLE stocks DROP COLUMN archived_at;
@@ -0,0 +1,3 @@
+ALTER TABLE stocks ADD COLUMN archived_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_stocks_user_code ON stocks(user_id, code);
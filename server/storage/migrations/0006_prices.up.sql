@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS prices (
+    stock_code TEXT NOT NULL,
+    interval TEXT NOT NULL DEFAULT '1d',
+    trade_time TEXT NOT NULL,
+    open REAL NOT NULL,
+    high REAL NOT NULL,
+    low REAL NOT NULL,
+    close REAL NOT NULL,
+    volume REAL DEFAULT 0,
+    amount REAL DEFAULT 0,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stock_code, interval, trade_time)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_strategies_kind;
+DROP TABLE IF EXISTS strategies;
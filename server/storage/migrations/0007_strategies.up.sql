@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS strategies (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT,
+    description TEXT,
+    detail TEXT,
+    risk_level TEXT NOT NULL DEFAULT 'medium',
+    parameters TEXT,
+    win_rate TEXT,
+    suitable_for TEXT,
+    pros TEXT,
+    cons TEXT,
+    enabled BOOLEAN DEFAULT 1,
+    sort_order INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_strategies_kind ON strategies(kind, sort_order);
+
+-- 选股策略，来自 web/src/utils/strategyConfig.js
+INSERT OR IGNORE INTO strategies (id, kind, name, category, description, detail, risk_level, parameters, win_rate, suitable_for, pros, cons, enabled, sort_order) VALUES
+('trend_following', 'selection', '趋势跟踪', 'technical', '跟随市场趋势选择股票', '通过分析价格趋势、成交量等指标，选择处于上升趋势的股票', 'medium',
+ '{"timeframe":"日线","indicators":["MA","MACD","RSI"]}', '60-70%', '趋势明显的市场环境', '[]', '[]', 1, 1),
+('breakout', 'selection', '突破策略', 'technical', '选择突破关键阻力位的股票', '寻找突破重要技术位、成交量放大的股票', 'high',
+ '{"timeframe":"日线","indicators":["支撑阻力位","成交量"]}', '55-65%', '震荡突破的市场环境', '[]', '[]', 1, 2),
+('reversal', 'selection', '反转策略', 'technical', '选择超跌反弹的股票', '寻找超卖后可能出现反弹的股票', 'high',
+ '{"timeframe":"日线","indicators":["RSI","KDJ","布林带"]}', '50-60%', '超跌反弹的市场环境', '[]', '[]', 1, 3),
+('bollinger_reversal', 'selection', '布林带反转', 'technical', '利用布林带上下轨进行反向操作', '在小时级别的布林上轨和下轨之间反向操作', 'medium',
+ '{"timeframe":"小时线","indicators":["布林带","RSI"]}', '60-75%', '震荡市场，高波动股票', '[]', '[]', 1, 4),
+('momentum', 'selection', '动量策略', 'technical', '选择近期表现强势的股票', '基于价格动量和相对强度选择股票', 'medium',
+ '{"timeframe":"日线","indicators":["ROC","RSI","MACD"]}', '55-70%', '强势上涨的市场环境', '[]', '[]', 1, 5);
+
+-- 交易策略，来自 web/src/utils/tradingStrategyConfig.js
+INSERT OR IGNORE INTO strategies (id, kind, name, category, description, detail, risk_level, parameters, win_rate, suitable_for, pros, cons, enabled, sort_order) VALUES
+('scalping', 'trading', '剥头皮', 'scalping', '超短线交易，快速进出', '利用极短时间内的价格波动进行交易，持仓时间通常为几分钟到几小时', 'high',
+ '{"holdingTime":"几分钟到几小时","frequency":"高频","capitalRequirement":"高资金要求","skillLevel":"需要专业交易技能"}', '50-60%', '专业交易员，有充足时间盯盘',
+ '["快速获利","资金周转快","风险相对可控"]', '["需要专业技能","手续费成本高","心理压力大"]', 1, 1),
+('daytrading', 'trading', '日内交易', 'day_trading', '当日开平仓，不过夜', '在同一个交易日内完成开仓和平仓，不持有过夜头寸', 'medium',
+ '{"holdingTime":"当日完成","frequency":"中频","capitalRequirement":"中等资金要求","skillLevel":"需要一定交易经验"}', '55-65%', '有时间的个人投资者，日内波动较大的市场',
+ '["避免隔夜风险","资金利用率高","适合上班族"]', '["需要盯盘时间","可能错过趋势机会","手续费成本"]', 1, 2),
+('swing', 'trading', '波段交易', 'swing', '短期趋势，持仓数日', '捕捉短期趋势，持仓时间通常为几天到几周', 'medium',
+ '{"holdingTime":"几天到几周","frequency":"低频","capitalRequirement":"中等资金要求","skillLevel":"需要技术分析能力"}', '60-70%', '有一定技术分析能力的投资者',
+ '["风险相对可控","适合上班族","技术分析友好"]', '["需要技术分析能力","可能错过短期机会","需要耐心"]', 1, 3),
+('position', 'trading', '持仓交易', 'position', '中长期持仓，价值投资', '基于基本面分析，长期持有优质股票', 'low',
+ '{"holdingTime":"几个月到几年","frequency":"极低频","capitalRequirement":"资金要求灵活","skillLevel":"需要基本面分析能力"}', '70-80%', '价值投资者，长期投资者',
+ '["风险较低","适合长期投资","手续费成本低"]', '["资金占用时间长","需要基本面分析","可能错过短期机会"]', 1, 4),
+('arbitrage', 'trading', '套利交易', 'arbitrage', '利用价差获利', '利用不同市场、不同时间或不同品种之间的价格差异进行无风险套利', 'low',
+ '{"holdingTime":"几分钟到几天","frequency":"中高频","capitalRequirement":"高资金要求","skillLevel":"需要专业套利技能"}', '80-90%', '专业投资者，机构投资者',
+ '["风险极低","收益稳定","专业性强"]', '["需要专业设备","资金要求高","机会有限"]', 1, 5);
@@ -0,0 +1,2 @@
+ALTER TABLE plans DROP COLUMN risk_flagged;
+ALTER TABLE plans DROP COLUMN reward_risk_ratio;
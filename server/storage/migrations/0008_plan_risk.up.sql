@@ -0,0 +1,2 @@
+ALTER TABLE plans ADD COLUMN reward_risk_ratio REAL DEFAULT 0;
+ALTER TABLE plans ADD COLUMN risk_flagged BOOLEAN DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE stocks DROP COLUMN lot_size;
+
+DROP TABLE IF EXISTS user_settings;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT PRIMARY KEY,
+    account_equity REAL DEFAULT 0,
+    max_risk_pct REAL DEFAULT 1,
+    auto_size_plans BOOLEAN DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE stocks ADD COLUMN lot_size INTEGER DEFAULT 0;
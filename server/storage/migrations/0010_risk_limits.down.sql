@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_risk_breaches_user_time;
+DROP TABLE IF EXISTS risk_breaches;
+
+ALTER TABLE logs DROP COLUMN risk_warning;
+
+ALTER TABLE user_settings DROP COLUMN risk_limit_policy;
+ALTER TABLE user_settings DROP COLUMN max_daily_loss;
+ALTER TABLE user_settings DROP COLUMN max_open_positions;
+ALTER TABLE user_settings DROP COLUMN max_total_exposure;
+ALTER TABLE user_settings DROP COLUMN max_position_value;
@@ -0,0 +1,26 @@
+ALTER TABLE user_settings ADD COLUMN max_position_value REAL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN max_total_exposure REAL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN max_open_positions INTEGER DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN max_daily_loss REAL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN risk_limit_policy TEXT DEFAULT 'warn';
+
+ALTER TABLE logs ADD COLUMN risk_warning TEXT;
+
+CREATE TABLE IF NOT EXISTS risk_breaches (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    log_id TEXT,
+    stock_code TEXT,
+    type TEXT,
+    trading_time TEXT,
+    price REAL,
+    quantity INTEGER,
+    rule TEXT NOT NULL,
+    limit_value REAL,
+    actual_value REAL,
+    action TEXT NOT NULL,
+    message TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_breaches_user_time ON risk_breaches(user_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE logs DROP COLUMN fee_manual;
+ALTER TABLE logs DROP COLUMN fee_detail;
+ALTER TABLE logs DROP COLUMN fees;
+
+DROP TABLE IF EXISTS fee_schedules;
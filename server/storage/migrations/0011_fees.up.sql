@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id TEXT PRIMARY KEY,
+    region TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    commission_rate REAL DEFAULT 0,
+    min_commission REAL DEFAULT 0,
+    stamp_duty_rate REAL DEFAULT 0,
+    stamp_duty_side TEXT DEFAULT 'sell',
+    transfer_fee_rate REAL DEFAULT 0,
+    regulatory_fee_rate REAL DEFAULT 0,
+    sec_fee_rate REAL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (region, category)
+);
+
+-- 各地区默认费率，category 为空表示适用于该地区全部板块
+-- A股：佣金万2.5最低5元，印花税卖出0.05%，过户费0.001%，经手费和证管费合计0.00541%
+-- 港股：佣金0.03%最低3港元，印花税双边0.1%，交易征费、交易费、会财局征费和结算费合计0.0105%
+-- 美股：零佣金，卖出收取SEC费用0.00278%
+INSERT OR IGNORE INTO fee_schedules (id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side, transfer_fee_rate, regulatory_fee_rate, sec_fee_rate) VALUES
+('china_default', 'china', '', 'A股默认费率', 0.00025, 5, 0.0005, 'sell', 0.00001, 0.0000541, 0),
+('hongkong_default', 'hongkong', '', '港股默认费率', 0.0003, 3, 0.001, 'both', 0, 0.000105, 0),
+('usa_default', 'usa', '', '美股默认费率', 0, 0, 0, 'sell', 0, 0, 0.0000278);
+
+ALTER TABLE logs ADD COLUMN fees REAL DEFAULT 0;
+ALTER TABLE logs ADD COLUMN fee_detail TEXT;
+ALTER TABLE logs ADD COLUMN fee_manual BOOLEAN DEFAULT 0;
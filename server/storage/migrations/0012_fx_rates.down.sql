@@ -0,0 +1,3 @@
+ALTER TABLE user_settings DROP COLUMN base_currency;
+
+DROP TABLE IF EXISTS fx_rates;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    from_currency TEXT NOT NULL,
+    to_currency TEXT NOT NULL,
+    rate_date TEXT NOT NULL,
+    rate REAL NOT NULL,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (from_currency, to_currency, rate_date)
+);
+
+ALTER TABLE user_settings ADD COLUMN base_currency TEXT DEFAULT 'CNY';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS import_mappings;
+
+DROP INDEX IF EXISTS idx_logs_broker_trade;
+ALTER TABLE logs DROP COLUMN broker_trade_id;
+ALTER TABLE logs DROP COLUMN broker;
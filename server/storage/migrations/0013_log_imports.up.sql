@@ -0,0 +1,20 @@
+ALTER TABLE logs ADD COLUMN broker TEXT;
+ALTER TABLE logs ADD COLUMN broker_trade_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_logs_broker_trade ON logs(user_id, broker, broker_trade_id)
+    WHERE broker_trade_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS import_mappings (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    broker TEXT NOT NULL,
+    columns TEXT NOT NULL,
+    buy_values TEXT,
+    sell_values TEXT,
+    time_format TEXT,
+    header_row INTEGER DEFAULT 1,
+    sheet TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, broker)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_plan_alerts_user;
+DROP TABLE IF EXISTS plan_alerts;
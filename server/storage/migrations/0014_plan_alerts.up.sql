@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS plan_alerts (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    plan_id TEXT NOT NULL,
+    plan_name TEXT,
+    stock_code TEXT NOT NULL,
+    stock_name TEXT,
+    kind TEXT NOT NULL,
+    level REAL NOT NULL,
+    price REAL NOT NULL,
+    quote_time DATETIME,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plan_id, kind, level)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plan_alerts_user ON plan_alerts(user_id, created_at);
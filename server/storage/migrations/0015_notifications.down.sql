@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_outbox_user;
+DROP INDEX IF EXISTS idx_notification_outbox_due;
+DROP INDEX IF EXISTS idx_notification_outbox_dedupe;
+DROP TABLE IF EXISTS notification_outbox;
+DROP TABLE IF EXISTS notification_channels;
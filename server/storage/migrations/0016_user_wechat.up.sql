@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_wechat (
+    user_id TEXT PRIMARY KEY,
+    openid TEXT NOT NULL UNIQUE,
+    unionid TEXT,
+    session_key TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_strategies_user_id;
+
+ALTER TABLE strategies DROP COLUMN user_id;
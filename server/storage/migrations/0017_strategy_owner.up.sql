@@ -0,0 +1,4 @@
+-- user_id 为空的是内置策略，所有用户可见但不能修改；用户创建的策略只对自己可见
+ALTER TABLE strategies ADD COLUMN user_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_strategies_user_id ON strategies(user_id);
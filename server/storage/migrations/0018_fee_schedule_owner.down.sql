@@ -0,0 +1,26 @@
+-- 用户创建的费率表无法保留在全局唯一约束下，回滚时只保留内置费率表
+CREATE TABLE fee_schedules_old (
+    id TEXT PRIMARY KEY,
+    region TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    commission_rate REAL DEFAULT 0,
+    min_commission REAL DEFAULT 0,
+    stamp_duty_rate REAL DEFAULT 0,
+    stamp_duty_side TEXT DEFAULT 'sell',
+    transfer_fee_rate REAL DEFAULT 0,
+    regulatory_fee_rate REAL DEFAULT 0,
+    sec_fee_rate REAL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (region, category)
+);
+
+INSERT INTO fee_schedules_old (id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side,
+    transfer_fee_rate, regulatory_fee_rate, sec_fee_rate, created_at, updated_at)
+SELECT id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side,
+    transfer_fee_rate, regulatory_fee_rate, sec_fee_rate, created_at, updated_at
+FROM fee_schedules WHERE user_id IS NULL;
+
+DROP TABLE fee_schedules;
+ALTER TABLE fee_schedules_old RENAME TO fee_schedules;
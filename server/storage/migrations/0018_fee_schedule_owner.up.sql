@@ -0,0 +1,30 @@
+-- user_id 为空的是内置费率表，所有用户共用但不能修改；用户创建的费率表只对自己生效，优先于内置费率表
+-- SQLite 不能直接修改唯一约束，重建表把唯一约束改为按用户区分
+CREATE TABLE fee_schedules_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    region TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    commission_rate REAL DEFAULT 0,
+    min_commission REAL DEFAULT 0,
+    stamp_duty_rate REAL DEFAULT 0,
+    stamp_duty_side TEXT DEFAULT 'sell',
+    transfer_fee_rate REAL DEFAULT 0,
+    regulatory_fee_rate REAL DEFAULT 0,
+    sec_fee_rate REAL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, region, category)
+);
+
+INSERT INTO fee_schedules_new (id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side,
+    transfer_fee_rate, regulatory_fee_rate, sec_fee_rate, created_at, updated_at)
+SELECT id, region, category, name, commission_rate, min_commission, stamp_duty_rate, stamp_duty_side,
+    transfer_fee_rate, regulatory_fee_rate, sec_fee_rate, created_at, updated_at
+FROM fee_schedules;
+
+DROP TABLE fee_schedules;
+ALTER TABLE fee_schedules_new RENAME TO fee_schedules;
+
+CREATE INDEX IF NOT EXISTS idx_fee_schedules_match ON fee_schedules(region, category);
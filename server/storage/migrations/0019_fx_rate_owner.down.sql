@@ -0,0 +1,17 @@
+-- 用户录入的汇率无法保留在全局主键下，回滚时只保留公共汇率
+CREATE TABLE fx_rates_old (
+    from_currency TEXT NOT NULL,
+    to_currency TEXT NOT NULL,
+    rate_date TEXT NOT NULL,
+    rate REAL NOT NULL,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (from_currency, to_currency, rate_date)
+);
+
+INSERT INTO fx_rates_old (from_currency, to_currency, rate_date, rate, source, created_at, updated_at)
+SELECT from_currency, to_currency, rate_date, rate, source, created_at, updated_at FROM fx_rates WHERE user_id IS NULL;
+
+DROP TABLE fx_rates;
+ALTER TABLE fx_rates_old RENAME TO fx_rates;
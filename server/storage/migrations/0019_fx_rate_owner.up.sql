@@ -0,0 +1,21 @@
+-- user_id 为空的是迁移前录入的公共汇率，所有用户共用但不能修改；之后录入和导入的汇率只对录入的用户生效
+-- SQLite 不能直接修改主键，重建表把唯一约束改为按用户区分
+CREATE TABLE fx_rates_new (
+    user_id TEXT,
+    from_currency TEXT NOT NULL,
+    to_currency TEXT NOT NULL,
+    rate_date TEXT NOT NULL,
+    rate REAL NOT NULL,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, from_currency, to_currency, rate_date)
+);
+
+INSERT INTO fx_rates_new (from_currency, to_currency, rate_date, rate, source, created_at, updated_at)
+SELECT from_currency, to_currency, rate_date, rate, source, created_at, updated_at FROM fx_rates;
+
+DROP TABLE fx_rates;
+ALTER TABLE fx_rates_new RENAME TO fx_rates;
+
+CREATE INDEX IF NOT EXISTS idx_fx_rates_pair ON fx_rates(from_currency, to_currency, rate_date);
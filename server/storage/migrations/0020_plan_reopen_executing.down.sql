@@ -0,0 +1 @@
+-- 计划状态由关联日志推导，回滚时不恢复
@@ -0,0 +1,8 @@
+-- 开仓数量达到计划数量后曾直接标记为已完成，仍有持仓的计划恢复为执行中，继续监控止损止盈
+UPDATE plans SET status = 'executing', updated_at = CURRENT_TIMESTAMP
+WHERE status = 'completed' AND (
+    SELECT COALESCE(SUM(CASE WHEN l.type = (CASE WHEN plans.type = 'sell' THEN 'sell' ELSE 'buy' END)
+        THEN l.quantity ELSE -l.quantity END), 0)
+    FROM logs l
+    WHERE l.plan_id = plans.id AND l.user_id IS plans.user_id AND l.status = 'completed'
+) > 0;
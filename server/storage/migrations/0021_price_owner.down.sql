@@ -0,0 +1,23 @@
+-- 用户导入的K线无法保留在全局主键下，回滚时只保留公共K线
+CREATE TABLE prices_old (
+    stock_code TEXT NOT NULL,
+    interval TEXT NOT NULL DEFAULT '1d',
+    trade_time TEXT NOT NULL,
+    open REAL NOT NULL,
+    high REAL NOT NULL,
+    low REAL NOT NULL,
+    close REAL NOT NULL,
+    volume REAL DEFAULT 0,
+    amount REAL DEFAULT 0,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stock_code, interval, trade_time)
+);
+
+INSERT INTO prices_old (stock_code, interval, trade_time, open, high, low, close, volume, amount, source, created_at, updated_at)
+SELECT stock_code, interval, trade_time, open, high, low, close, volume, amount, source, created_at, updated_at
+FROM prices WHERE user_id IS NULL;
+
+DROP TABLE prices;
+ALTER TABLE prices_old RENAME TO prices;
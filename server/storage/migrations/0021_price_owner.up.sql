@@ -0,0 +1,26 @@
+-- user_id 为空的是迁移前导入的公共K线，所有用户共用但不能覆盖；之后导入的K线只对导入的用户生效
+-- SQLite 不能直接修改主键，重建表把唯一约束改为按用户区分
+CREATE TABLE prices_new (
+    user_id TEXT,
+    stock_code TEXT NOT NULL,
+    interval TEXT NOT NULL DEFAULT '1d',
+    trade_time TEXT NOT NULL,
+    open REAL NOT NULL,
+    high REAL NOT NULL,
+    low REAL NOT NULL,
+    close REAL NOT NULL,
+    volume REAL DEFAULT 0,
+    amount REAL DEFAULT 0,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, stock_code, interval, trade_time)
+);
+
+INSERT INTO prices_new (stock_code, interval, trade_time, open, high, low, close, volume, amount, source, created_at, updated_at)
+SELECT stock_code, interval, trade_time, open, high, low, close, volume, amount, source, created_at, updated_at FROM prices;
+
+DROP TABLE prices;
+ALTER TABLE prices_new RENAME TO prices;
+
+CREATE INDEX IF NOT EXISTS idx_prices_bar ON prices(stock_code, interval, trade_time);
@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导出格式
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+)
+
+// csvFlushRows CSV 每写入多少行刷新一次输出
+const csvFlushRows = 200
+
+// RowWriter 逐行写出表格数据，写完后必须调用 Close
+// 单元格支持 string、int、float64、bool 和 time.Time，其他类型按 fmt 格式化为文本
+type RowWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// ParseExportFormat 校验导出格式，为空时为 csv
+func ParseExportFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", ExportCSV:
+		return ExportCSV, nil
+	case ExportXLSX:
+		return ExportXLSX, nil
+	}
+	return "", fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// ExportContentType 导出格式对应的 Content-Type
+func ExportContentType(format string) string {
+	if format == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewRowWriter 创建指定格式的表格写出器，sheet 为 xlsx 工作表名称
+func NewRowWriter(w io.Writer, format, sheet string) (RowWriter, error) {
+	switch format {
+	case ExportCSV:
+		return newCSVRowWriter(w)
+	case ExportXLSX:
+		return newXLSXRowWriter(w, sheet)
+	}
+	return nil, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// formatCell 将单元格转换为文本
+func formatCell(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		if value {
+			return "是"
+		}
+		return "否"
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(v)
+}
+
+// csvRowWriter CSV 写出器，带 UTF-8 BOM 以便 Excel 正确识别中文
+type csvRowWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVRowWriter(w io.Writer) (*csvRowWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatCell(v)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%csvFlushRows == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxRowWriter 流式 xlsx 写出器，只包含一个工作表，字符串使用内联字符串，不需要在内存中保留整张表
+type xlsxRowWriter struct {
+	zw   *zip.Writer
+	buf  *bufio.Writer
+	rows int
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxWorkbookFormat = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+func newXLSXRowWriter(w io.Writer, sheet string) (*xlsxRowWriter, error) {
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheet)); err != nil {
+		return nil, err
+	}
+
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbookFormat, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// 工作表放在最后，逐行写入压缩流
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(f)
+	if _, err := buf.WriteString(xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+	return &xlsxRowWriter{zw: zw, buf: buf}, nil
+}
+
+func (x *xlsxRowWriter) WriteRow(values []interface{}) error {
+	x.rows++
+	fmt.Fprintf(x.buf, `<row r="%d">`, x.rows)
+	for i, v := range values {
+		ref := xlsxColumnName(i) + strconv.Itoa(x.rows)
+		switch value := v.(type) {
+		case nil:
+			continue
+		case int, float64:
+			fmt.Fprintf(x.buf, `<c r="%s"><v>%s</v></c>`, ref, formatCell(value))
+		default:
+			fmt.Fprintf(x.buf, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(x.buf, []byte(formatCell(value))); err != nil {
+				return err
+			}
+			x.buf.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.buf.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxRowWriter) Close() error {
+	if _, err := x.buf.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+	if err := x.buf.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+// xlsxColumnName 将从 0 开始的列序号转换为列名（如 0 -> A，27 -> AB）
+func xlsxColumnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
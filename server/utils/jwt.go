@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// JWTClaims JWT 载荷
+type JWTClaims struct {
+	Subject   string `json:"sub"` // 用户ID
+	Username  string `json:"username"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var (
+	// ErrTokenInvalid token 格式或签名错误
+	ErrTokenInvalid = errors.New("token无效")
+	// ErrTokenExpired token 已过期
+	ErrTokenExpired = errors.New("token已过期")
+)
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// GenerateJWT 生成 HS256 签名的 JWT
+func GenerateJWT(userID, username, secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		Subject:   userID,
+		Username:  username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + signJWT(signingInput, secret), nil
+}
+
+// ParseJWT 校验签名和有效期，返回载荷
+func ParseJWT(token, secret string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrTokenInvalid
+	}
+
+	expected := signJWT(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrTokenInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return nil, ErrTokenInvalid
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+func signJWT(signingInput, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// xlsx 文件结构（仅读取需要的部分）
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T  string `xml:"t"`
+	Rs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) text() string {
+	if len(t.Rs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Rs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"` // 行号，从 1 开始
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取 xlsx 文件中的一个工作表，返回按行排列的单元格文本，空行返回空切片
+// sheet 为空时读取第一个工作表；日期单元格返回 Excel 序列号，由调用方按需转换
+func ReadXLSX(filePath, sheet string) ([][]string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 xlsx 文件失败: %w", err)
+	}
+	defer zr.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, fmt.Errorf("xlsx 文件没有工作表")
+	}
+
+	rid := workbook.Sheets[0].RID
+	if sheet != "" {
+		rid = ""
+		for _, s := range workbook.Sheets {
+			if s.Name == sheet {
+				rid = s.RID
+				break
+			}
+		}
+		if rid == "" {
+			return nil, fmt.Errorf("工作表 %s 不存在", sheet)
+		}
+	}
+	target := ""
+	for _, rel := range rels.Items {
+		if rel.ID == rid {
+			target = rel.Target
+			break
+		}
+	}
+	if target == "" {
+		return nil, fmt.Errorf("找不到工作表文件")
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+	var data xlsxSheet
+	if err := decodeZipXML(files, target, &data); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(data.Rows))
+	for _, row := range data.Rows {
+		// 空行不会写入文件，按行号补齐，保证返回的行号与表格一致
+		for row.Ref > 0 && len(rows) < row.Ref-1 {
+			rows = append(rows, []string{})
+		}
+		record := []string{}
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				col = xlsxColumnIndex(cell.Ref)
+			}
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err == nil && idx >= 0 && idx < len(shared.Items) {
+					value = shared.Items[idx].text()
+				}
+			case "inlineStr":
+				value = cell.Inline.text()
+			default:
+				value = cell.Value
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			record[col] = value
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// decodeZipXML 解析压缩包中的 XML 文件
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("xlsx 文件缺少 %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 256<<20)).Decode(v); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex 将单元格引用（如 AB12）转换为从 0 开始的列序号
+func xlsxColumnIndex(ref string) int {
+	col := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+	}
+	return col - 1
+}